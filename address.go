@@ -0,0 +1,92 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import "net"
+
+// afInet6 is the address family written in IPv6 addresses, AF_INET6 as defined on Windows, which RakNet uses.
+const afInet6 = 23
+
+// addressSize returns the size of the IP address and port in the RakNet format.
+func addressSize(ip net.IP) int {
+	if ip.To4() != nil {
+		return 7
+	}
+	return 29
+}
+
+// PutAddress writes an IP address and port in the RakNet format: the IP version byte, followed by either
+// the inverted bytes of an IPv4 address and the big endian port, or a sockaddr_in6 structure for IPv6 addresses.
+// If the IP is invalid, ErrInvalidAddress is set on the stream.
+func (stream *Stream) PutAddress(ip net.IP, port uint16) {
+	if !stream.writable("PutAddress", addressSize(ip)) {
+		return
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		stream.PutByte(4)
+		for _, b := range ip4 {
+			stream.PutByte(^b)
+		}
+		WriteUnsignedShort(&stream.Buffer, port)
+		return
+	}
+	if len(ip) != net.IPv6len {
+		stream.SetError(&StreamError{"PutAddress", len(stream.Buffer), ErrInvalidAddress})
+		return
+	}
+	stream.PutByte(6)
+	WriteLittleUnsignedShort(&stream.Buffer, afInet6)
+	WriteUnsignedShort(&stream.Buffer, port)
+	WriteUnsignedInt(&stream.Buffer, 0)
+	stream.PutBytes(ip)
+	WriteUnsignedInt(&stream.Buffer, 0)
+}
+
+// GetAddress reads an IP address and port in the RakNet format written by PutAddress.
+// If the IP version is unknown, ErrInvalidAddress is set on the stream.
+func (stream *Stream) GetAddress() (net.IP, uint16) {
+	offset := stream.Offset
+	switch stream.GetByte() {
+	case 4:
+		if !stream.check("GetAddress", 6) {
+			return nil, 0
+		}
+		ip := make(net.IP, net.IPv4len)
+		for i, b := range Read(&stream.Buffer, &stream.Offset, net.IPv4len) {
+			ip[i] = ^b
+		}
+		return ip, ReadUnsignedShort(&stream.Buffer, &stream.Offset)
+	case 6:
+		if !stream.check("GetAddress", 28) {
+			return nil, 0
+		}
+		stream.Offset += 2
+		port := ReadUnsignedShort(&stream.Buffer, &stream.Offset)
+		stream.Offset += 4
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, Read(&stream.Buffer, &stream.Offset, net.IPv6len))
+		stream.Offset += 4
+		return ip, port
+	}
+	if stream.err == nil {
+		stream.SetError(&StreamError{"GetAddress", offset, ErrInvalidAddress})
+	}
+	return nil, 0
+}
+
+// PutUDPAddress writes a UDP address with PutAddress.
+func (stream *Stream) PutUDPAddress(addr *net.UDPAddr) {
+	if !stream.writable("PutUDPAddress", 0) {
+		return
+	}
+	stream.PutAddress(addr.IP, uint16(addr.Port))
+}
+
+// GetUDPAddress reads a UDP address with GetAddress. It returns nil if the address could not be read.
+func (stream *Stream) GetUDPAddress() *net.UDPAddr {
+	ip, port := stream.GetAddress()
+	if ip == nil {
+		return nil
+	}
+	return &net.UDPAddr{IP: ip, Port: int(port)}
+}
@@ -0,0 +1,39 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"net"
+	"testing"
+)
+
+func TestAddress(t *testing.T) {
+	stream := NewStream()
+	stream.PutAddress(net.IPv4(127, 0, 0, 1), 19132)
+	assert.DeepEqual(t, stream.Buffer, b(4, 0x80, 0xff, 0xff, 0xfe, 0x4a, 0xbc))
+	stream.PutUDPAddress(&net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 19133})
+	assert.Equal(t, len(stream.Buffer), 7+29)
+	assert.DeepEqual(t, stream.Buffer[7:12], b(6, 23, 0, 0x4a, 0xbd))
+
+	ip, port := stream.GetAddress()
+	assert.Assert(t, ip.Equal(net.IPv4(127, 0, 0, 1)))
+	assert.Equal(t, port, uint16(19132))
+	addr := stream.GetUDPAddress()
+	assert.Equal(t, addr.String(), "[2001:db8::1]:19133")
+	assert.NilError(t, stream.Err())
+
+	stream = &Stream{Buffer: b(5, 0, 0)}
+	ip, _ = stream.GetAddress()
+	assert.Assert(t, ip == nil)
+	assert.Assert(t, errors.Is(stream.Err(), ErrInvalidAddress))
+
+	stream = &Stream{Buffer: b(4, 0, 0)}
+	stream.GetAddress()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream = NewStream()
+	stream.PutAddress(nil, 1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrInvalidAddress))
+}
@@ -0,0 +1,61 @@
+package binutils_test
+
+import (
+	"testing"
+
+	"github.com/irmine/binutils"
+	"github.com/irmine/binutils/binutilstest"
+)
+
+// TestHotPathAllocations guarantees that the fixed width and varint primitives, once the buffer has grown,
+// never allocate.
+func TestHotPathAllocations(t *testing.T) {
+	stream := binutils.NewStreamWithCapacity(256)
+	payload := []byte("payload")
+	binutilstest.AssertNoAllocs(t, func() {
+		stream.Reset()
+		stream.PutBool(true)
+		stream.PutByte(1)
+		stream.PutShort(-2)
+		stream.PutLittleUnsignedShort(2)
+		stream.PutInt(-3)
+		stream.PutLittleUnsignedInt(3)
+		stream.PutLong(-4)
+		stream.PutLittleUnsignedLong(4)
+		stream.PutFloat(1.5)
+		stream.PutLittleDouble(2.5)
+		stream.PutTriad(0x010203)
+		stream.PutInt24(-5)
+		stream.PutVarInt(-300)
+		stream.PutUnsignedVarLong(1 << 40)
+		stream.PutString("steve")
+		stream.PutLengthPrefixedBytes(payload)
+		stream.PutChecksummedBlock(binutils.ChecksumCRC32C, payload)
+		binutils.PutNumber(stream, uint16(6))
+	})
+	binutilstest.AssertNoAllocs(t, func() {
+		stream.Offset = 0
+		stream.GetBool()
+		stream.GetByte()
+		stream.GetShort()
+		stream.GetLittleUnsignedShort()
+		stream.GetInt()
+		stream.GetLittleUnsignedInt()
+		stream.GetLong()
+		stream.GetLittleUnsignedLong()
+		stream.GetFloat()
+		stream.GetLittleDouble()
+		stream.GetTriad()
+		stream.GetInt24()
+		stream.PeekVarInt()
+		stream.GetVarInt()
+		stream.GetUnsignedVarLong()
+		stream.Skip(6)
+		stream.Get(8)
+		stream.VerifyChecksummedBlock(binutils.ChecksumCRC32C)
+		binutils.GetNumber[uint16](stream)
+	})
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
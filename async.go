@@ -0,0 +1,215 @@
+package binutils
+
+import (
+	"io"
+	"sync"
+)
+
+// DefaultMaxBatchSize is the maximum amount of bytes an async writer coalesces into a single write by default.
+const DefaultMaxBatchSize = 64 << 10
+
+// OverflowPolicy decides what an AsyncWriter does with a frame written while its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the write until the queue has room, slowing down the producer.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the frame written.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest frame in the queue to make room for the frame written.
+	OverflowDropOldest
+)
+
+// Priority is the priority of a frame queued in an AsyncWriter. Frames of higher priority are written before
+// frames of lower priority queued earlier, so that control messages such as pings and acks are not held up
+// by bulk transfers. Frames of the same priority are written in order.
+type Priority int
+
+const (
+	// PriorityControl is the highest priority, for small control messages.
+	PriorityControl Priority = iota
+	// PriorityNormal is the priority of frames written by Write.
+	PriorityNormal
+	// PriorityBulk is the lowest priority, for large transfers.
+	PriorityBulk
+
+	// priorities is the amount of priorities.
+	priorities = iota
+)
+
+// AsyncWriter queues frames and writes them to a writer, such as a net.Conn, from its own goroutine,
+// so that producers are not held up by slow connections. Frames queued while a write is in progress are coalesced
+// into a single write, saving system calls for many small frames. The queue is bounded, and the overflow policy
+// decides what happens once it is full. Every priority has its own queue. It is safe for concurrent use.
+type AsyncWriter struct {
+	output    io.Writer
+	maxFrames int
+	maxBatch  int
+	policy    OverflowPolicy
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queues  [priorities][][]byte
+	queued  int
+	writing bool
+	closed  bool
+	dropped uint64
+	err     error
+	done    chan struct{}
+}
+
+// NewAsyncWriter returns a new async writer writing to the writer, queueing up to maxFrames frames per priority
+// with the overflow policy applied once a queue is full. Close must be called to stop it.
+func NewAsyncWriter(writer io.Writer, maxFrames int, policy OverflowPolicy) *AsyncWriter {
+	async := &AsyncWriter{output: writer, maxFrames: max(maxFrames, 1), maxBatch: DefaultMaxBatchSize, policy: policy, done: make(chan struct{})}
+	async.cond = sync.NewCond(&async.mu)
+	go async.run()
+	return async
+}
+
+// SetMaxBatchSize sets the maximum amount of bytes coalesced into a single write.
+// Frames larger than the maximum are still written, on their own.
+func (writer *AsyncWriter) SetMaxBatchSize(size int) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writer.maxBatch = size
+}
+
+// Write queues a copy of the frame with PriorityNormal. It returns ErrWriterClosed once the writer is closed,
+// and the error of a failed write to the underlying writer, after which all frames are discarded.
+// Frames dropped by the overflow policy are not reported as error, but counted by Dropped.
+func (writer *AsyncWriter) Write(frame []byte) (int, error) {
+	return writer.WritePriority(frame, PriorityNormal)
+}
+
+// WritePriority queues a copy of the frame with the priority, like Write.
+// Priorities out of range are clamped to the nearest priority.
+func (writer *AsyncWriter) WritePriority(frame []byte, priority Priority) (int, error) {
+	priority = min(max(priority, PriorityControl), PriorityBulk)
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	for writer.policy == OverflowBlock && len(writer.queues[priority]) >= writer.maxFrames && !writer.closed && writer.err == nil {
+		writer.cond.Wait()
+	}
+	queue := writer.queues[priority]
+	switch {
+	case writer.closed:
+		return 0, ErrWriterClosed
+	case writer.err != nil:
+		return 0, writer.err
+	case len(queue) >= writer.maxFrames && writer.policy == OverflowDropNewest:
+		writer.dropped++
+		return len(frame), nil
+	case len(queue) >= writer.maxFrames:
+		queue[0] = nil
+		queue = queue[1:]
+		writer.queued--
+		writer.dropped++
+	}
+	writer.queues[priority] = append(queue, append([]byte(nil), frame...))
+	writer.queued++
+	writer.cond.Broadcast()
+	return len(frame), nil
+}
+
+// Queued returns the amount of frames waiting to be written.
+func (writer *AsyncWriter) Queued() int {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.queued
+}
+
+// Dropped returns the amount of frames dropped by the overflow policy.
+func (writer *AsyncWriter) Dropped() uint64 {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.dropped
+}
+
+// Err returns the error of a failed write to the underlying writer, if any.
+func (writer *AsyncWriter) Err() error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.err
+}
+
+// Flush blocks until all frames queued are written, and returns the error of a failed write, if any.
+func (writer *AsyncWriter) Flush() error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	for (writer.queued > 0 || writer.writing) && writer.err == nil {
+		writer.cond.Wait()
+	}
+	return writer.err
+}
+
+// Close writes the frames still queued and stops the writer. The underlying writer is not closed.
+// It returns the error of a failed write, if any.
+func (writer *AsyncWriter) Close() error {
+	writer.mu.Lock()
+	if !writer.closed {
+		writer.closed = true
+		writer.cond.Broadcast()
+	}
+	writer.mu.Unlock()
+	<-writer.done
+	return writer.Err()
+}
+
+// run writes batches of queued frames, highest priority first, until the writer is closed and its queue drained, or a write fails.
+func (writer *AsyncWriter) run() {
+	defer close(writer.done)
+	var batch []byte
+	for {
+		writer.mu.Lock()
+		for writer.queued == 0 && !writer.closed {
+			writer.cond.Wait()
+		}
+		if writer.queued == 0 || writer.err != nil {
+			writer.mu.Unlock()
+			return
+		}
+		batch = writer.batch(batch[:0])
+		writer.writing = true
+		// Room was made in the queue for blocked writes.
+		writer.cond.Broadcast()
+		writer.mu.Unlock()
+
+		_, err := writer.output.Write(batch)
+
+		writer.mu.Lock()
+		writer.writing = false
+		if err != nil {
+			writer.err = err
+			writer.queues = [priorities][][]byte{}
+			writer.queued = 0
+		}
+		writer.cond.Broadcast()
+		writer.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// batch appends queued frames to the batch, highest priority first, up to the maximum batch size or at least one,
+// and removes them from their queues.
+func (writer *AsyncWriter) batch(batch []byte) []byte {
+	n := 0
+	for priority := range writer.queues {
+		queue := writer.queues[priority]
+		i := 0
+		for i < len(queue) && (n == 0 || len(batch)+len(queue[i]) <= writer.maxBatch) {
+			batch = append(batch, queue[i]...)
+			queue[i] = nil
+			i++
+			n++
+		}
+		writer.queues[priority] = queue[i:]
+		if i < len(queue) {
+			break
+		}
+	}
+	writer.queued -= n
+	return batch
+}
@@ -0,0 +1,122 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"sync"
+	"testing"
+)
+
+// gatedWriter records writes, blocking each until it is released.
+type gatedWriter struct {
+	mu      sync.Mutex
+	writes  []string
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 16), release: make(chan struct{}, 16)}
+}
+
+func (writer *gatedWriter) Write(p []byte) (int, error) {
+	writer.started <- struct{}{}
+	<-writer.release
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writer.writes = append(writer.writes, string(p))
+	return len(p), writer.err
+}
+
+func TestAsyncWriterCoalescing(t *testing.T) {
+	gated := newGatedWriter()
+	writer := NewAsyncWriter(gated, 16, OverflowBlock)
+	writer.Write([]byte("a"))
+	<-gated.started
+	// Frames queued while a write is in progress are written together.
+	writer.Write([]byte("b"))
+	writer.Write([]byte("c"))
+	assert.Equal(t, writer.Queued(), 2)
+	gated.release <- struct{}{}
+	gated.release <- struct{}{}
+	assert.NilError(t, writer.Flush())
+	assert.NilError(t, writer.Close())
+	assert.DeepEqual(t, gated.writes, []string{"a", "bc"})
+
+	_, err := writer.Write([]byte("d"))
+	assert.Equal(t, err, ErrWriterClosed)
+}
+
+func TestAsyncWriterOverflow(t *testing.T) {
+	for _, test := range []struct {
+		policy OverflowPolicy
+		writes []string
+	}{
+		{OverflowDropNewest, []string{"a", "b"}},
+		{OverflowDropOldest, []string{"a", "c"}},
+		{OverflowBlock, []string{"a", "b", "c"}},
+	} {
+		gated := newGatedWriter()
+		writer := NewAsyncWriter(gated, 1, test.policy)
+		writer.SetMaxBatchSize(1)
+		writer.Write([]byte("a"))
+		<-gated.started
+		writer.Write([]byte("b"))
+		written := make(chan struct{})
+		go func() {
+			writer.Write([]byte("c"))
+			close(written)
+		}()
+		if test.policy == OverflowBlock {
+			// The write blocks until the queue has room again.
+			gated.release <- struct{}{}
+			<-gated.started
+		}
+		<-written
+		for range 3 {
+			gated.release <- struct{}{}
+		}
+		assert.NilError(t, writer.Close())
+		assert.DeepEqual(t, gated.writes, test.writes)
+		assert.Equal(t, writer.Dropped(), uint64(3-len(test.writes)))
+	}
+}
+
+func TestAsyncWriterError(t *testing.T) {
+	gated := newGatedWriter()
+	gated.err = errors.New("connection reset")
+	gated.release <- struct{}{}
+	writer := NewAsyncWriter(gated, 16, OverflowBlock)
+	writer.Write([]byte("a"))
+	assert.Equal(t, writer.Flush(), gated.err)
+	_, err := writer.Write([]byte("b"))
+	assert.Equal(t, err, gated.err)
+	assert.Equal(t, writer.Close(), gated.err)
+}
+
+func TestAsyncWriterPriority(t *testing.T) {
+	for _, test := range []struct {
+		maxBatch int
+		writes   []string
+	}{
+		{DefaultMaxBatchSize, []string{"a", "dcb"}},
+		{1, []string{"a", "d", "c", "b"}},
+	} {
+		gated := newGatedWriter()
+		writer := NewAsyncWriter(gated, 1, OverflowBlock)
+		writer.SetMaxBatchSize(test.maxBatch)
+		writer.Write([]byte("a"))
+		<-gated.started
+		writer.WritePriority([]byte("b"), PriorityBulk)
+		writer.WritePriority([]byte("c"), PriorityNormal)
+		// The control frame is queued despite the full queues of other priorities.
+		writer.WritePriority([]byte("d"), PriorityControl)
+		assert.Equal(t, writer.Queued(), 3)
+		for range 4 {
+			gated.release <- struct{}{}
+		}
+		assert.NilError(t, writer.Close())
+		assert.DeepEqual(t, gated.writes, test.writes)
+	}
+}
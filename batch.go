@@ -0,0 +1,64 @@
+package binutils
+
+// PutBatch writes the packets as a Bedrock Edition batch: each packet prefixed with its length as unsigned varint,
+// concatenated and compressed with zlib. The batch takes up the rest of the packet, so any batch packet ID
+// is written beforehand. A compression error is set on the stream.
+func (stream *Stream) PutBatch(packets [][]byte) {
+	stream.PutBatchCompressed(packets, CompressionZlib)
+}
+
+// GetBatch reads the packets of a batch written by PutBatch from the rest of the stream.
+// The packets are not copied from the decompressed batch. A decompression error is set on the stream,
+// and the packets read before a malformed packet are returned with the error of the stream.
+func (stream *Stream) GetBatch() ([][]byte, error) {
+	return stream.GetBatchCompressed(CompressionZlib)
+}
+
+// PutBatchCompressed writes the packets as a batch like PutBatch, compressed with the algorithm,
+// such as CompressionDeflate used by newer versions of Bedrock Edition.
+func (stream *Stream) PutBatchCompressed(packets [][]byte, algo CompressionType) {
+	if !stream.writable("PutBatch", 0) {
+		return
+	}
+	batch := AcquireStream()
+	defer ReleaseStream(batch)
+	for _, packet := range packets {
+		batch.PutPrefix(PrefixUnsignedVarInt, len(packet))
+		batch.PutBytes(packet)
+	}
+	compressed, err := compress(algo, batch.Buffer)
+	if err != nil {
+		stream.SetError(&StreamError{"PutBatch", len(stream.Buffer), err})
+		return
+	}
+	stream.PutBytes(compressed)
+}
+
+// GetBatchCompressed reads the packets of a batch written by PutBatchCompressed with the algorithm.
+// Packets longer than the maximum string length of the stream are rejected with a *LengthError,
+// and batches decompressing to more than the maximum decompressed size of the stream with an error
+// matching ErrLimitExceeded.
+func (stream *Stream) GetBatchCompressed(algo CompressionType) ([][]byte, error) {
+	offset := stream.Offset
+	if !stream.check("GetBatch", 0) {
+		return nil, stream.err
+	}
+	data, err := stream.decompress(algo, stream.Buffer[stream.Offset:])
+	if err != nil {
+		stream.SetError(&StreamError{"GetBatch", offset, err})
+		return nil, stream.err
+	}
+	stream.Offset = len(stream.Buffer)
+
+	batch := stream.child(data)
+	var packets [][]byte
+	for batch.Remaining() > 0 {
+		packet := batch.getPrefixed("GetBatch", PrefixUnsignedVarInt, stream.maxStringLength)
+		if batch.err != nil {
+			stream.SetError(&StreamError{"GetBatch", offset, batch.err})
+			break
+		}
+		packets = append(packets, packet)
+	}
+	return packets, stream.err
+}
@@ -0,0 +1,63 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	packets := [][]byte{{0x01, 0x02}, {}, make([]byte, 300)}
+	for _, algo := range []CompressionType{CompressionZlib, CompressionDeflate} {
+		stream := NewStream()
+		stream.PutByte(0xfe)
+		stream.PutBatchCompressed(packets, algo)
+		assert.NilError(t, stream.Err())
+
+		assert.Equal(t, stream.GetByte(), byte(0xfe))
+		decoded, err := stream.GetBatchCompressed(algo)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, decoded, packets)
+		assert.Equal(t, stream.Remaining(), 0)
+	}
+
+	stream := NewStream()
+	stream.PutBatch(packets)
+	assert.DeepEqual(t, decompressed(t, stream.Buffer)[:4], []byte{0x02, 0x01, 0x02, 0x00})
+	stream.SetMaxStringLength(100)
+	decoded, err := stream.GetBatch()
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(err, &lengthErr))
+	assert.DeepEqual(t, decoded, packets[:2])
+
+	stream = &Stream{Buffer: []byte{0x78, 0x9c, 0x00}}
+	_, err = stream.GetBatch()
+	assert.ErrorContains(t, err, "GetBatch")
+}
+
+func TestBatchBomb(t *testing.T) {
+	// A megabyte of zeros compresses to about two kilobytes.
+	stream := NewStream()
+	stream.PutBytes(compressed(t, make([]byte, 1<<20)))
+	assert.Assert(t, len(stream.Buffer) < 4096)
+	stream.SetMaxDecompressedSize(1 << 16)
+	decoded, err := stream.GetBatch()
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Assert(t, decoded == nil)
+}
+
+// compressed returns the data compressed with zlib.
+func compressed(t *testing.T, data []byte) []byte {
+	t.Helper()
+	data, err := compress(CompressionZlib, data)
+	assert.NilError(t, err)
+	return data
+}
+
+// decompressed returns the data decompressed with zlib.
+func decompressed(t *testing.T, data []byte) []byte {
+	t.Helper()
+	data, err := decompress(CompressionZlib, data, DefaultMaxDecompressedSize)
+	assert.NilError(t, err)
+	return data
+}
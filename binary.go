@@ -1,8 +1,13 @@
 // Package binutils implements easy and simple reading and writing of byte arrays.
 // It writes and reads directly to and from byte arrays, rather than readers.
+//
+// Building with the binutils_minimal tag, which TinyGo builds imply, selects a minimal profile for embedded targets:
+// the reflection based API, such as Marshal, Registry, Schema and Enum, is left out, while streams and the wire format
+// are unchanged. Encode and Decode methods generated by cmd/binutils-gen work in both profiles.
 package binutils
 
 import (
+	"io"
 	"math"
 )
 
@@ -14,7 +19,11 @@ const (
 type EndianType byte
 
 // Read reads from buffer at the given offset with the given length.
+// It panics with ErrUnexpectedEOF if the buffer holds fewer than length bytes past offset.
 func Read(buffer *[]byte, offset *int, length int) []byte {
+	if length < 0 || *offset < 0 || *offset+length > len(*buffer) {
+		panic(ErrUnexpectedEOF)
+	}
 	var b = (*buffer)[*offset : *offset+length]
 	*offset += length
 	return b
@@ -57,11 +66,11 @@ func ReadUnsignedByte(buffer *[]byte, offset *int) byte {
 }
 
 func WriteShort(buffer *[]byte, signed int16) {
-	var b = make([]byte, 2)
+	var b [2]byte
 	var v = uint16(signed)
 	b[0] = byte(v >> 8)
 	b[1] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadShort(buffer *[]byte, offset *int) int16 {
@@ -70,10 +79,10 @@ func ReadShort(buffer *[]byte, offset *int) int16 {
 }
 
 func WriteUnsignedShort(buffer *[]byte, v uint16) {
-	var b = make([]byte, 2)
+	var b [2]byte
 	b[0] = byte(v >> 8)
 	b[1] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadUnsignedShort(buffer *[]byte, offset *int) uint16 {
@@ -82,13 +91,13 @@ func ReadUnsignedShort(buffer *[]byte, offset *int) uint16 {
 }
 
 func WriteInt(buffer *[]byte, int int32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	var v = uint32(int)
 	b[0] = byte(v >> 24)
 	b[1] = byte(v >> 16)
 	b[2] = byte(v >> 8)
 	b[3] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadInt(buffer *[]byte, offset *int) int32 {
@@ -97,12 +106,12 @@ func ReadInt(buffer *[]byte, offset *int) int32 {
 }
 
 func WriteUnsignedInt(buffer *[]byte, v uint32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	b[0] = byte(v >> 24)
 	b[1] = byte(v >> 16)
 	b[2] = byte(v >> 8)
 	b[3] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadUnsignedInt(buffer *[]byte, offset *int) uint32 {
@@ -111,7 +120,7 @@ func ReadUnsignedInt(buffer *[]byte, offset *int) uint32 {
 }
 
 func WriteLong(buffer *[]byte, long int64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	var v = uint64(long)
 	b[0] = byte(v >> 56)
 	b[1] = byte(v >> 48)
@@ -121,7 +130,7 @@ func WriteLong(buffer *[]byte, long int64) {
 	b[5] = byte(v >> 16)
 	b[6] = byte(v >> 8)
 	b[7] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLong(buffer *[]byte, offset *int) int64 {
@@ -131,7 +140,7 @@ func ReadLong(buffer *[]byte, offset *int) int64 {
 }
 
 func WriteUnsignedLong(buffer *[]byte, v uint64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	b[0] = byte(v >> 56)
 	b[1] = byte(v >> 48)
 	b[2] = byte(v >> 40)
@@ -140,7 +149,7 @@ func WriteUnsignedLong(buffer *[]byte, v uint64) {
 	b[5] = byte(v >> 16)
 	b[6] = byte(v >> 8)
 	b[7] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadUnsignedLong(buffer *[]byte, offset *int) uint64 {
@@ -150,13 +159,13 @@ func ReadUnsignedLong(buffer *[]byte, offset *int) uint64 {
 }
 
 func WriteFloat(buffer *[]byte, float float32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	var v = math.Float32bits(float)
 	b[0] = byte(v >> 24)
 	b[1] = byte(v >> 16)
 	b[2] = byte(v >> 8)
 	b[3] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadFloat(buffer *[]byte, offset *int) float32 {
@@ -167,7 +176,7 @@ func ReadFloat(buffer *[]byte, offset *int) float32 {
 }
 
 func WriteDouble(buffer *[]byte, double float64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	var v = math.Float64bits(double)
 	b[0] = byte(v >> 56)
 	b[1] = byte(v >> 48)
@@ -177,7 +186,7 @@ func WriteDouble(buffer *[]byte, double float64) {
 	b[5] = byte(v >> 16)
 	b[6] = byte(v >> 8)
 	b[7] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadDouble(buffer *[]byte, offset *int) float64 {
@@ -188,11 +197,11 @@ func ReadDouble(buffer *[]byte, offset *int) float64 {
 }
 
 func WriteLittleShort(buffer *[]byte, signed int16) {
-	var b = make([]byte, 2)
+	var b [2]byte
 	var v = uint16(signed)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleShort(buffer *[]byte, offset *int) int16 {
@@ -201,10 +210,10 @@ func ReadLittleShort(buffer *[]byte, offset *int) int16 {
 }
 
 func WriteLittleUnsignedShort(buffer *[]byte, v uint16) {
-	var b = make([]byte, 2)
+	var b [2]byte
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleUnsignedShort(buffer *[]byte, offset *int) uint16 {
@@ -213,13 +222,13 @@ func ReadLittleUnsignedShort(buffer *[]byte, offset *int) uint16 {
 }
 
 func WriteLittleInt(buffer *[]byte, int int32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	var v = uint32(int)
 	b[3] = byte(v >> 24)
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleInt(buffer *[]byte, offset *int) int32 {
@@ -228,12 +237,12 @@ func ReadLittleInt(buffer *[]byte, offset *int) int32 {
 }
 
 func WriteLittleUnsignedInt(buffer *[]byte, v uint32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	b[3] = byte(v >> 24)
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleUnsignedInt(buffer *[]byte, offset *int) uint32 {
@@ -242,7 +251,7 @@ func ReadLittleUnsignedInt(buffer *[]byte, offset *int) uint32 {
 }
 
 func WriteLittleLong(buffer *[]byte, long int64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	var v = uint64(long)
 	b[7] = byte(v >> 56)
 	b[6] = byte(v >> 48)
@@ -252,7 +261,7 @@ func WriteLittleLong(buffer *[]byte, long int64) {
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleLong(buffer *[]byte, offset *int) int64 {
@@ -262,7 +271,7 @@ func ReadLittleLong(buffer *[]byte, offset *int) int64 {
 }
 
 func WriteLittleUnsignedLong(buffer *[]byte, v uint64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	b[7] = byte(v >> 56)
 	b[6] = byte(v >> 48)
 	b[5] = byte(v >> 40)
@@ -271,7 +280,7 @@ func WriteLittleUnsignedLong(buffer *[]byte, v uint64) {
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleUnsignedLong(buffer *[]byte, offset *int) uint64 {
@@ -281,13 +290,13 @@ func ReadLittleUnsignedLong(buffer *[]byte, offset *int) uint64 {
 }
 
 func WriteLittleFloat(buffer *[]byte, float float32) {
-	var b = make([]byte, 4)
+	var b [4]byte
 	var v = math.Float32bits(float)
 	b[3] = byte(v >> 24)
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleFloat(buffer *[]byte, offset *int) float32 {
@@ -298,7 +307,7 @@ func ReadLittleFloat(buffer *[]byte, offset *int) float32 {
 }
 
 func WriteLittleDouble(buffer *[]byte, double float64) {
-	var b = make([]byte, 8)
+	var b [8]byte
 	var v = math.Float64bits(double)
 	b[7] = byte(v >> 56)
 	b[6] = byte(v >> 48)
@@ -308,7 +317,7 @@ func WriteLittleDouble(buffer *[]byte, double float64) {
 	b[2] = byte(v >> 16)
 	b[1] = byte(v >> 8)
 	b[0] = byte(v)
-	*buffer = append(*buffer, b...)
+	*buffer = append(*buffer, b[:]...)
 }
 
 func ReadLittleDouble(buffer *[]byte, offset *int) float64 {
@@ -321,7 +330,7 @@ func ReadLittleDouble(buffer *[]byte, offset *int) float64 {
 func ReadBigTriad(buffer *[]byte, offset *int) uint32 {
 	var out uint32
 	var b = Read(buffer, offset, 3)
-	out = (uint32(b[2]) & 0xFF) | ((uint32(b[1]) & 0xFF) << 8) | ((uint32(b[0]) & 0x0F) << 16)
+	out = (uint32(b[2]) & 0xFF) | ((uint32(b[1]) & 0xFF) << 8) | ((uint32(b[0]) & triadHighMask()) << 16)
 
 	return out
 }
@@ -335,7 +344,7 @@ func WriteLittleTriad(buffer *[]byte, uint uint32) {
 func ReadLittleTriad(buffer *[]byte, offset *int) uint32 {
 	var b = Read(buffer, offset, 3)
 
-	return (uint32(b[0]) & 0xFF) | ((uint32(b[1]) & 0xFF) << 8) | ((uint32(b[2]) & 0x0F) << 16)
+	return (uint32(b[0]) & 0xFF) | ((uint32(b[1]) & 0xFF) << 8) | ((uint32(b[2]) & triadHighMask()) << 16)
 }
 
 func WriteBigTriad(buffer *[]byte, uint uint32) {
@@ -344,6 +353,40 @@ func WriteBigTriad(buffer *[]byte, uint uint32) {
 	Write(buffer, byte(uint&0xFF))
 }
 
+// LegacyTriads makes the triad read functions mask the high byte with 0x0F like older versions did,
+// truncating triads to 20 bits. It only exists for compatibility with code relying on the truncation.
+var LegacyTriads = false
+
+// triadHighMask returns the mask applied to the high byte of triads.
+func triadHighMask() uint32 {
+	if LegacyTriads {
+		return 0x0F
+	}
+	return 0xFF
+}
+
+// WriteInt24 writes a signed 24 bit integer to the buffer in big endian.
+func WriteInt24(buffer *[]byte, v int32) {
+	WriteBigTriad(buffer, uint32(v))
+}
+
+// ReadInt24 reads a signed 24 bit integer from the buffer in big endian.
+func ReadInt24(buffer *[]byte, offset *int) int32 {
+	b := Read(buffer, offset, 3)
+	return int32(uint32(b[0])<<24|uint32(b[1])<<16|uint32(b[2])<<8) >> 8
+}
+
+// WriteLittleInt24 writes a signed 24 bit integer to the buffer in little endian.
+func WriteLittleInt24(buffer *[]byte, v int32) {
+	WriteLittleTriad(buffer, uint32(v))
+}
+
+// ReadLittleInt24 reads a signed 24 bit integer from the buffer in little endian.
+func ReadLittleInt24(buffer *[]byte, offset *int) int32 {
+	b := Read(buffer, offset, 3)
+	return int32(uint32(b[2])<<24|uint32(b[1])<<16|uint32(b[0])<<8) >> 8
+}
+
 func toZigZag32(n int32) uint32 {
 	return (uint32)((n << 1) ^ (n >> 31))
 }
@@ -400,7 +443,7 @@ func ReadUnsignedVarInt(buffer *[]byte, offset *int) uint32 {
 		result |= uint32(b0&0x7f) << (j * 7)
 		j++
 		if j > 5 { // up to 5 bytes in varint
-			panic("Varint too big")
+			panic(ErrMalformedVarInt)
 		}
 	}
 
@@ -431,13 +474,70 @@ func ReadUnsignedVarLong(buffer *[]byte, offset *int) uint64 {
 		result |= uint64(b0&0x7f) << (j * 7)
 		j++
 		if j > 10 { // up to 10 bytes in varlong
-			panic("Varint too big")
+			panic(ErrMalformedVarInt)
 		}
 	}
 
 	return result
 }
 
+// decodeUnsignedVarInt decodes an unsigned varint of at most maxBytes bytes at the start of the buffer,
+// or of any length if maxBytes is 0, ignoring bits beyond 64 bits.
+// It returns the value and the amount of bytes the varint takes up.
+func decodeUnsignedVarInt(buffer []byte, maxBytes int) (uint64, int, error) {
+	var result uint64
+	for i := 0; i < maxBytes || maxBytes == 0; i++ {
+		if i >= len(buffer) {
+			return 0, 0, ErrUnexpectedEOF
+		}
+		result |= uint64(buffer[i]&0x7f) << (7 * uint(i))
+		if buffer[i]&0x80 == 0 {
+			return result, i + 1, nil
+		}
+	}
+	return 0, 0, ErrMalformedVarInt
+}
+
+// ReadUnsignedVarIntFrom reads an unsigned varint of at most 5 bytes byte by byte from the reader.
+// Varints overflowing 32 bits return ErrMalformedVarInt.
+// It returns the value and the amount of bytes read, so that a length prefix can be decoded directly
+// from a connection before the rest of the frame is buffered. If the reader returns io.EOF before the first byte,
+// io.EOF is returned; if it does so in the middle of the varint, io.ErrUnexpectedEOF is returned.
+func ReadUnsignedVarIntFrom(reader io.ByteReader) (uint32, int, error) {
+	v, n, err := readUnsignedVarIntFrom(reader, 32)
+	return uint32(v), n, err
+}
+
+// ReadUnsignedVarLongFrom reads an unsigned varint of at most 10 bytes byte by byte from the reader,
+// like ReadUnsignedVarIntFrom.
+func ReadUnsignedVarLongFrom(reader io.ByteReader) (uint64, int, error) {
+	return readUnsignedVarIntFrom(reader, 64)
+}
+
+// readUnsignedVarIntFrom reads an unsigned varint of at most size bits from the reader.
+// Varints with bits set beyond the size in their last byte are malformed.
+func readUnsignedVarIntFrom(reader io.ByteReader, size int) (uint64, int, error) {
+	var result uint64
+	maxBytes := (size + 6) / 7
+	for i := 0; i < maxBytes; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, i, err
+		}
+		if i == maxBytes-1 && b>>(size-7*i) != 0 {
+			return 0, i + 1, ErrMalformedVarInt
+		}
+		result |= uint64(b&0x7f) << (7 * uint(i))
+		if b&0x80 == 0 {
+			return result, i + 1, nil
+		}
+	}
+	return 0, maxBytes, ErrMalformedVarInt
+}
+
 func WriteString(buffer *[]byte, str string) {
 	WriteUnsignedVarInt(buffer, uint32(len(str)))
 	*buffer = append(*buffer, []byte(str)...)
@@ -0,0 +1,31 @@
+package binutils
+
+import (
+	"encoding"
+)
+
+// PutBinary writes the encoding of the encoding.BinaryMarshaler, without length prefix.
+// A marshaling error is set on the stream.
+func (stream *Stream) PutBinary(m encoding.BinaryMarshaler) {
+	if !stream.writable("PutBinary", 0) {
+		return
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		stream.SetError(&StreamError{"PutBinary", len(stream.Buffer), err})
+		return
+	}
+	stream.PutBytes(data)
+}
+
+// GetBinary reads length bytes and decodes them with the encoding.BinaryUnmarshaler.
+// An unmarshaling error is set on the stream.
+func (stream *Stream) GetBinary(u encoding.BinaryUnmarshaler, length int) {
+	offset := stream.Offset
+	if !stream.check("GetBinary", length) {
+		return
+	}
+	if err := u.UnmarshalBinary(Read(&stream.Buffer, &stream.Offset, length)); err != nil {
+		stream.SetError(&StreamError{"GetBinary", offset, err})
+	}
+}
@@ -0,0 +1,34 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBinaryMarshaler(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	u, _ := url.Parse("https://example.com/path")
+	stream := NewStream()
+	stream.PutBinary(now)
+	stream.PutBinary(u)
+	length := len(stream.Buffer)
+	assert.NilError(t, stream.Err())
+
+	var decoded time.Time
+	stream.GetBinary(&decoded, 15)
+	assert.Assert(t, decoded.Equal(now))
+	var decodedURL url.URL
+	stream.GetBinary(&decodedURL, length-15)
+	assert.Equal(t, decodedURL.String(), u.String())
+	assert.NilError(t, stream.Err())
+
+	stream = &Stream{Buffer: b(0xff)}
+	stream.GetBinary(&decoded, 1)
+	assert.ErrorContains(t, stream.Err(), "binutils: GetBinary at offset 0: Time.UnmarshalBinary")
+	stream.ClearError()
+	stream.GetBinary(&decoded, 1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
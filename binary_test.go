@@ -1,7 +1,9 @@
 package binutils
 
 import (
+	"bytes"
 	"gotest.tools/assert"
+	"io"
 	"math"
 	"testing"
 )
@@ -95,3 +97,41 @@ func TestDecodeVarLong(t *testing.T) {
 		assert.Equal(t, value, read)
 	}
 }
+
+func TestReadUnsignedVarIntFrom(t *testing.T) {
+	reader := bytes.NewReader(b(0xac, 0x02, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x80))
+	v, n, err := ReadUnsignedVarIntFrom(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, v, uint32(300))
+	assert.Equal(t, n, 2)
+	assert.Equal(t, reader.Len(), 12)
+
+	v, n, err = ReadUnsignedVarIntFrom(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, v, uint32(1))
+	assert.Equal(t, n, 1)
+
+	long, n, err := ReadUnsignedVarLongFrom(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, long, uint64(math.MaxUint64))
+	assert.Equal(t, n, 10)
+
+	_, _, err = ReadUnsignedVarIntFrom(reader)
+	assert.Equal(t, err, io.ErrUnexpectedEOF)
+	_, _, err = ReadUnsignedVarIntFrom(reader)
+	assert.Equal(t, err, io.EOF)
+
+	_, n, err = ReadUnsignedVarIntFrom(bytes.NewReader(b(0x80, 0x80, 0x80, 0x80, 0x80, 0x01)))
+	assert.Equal(t, err, ErrMalformedVarInt)
+	assert.Equal(t, n, 5)
+
+	v, _, err = ReadUnsignedVarIntFrom(bytes.NewReader(b(0xff, 0xff, 0xff, 0xff, 0x0f)))
+	assert.NilError(t, err)
+	assert.Equal(t, v, uint32(math.MaxUint32))
+	// Bits beyond 32 in the last byte would be dropped silently.
+	_, n, err = ReadUnsignedVarIntFrom(bytes.NewReader(b(0xff, 0xff, 0xff, 0xff, 0x1f)))
+	assert.Equal(t, err, ErrMalformedVarInt)
+	assert.Equal(t, n, 5)
+	_, _, err = ReadUnsignedVarLongFrom(bytes.NewReader(b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02)))
+	assert.Equal(t, err, ErrMalformedVarInt)
+}
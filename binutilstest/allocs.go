@@ -0,0 +1,28 @@
+// Package binutilstest provides helpers for testing code built on binutils.
+package binutilstest
+
+import (
+	"testing"
+)
+
+// allocRuns is the number of runs allocations are averaged over.
+const allocRuns = 100
+
+// AssertAllocs fails the test if f allocates more than max times per run on average.
+// f is run once before measuring, so that buffers grown on the first run are not counted.
+// The test is skipped when the race detector is enabled, as it allocates on its own.
+func AssertAllocs(t testing.TB, max float64, f func()) {
+	t.Helper()
+	if raceEnabled {
+		t.Skip("allocations are not measured with the race detector enabled")
+	}
+	if allocs := testing.AllocsPerRun(allocRuns, f); allocs > max {
+		t.Errorf("got %v allocations per run, want at most %v", allocs, max)
+	}
+}
+
+// AssertNoAllocs fails the test if f allocates.
+func AssertNoAllocs(t testing.TB, f func()) {
+	t.Helper()
+	AssertAllocs(t, 0, f)
+}
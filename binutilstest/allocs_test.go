@@ -0,0 +1,36 @@
+package binutilstest
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// recorder records failures of a test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestAssertAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not measured with the race detector enabled")
+	}
+	var sink []byte
+	r := &recorder{TB: t}
+	AssertNoAllocs(r, func() {})
+	assert.Assert(t, !r.failed)
+	AssertNoAllocs(r, func() { sink = make([]byte, 64) })
+	assert.Assert(t, r.failed)
+
+	r = &recorder{TB: t}
+	AssertAllocs(r, 1, func() { sink = make([]byte, 64) })
+	assert.Assert(t, !r.failed)
+	_ = sink
+}
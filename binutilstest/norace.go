@@ -0,0 +1,6 @@
+//go:build !race
+
+package binutilstest
+
+// raceEnabled is set if the race detector is enabled.
+const raceEnabled = false
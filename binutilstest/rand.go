@@ -0,0 +1,141 @@
+package binutilstest
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Rand generates random values of the types read and written by binutils streams.
+// One in four values is an edge case, such as zero or the minimum or maximum of the type,
+// as encoders tend to fail on those.
+type Rand struct {
+	*rand.Rand
+}
+
+// NewRand returns a new generator with the given seed, so that failures can be reproduced.
+func NewRand(seed int64) *Rand {
+	return &Rand{rand.New(rand.NewSource(seed))}
+}
+
+// edge checks if the next value should be an edge case.
+func (r *Rand) edge() bool {
+	return r.Intn(4) == 0
+}
+
+// pick returns one of the values at random.
+func pick[T any](r *Rand, values ...T) T {
+	return values[r.Intn(len(values))]
+}
+
+// Bool returns a random bool.
+func (r *Rand) Bool() bool {
+	return r.Intn(2) == 0
+}
+
+// Byte returns a random byte.
+func (r *Rand) Byte() byte {
+	return byte(r.Rand.Uint32())
+}
+
+// Int16 returns a random int16.
+func (r *Rand) Int16() int16 {
+	if r.edge() {
+		return pick[int16](r, 0, 1, -1, math.MinInt16, math.MaxInt16)
+	}
+	return int16(r.Rand.Uint32())
+}
+
+// Uint16 returns a random uint16.
+func (r *Rand) Uint16() uint16 {
+	if r.edge() {
+		return pick[uint16](r, 0, 1, math.MaxUint16)
+	}
+	return uint16(r.Rand.Uint32())
+}
+
+// Int32 returns a random int32, such as for ints and varints.
+func (r *Rand) Int32() int32 {
+	if r.edge() {
+		return pick[int32](r, 0, 1, -1, math.MinInt32, math.MaxInt32)
+	}
+	// Values are spread over all encoded sizes of varints.
+	return int32(r.Rand.Uint32()) >> r.Intn(32)
+}
+
+// Uint32 returns a random uint32, such as for unsigned ints and varints.
+func (r *Rand) Uint32() uint32 {
+	if r.edge() {
+		return pick[uint32](r, 0, 1, 0x7f, 0x80, math.MaxUint32)
+	}
+	return r.Rand.Uint32() >> r.Intn(32)
+}
+
+// Int64 returns a random int64, such as for longs and varlongs.
+func (r *Rand) Int64() int64 {
+	if r.edge() {
+		return pick[int64](r, 0, 1, -1, math.MinInt64, math.MaxInt64)
+	}
+	return int64(r.Rand.Uint64()) >> r.Intn(64)
+}
+
+// Uint64 returns a random uint64, such as for unsigned longs and varlongs.
+func (r *Rand) Uint64() uint64 {
+	if r.edge() {
+		return pick[uint64](r, 0, 1, 0x7f, 0x80, math.MaxUint64)
+	}
+	return r.Rand.Uint64() >> r.Intn(64)
+}
+
+// Triad returns a random unsigned 24 bit integer.
+func (r *Rand) Triad() uint32 {
+	if r.edge() {
+		return pick[uint32](r, 0, 1, 1<<24-1)
+	}
+	return r.Rand.Uint32() & (1<<24 - 1)
+}
+
+// Int24 returns a random signed 24 bit integer.
+func (r *Rand) Int24() int32 {
+	if r.edge() {
+		return pick[int32](r, 0, 1, -1, -1<<23, 1<<23-1)
+	}
+	return int32(r.Rand.Uint32()<<8) >> 8
+}
+
+// Float32 returns a random float32, including infinities, NaN and negative zero.
+func (r *Rand) Float32() float32 {
+	if r.edge() {
+		return pick(r, 0, float32(math.Copysign(0, -1)), float32(math.Inf(1)), float32(math.Inf(-1)),
+			float32(math.NaN()), math.MaxFloat32, math.SmallestNonzeroFloat32)
+	}
+	return float32(r.NormFloat64() * math.Pow(10, float64(r.Intn(20)-10)))
+}
+
+// Float64 returns a random float64, including infinities, NaN and negative zero.
+func (r *Rand) Float64() float64 {
+	if r.edge() {
+		return pick(r, 0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN(),
+			math.MaxFloat64, math.SmallestNonzeroFloat64)
+	}
+	return r.NormFloat64() * math.Pow(10, float64(r.Intn(200)-100))
+}
+
+// Bytes returns random bytes of a random length up to maxLength.
+func (r *Rand) Bytes(maxLength int) []byte {
+	b := make([]byte, r.Intn(maxLength+1))
+	r.Read(b)
+	return b
+}
+
+// String returns a random valid UTF-8 string of up to maxLength runes, mixing ASCII and multi-byte runes.
+func (r *Rand) String(maxLength int) string {
+	runes := make([]rune, r.Intn(maxLength+1))
+	for i := range runes {
+		if r.Bool() {
+			runes[i] = rune(0x20 + r.Intn(0x5f))
+			continue
+		}
+		runes[i] = pick(r, 'é', 'ß', '€', '世', '😀', rune(r.Intn(0xd800)))
+	}
+	return string(runes)
+}
@@ -0,0 +1,134 @@
+package binutilstest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/irmine/binutils"
+)
+
+// Iterations is the amount of random values checked by CheckRandomRoundTrips.
+var Iterations = 200
+
+// CheckRoundTrip writes v with put and reads it back with get, failing the test if the value read differs from v,
+// if an error is set on the stream, or if get leaves bytes unread. Values are compared like reflect.DeepEqual,
+// except that NaNs are equal. It returns the encoding of v.
+func CheckRoundTrip[T any](t testing.TB, v T, put func(*binutils.Stream, T), get func(*binutils.Stream) T) []byte {
+	t.Helper()
+	if err := roundTrip(v, put, get); err != "" {
+		t.Errorf("round trip of %#v: %s", v, err)
+	}
+	return encode(v, put)
+}
+
+// CheckRandomRoundTrips checks the round trip of Iterations values generated by generate like CheckRoundTrip,
+// stopping at the first failure. The seed of the generator is logged on failure, to reproduce it with NewRand.
+func CheckRandomRoundTrips[T any](t testing.TB, generate func(*Rand) T, put func(*binutils.Stream, T), get func(*binutils.Stream) T) {
+	t.Helper()
+	seed := time.Now().UnixNano()
+	r := NewRand(seed)
+	for i := 0; i < Iterations; i++ {
+		v := generate(r)
+		if err := roundTrip(v, put, get); err != "" {
+			t.Errorf("round trip of %#v (value %d generated with seed %d): %s", v, i, seed, err)
+			return
+		}
+	}
+}
+
+// roundTrip writes and reads back v, and returns a description of the failure, if any.
+func roundTrip[T any](v T, put func(*binutils.Stream, T), get func(*binutils.Stream) T) string {
+	stream := binutils.NewStream()
+	put(stream, v)
+	if err := stream.Err(); err != nil {
+		return "put: " + err.Error()
+	}
+	decoded := get(stream)
+	switch {
+	case stream.Err() != nil:
+		return "get: " + stream.Err().Error()
+	case stream.Remaining() > 0:
+		return "get left bytes unread"
+	case !equal(reflect.ValueOf(decoded), reflect.ValueOf(v)):
+		return fmt.Sprintf("got %#v", decoded)
+	}
+	return ""
+}
+
+// encode returns the encoding of v written with put.
+func encode[T any](v T, put func(*binutils.Stream, T)) []byte {
+	stream := binutils.NewStream()
+	put(stream, v)
+	return stream.Buffer
+}
+
+// equal compares the values like reflect.DeepEqual, except that NaNs are equal.
+func equal(a, b reflect.Value) bool {
+	if a.IsValid() != b.IsValid() || a.IsValid() && a.Type() != b.Type() {
+		return false
+	}
+	if !a.IsValid() {
+		return true
+	}
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float() || math.IsNaN(a.Float()) && math.IsNaN(b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return equal(reflect.ValueOf(real(a.Complex())), reflect.ValueOf(real(b.Complex()))) &&
+			equal(reflect.ValueOf(imag(a.Complex())), reflect.ValueOf(imag(b.Complex())))
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if !b.MapIndex(key).IsValid() || !equal(a.MapIndex(key), b.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	}
+	// Unexported fields cannot be compared through Interface, so they are compared as their kind.
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return a.CanInterface() && reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,60 @@
+package binutilstest
+
+import (
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"gotest.tools/assert"
+
+	"github.com/irmine/binutils"
+)
+
+func TestCheckRoundTrip(t *testing.T) {
+	r := &recorder{TB: t}
+	encoded := CheckRoundTrip(r, math.NaN(), (*binutils.Stream).PutDouble, (*binutils.Stream).GetDouble)
+	assert.Assert(t, !r.failed)
+	assert.Equal(t, len(encoded), 8)
+
+	// A decoder truncating values fails.
+	CheckRoundTrip(r, int32(0x10000), (*binutils.Stream).PutInt, func(stream *binutils.Stream) int32 {
+		return stream.GetInt() & 0xffff
+	})
+	assert.Assert(t, r.failed)
+
+	// A decoder leaving bytes unread fails.
+	r = &recorder{TB: t}
+	CheckRoundTrip(r, int32(1), (*binutils.Stream).PutInt, func(stream *binutils.Stream) int32 {
+		return int32(stream.GetShort())
+	})
+	assert.Assert(t, r.failed)
+}
+
+func TestCheckRandomRoundTrips(t *testing.T) {
+	r := &recorder{TB: t}
+	CheckRandomRoundTrips(r, (*Rand).Int64, (*binutils.Stream).PutVarLong, (*binutils.Stream).GetVarLong)
+	assert.Assert(t, !r.failed)
+
+	// An encoder losing the sign of values fails on the negative values generated.
+	CheckRandomRoundTrips(r, (*Rand).Int32, func(stream *binutils.Stream, v int32) {
+		stream.PutUnsignedVarLong(uint64(max(v, 0)))
+	}, func(stream *binutils.Stream) int32 {
+		return int32(stream.GetUnsignedVarLong())
+	})
+	assert.Assert(t, r.failed)
+}
+
+func TestRand(t *testing.T) {
+	a, b := NewRand(1), NewRand(1)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, a.Int64(), b.Int64())
+	}
+	for i := 0; i < 1000; i++ {
+		assert.Assert(t, a.Triad() < 1<<24)
+		v := a.Int24()
+		assert.Assert(t, v >= -1<<23 && v < 1<<23)
+		assert.Assert(t, len(a.Bytes(8)) <= 8)
+		s := a.String(8)
+		assert.Assert(t, utf8.ValidString(s) && utf8.RuneCountInString(s) <= 8)
+	}
+}
@@ -0,0 +1,86 @@
+package binutils
+
+import (
+	"errors"
+)
+
+// errBitWidth is used when a bit field is wider than 64 bits.
+var errBitWidth = errors.New("bit field wider than 64 bits")
+
+// checkBits checks if the bit field at bitOffset bits past the byte at offset with the given width is in the buffer.
+// Bits are numbered from the most significant bit of the byte at offset.
+func (stream *Stream) checkBits(op string, offset int, bitOffset, width uint) bool {
+	if stream.err != nil {
+		return false
+	}
+	if width > 64 {
+		stream.SetError(&StreamError{op, offset, errBitWidth})
+		return false
+	}
+	if offset < 0 || uint64(offset)*8+uint64(bitOffset)+uint64(width) > uint64(len(stream.Buffer))*8 {
+		stream.SetError(&StreamError{op, offset, ErrUnexpectedEOF})
+		return false
+	}
+	return true
+}
+
+// GetBitsAt returns the bit field of the given width starting bitOffset bits past the byte at offset,
+// without moving the offset of the stream. Bits are numbered from the most significant bit of the byte at offset,
+// so that fields may straddle byte boundaries like in big endian register dumps.
+func (stream *Stream) GetBitsAt(offset int, bitOffset, width uint) uint64 {
+	if !stream.checkBits("GetBitsAt", offset, bitOffset, width) {
+		return 0
+	}
+	var v uint64
+	for bit := uint(offset)*8 + bitOffset; bit < uint(offset)*8+bitOffset+width; bit++ {
+		v = v<<1 | uint64(stream.Buffer[bit/8]>>(7-bit%8)&1)
+	}
+	return v
+}
+
+// SetBitsAt sets the bit field of the given width starting bitOffset bits past the byte at offset to the
+// lowest bits of v, leaving the other bits of the bytes unchanged.
+func (stream *Stream) SetBitsAt(offset int, bitOffset, width uint, v uint64) {
+	if !stream.writable("SetBitsAt", 0) {
+		return
+	}
+	if !stream.checkBits("SetBitsAt", offset, bitOffset, width) {
+		return
+	}
+	end := uint(offset)*8 + bitOffset + width
+	for bit := uint(offset)*8 + bitOffset; bit < end; bit++ {
+		mask := byte(1) << (7 - bit%8)
+		if v>>(end-bit-1)&1 == 1 {
+			stream.Buffer[bit/8] |= mask
+		} else {
+			stream.Buffer[bit/8] &^= mask
+		}
+	}
+}
+
+// UpdateBitsAt replaces the bit field of the given width starting bitOffset bits past the byte at offset
+// with the result of update, which is masked to the width of the field.
+func (stream *Stream) UpdateBitsAt(offset int, bitOffset, width uint, update func(v uint64) uint64) {
+	if !stream.writable("UpdateBitsAt", 0) {
+		return
+	}
+	v := stream.GetBitsAt(offset, bitOffset, width)
+	if stream.err == nil {
+		stream.SetBitsAt(offset, bitOffset, width, update(v))
+	}
+}
+
+// RotateBitsAt rotates the bit field of the given width starting bitOffset bits past the byte at offset
+// left by k bits, or right by -k bits.
+func (stream *Stream) RotateBitsAt(offset int, bitOffset, width uint, k int) {
+	if !stream.writable("RotateBitsAt", 0) {
+		return
+	}
+	stream.UpdateBitsAt(offset, bitOffset, width, func(v uint64) uint64 {
+		if width == 0 {
+			return v
+		}
+		k := uint(((k % int(width)) + int(width)) % int(width))
+		return v<<k | v>>(width-k)
+	})
+}
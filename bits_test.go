@@ -0,0 +1,32 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestBitsAt(t *testing.T) {
+	stream := &Stream{Buffer: b(0x12, 0x34, 0x56)}
+	assert.Equal(t, stream.GetBitsAt(0, 0, 4), uint64(0x1))
+	assert.Equal(t, stream.GetBitsAt(0, 4, 8), uint64(0x23))
+	assert.Equal(t, stream.GetBitsAt(1, 4, 12), uint64(0x456))
+	assert.Equal(t, stream.GetBitsAt(0, 0, 0), uint64(0))
+	assert.Equal(t, stream.Offset, 0)
+
+	stream.SetBitsAt(0, 4, 8, 0xab)
+	assert.DeepEqual(t, stream.Buffer, b(0x1a, 0xb4, 0x56))
+	stream.UpdateBitsAt(2, 0, 4, func(v uint64) uint64 { return v + 1 })
+	assert.DeepEqual(t, stream.Buffer, b(0x1a, 0xb4, 0x66))
+	stream.RotateBitsAt(0, 0, 8, 4)
+	assert.DeepEqual(t, stream.Buffer, b(0xa1, 0xb4, 0x66))
+	stream.RotateBitsAt(0, 0, 24, -8)
+	assert.DeepEqual(t, stream.Buffer, b(0x66, 0xa1, 0xb4))
+	assert.NilError(t, stream.Err())
+
+	assert.Equal(t, stream.GetBitsAt(2, 1, 8), uint64(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+	stream.ClearError()
+	stream.GetBitsAt(0, 0, 65)
+	assert.ErrorContains(t, stream.Err(), "bit field wider than 64 bits")
+}
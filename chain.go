@@ -0,0 +1,120 @@
+package binutils
+
+import (
+	"io"
+)
+
+// ChainStream presents multiple byte slices as one logical stream for reading, such as the fragments of packets
+// received from a connection, without copying them into one contiguous buffer. Bytes are only copied when a read
+// spans more than one segment.
+type ChainStream struct {
+	// Offset is the amount of bytes read from the chain.
+	Offset int
+
+	segments [][]byte
+	offset   int
+	length   int
+}
+
+// NewChainStream returns a new chain stream reading the segments in order.
+// The segments are not copied and must not be modified while the chain is read.
+func NewChainStream(segments ...[]byte) *ChainStream {
+	chain := &ChainStream{}
+	for _, segment := range segments {
+		chain.Append(segment)
+	}
+	return chain
+}
+
+// Append appends a segment to the end of the chain.
+func (chain *ChainStream) Append(segment []byte) {
+	if len(segment) == 0 {
+		return
+	}
+	chain.segments = append(chain.segments, segment)
+	chain.length += len(segment)
+}
+
+// Len returns the amount of bytes left to read in the chain.
+func (chain *ChainStream) Len() int {
+	return chain.length
+}
+
+// Segments returns the amount of segments with bytes left to read.
+func (chain *ChainStream) Segments() int {
+	return len(chain.segments)
+}
+
+// advance consumes n bytes, which must not exceed the bytes left in the first segment.
+func (chain *ChainStream) advance(n int) {
+	chain.offset += n
+	chain.length -= n
+	chain.Offset += n
+	if chain.offset == len(chain.segments[0]) {
+		chain.segments[0] = nil
+		chain.segments = chain.segments[1:]
+		chain.offset = 0
+	}
+}
+
+// Read reads up to len(p) bytes from the chain, returning io.EOF if no bytes are left. It implements io.Reader.
+func (chain *ChainStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if chain.length == 0 {
+		return 0, io.EOF
+	}
+	total := 0
+	for total < len(p) && chain.length > 0 {
+		n := copy(p[total:], chain.segments[0][chain.offset:])
+		chain.advance(n)
+		total += n
+	}
+	return total, nil
+}
+
+// ReadByte reads a byte from the chain, returning io.EOF if no bytes are left. It implements io.ByteReader,
+// so that length prefixes can be read from the chain with ReadUnsignedVarIntFrom.
+func (chain *ChainStream) ReadByte() (byte, error) {
+	if chain.length == 0 {
+		return 0, io.EOF
+	}
+	c := chain.segments[0][chain.offset]
+	chain.advance(1)
+	return c, nil
+}
+
+// Next returns the next n bytes and consumes them. The bytes share the segment they are in if they do not span
+// more than one segment, and are copied otherwise. If fewer than n bytes are left, nothing is consumed and
+// a *StreamError matching ErrUnexpectedEOF is returned.
+func (chain *ChainStream) Next(n int) ([]byte, error) {
+	return chain.next("Next", n)
+}
+
+// next returns the next n bytes for the operation op.
+func (chain *ChainStream) next(op string, n int) ([]byte, error) {
+	if n < 0 || n > chain.length {
+		return nil, &StreamError{op, chain.Offset, ErrUnexpectedEOF}
+	}
+	if n == 0 {
+		return []byte{}, nil
+	}
+	if segment := chain.segments[0][chain.offset:]; n <= len(segment) {
+		chain.advance(n)
+		return segment[:n:n], nil
+	}
+	bytes := make([]byte, n)
+	chain.Read(bytes)
+	return bytes, nil
+}
+
+// Sub returns a stream of the next n bytes and consumes them, sharing or copying them as described for Next,
+// so that the full Get API can be used to decode a packet of a known length.
+func (chain *ChainStream) Sub(n int) (*Stream, error) {
+	bytes, err := chain.next("Sub", n)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{Buffer: bytes}, nil
+}
@@ -0,0 +1,48 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"io"
+	"testing"
+)
+
+func TestChainStream(t *testing.T) {
+	chain := NewChainStream(b(0x03, 0x01), nil, b(0x02, 0x03, 0x02), b(0x04, 0x05, 0x06))
+	assert.Equal(t, chain.Len(), 8)
+	assert.Equal(t, chain.Segments(), 3)
+
+	length, _, err := ReadUnsignedVarIntFrom(chain)
+	assert.NilError(t, err)
+	packet, err := chain.Sub(int(length))
+	assert.NilError(t, err)
+	assert.Equal(t, packet.GetByte(), byte(1))
+	assert.Equal(t, packet.GetUnsignedShort(), uint16(0x0203))
+	assert.NilError(t, packet.Err())
+
+	first := chain.Segments()
+	next, err := chain.Next(2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, next, b(0x02, 0x04))
+	assert.Equal(t, chain.Segments(), first-1)
+
+	_, err = chain.Sub(3)
+	var streamErr *StreamError
+	assert.Assert(t, errors.As(err, &streamErr))
+	assert.Equal(t, *streamErr, StreamError{"Sub", 6, ErrUnexpectedEOF})
+
+	chain.Append(b(0x07))
+	rest, err := io.ReadAll(chain)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rest, b(0x05, 0x06, 0x07))
+	assert.Equal(t, chain.Offset, 9)
+}
+
+func TestChainStreamShares(t *testing.T) {
+	segment := b(1, 2, 3)
+	chain := NewChainStream(segment)
+	next, err := chain.Next(2)
+	assert.NilError(t, err)
+	assert.Equal(t, &next[0], &segment[0])
+	assert.Equal(t, cap(next), 2)
+}
@@ -0,0 +1,226 @@
+package binutils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"math/bits"
+)
+
+// ChecksumType is a checksum algorithm.
+type ChecksumType byte
+
+const (
+	// ChecksumCRC32 is CRC-32 with the IEEE polynomial.
+	ChecksumCRC32 ChecksumType = iota
+	// ChecksumCRC32C is CRC-32 with the Castagnoli polynomial.
+	ChecksumCRC32C
+	// ChecksumCRC16 is CRC-16/CCITT-FALSE: polynomial 0x1021, initial value 0xFFFF, not reflected.
+	ChecksumCRC16
+	ChecksumAdler32
+	// ChecksumXXHash64 is xxHash64 with seed 0.
+	ChecksumXXHash64
+)
+
+// checksumNames holds the names of the checksum types.
+var checksumNames = map[ChecksumType]string{
+	ChecksumCRC32:    "crc32",
+	ChecksumCRC32C:   "crc32c",
+	ChecksumCRC16:    "crc16",
+	ChecksumAdler32:  "adler32",
+	ChecksumXXHash64: "xxhash64",
+}
+
+// String returns the name of the checksum type.
+func (algo ChecksumType) String() string {
+	if name, ok := checksumNames[algo]; ok {
+		return name
+	}
+	return fmt.Sprintf("checksum %d", byte(algo))
+}
+
+// Size returns the encoded size of checksums of the type, or 0 if the type is unknown.
+func (algo ChecksumType) Size() int {
+	switch algo {
+	case ChecksumCRC32, ChecksumCRC32C, ChecksumAdler32:
+		return 4
+	case ChecksumCRC16:
+		return 2
+	case ChecksumXXHash64:
+		return 8
+	}
+	return 0
+}
+
+// castagnoliTable is the CRC-32 table of the Castagnoli polynomial.
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Checksum returns the checksum of the data, or an error if the checksum type is unknown.
+func Checksum(algo ChecksumType, data []byte) (uint64, error) {
+	sum, err := checksum(algo, data)
+	if err != nil {
+		return 0, fmt.Errorf("binutils: %w", err)
+	}
+	return sum, nil
+}
+
+// checksum returns the checksum of the data, or an error if the checksum type is unknown.
+func checksum(algo ChecksumType, data []byte) (uint64, error) {
+	switch algo {
+	case ChecksumCRC32:
+		return uint64(crc32.ChecksumIEEE(data)), nil
+	case ChecksumCRC32C:
+		return uint64(crc32.Checksum(data, castagnoliTable)), nil
+	case ChecksumCRC16:
+		return uint64(crc16(data)), nil
+	case ChecksumAdler32:
+		return uint64(adler32.Checksum(data)), nil
+	case ChecksumXXHash64:
+		return xxHash64(data), nil
+	}
+	return 0, fmt.Errorf("unknown %s", algo)
+}
+
+// Checksum returns the checksum of the whole buffer of the stream, or an error if the checksum type is unknown.
+func (stream *Stream) Checksum(algo ChecksumType) (uint64, error) {
+	return Checksum(algo, stream.Buffer)
+}
+
+// putChecksum writes a checksum of the type in the byte order of the stream.
+func (stream *Stream) putChecksum(algo ChecksumType, sum uint64) {
+	switch algo.Size() {
+	case 2:
+		WriteUnsignedShortEndian(&stream.Buffer, uint16(sum), stream.endian)
+	case 4:
+		WriteUnsignedIntEndian(&stream.Buffer, uint32(sum), stream.endian)
+	default:
+		WriteUnsignedLongEndian(&stream.Buffer, sum, stream.endian)
+	}
+}
+
+// getChecksum reads a checksum of the type in the byte order of the stream.
+func (stream *Stream) getChecksum(algo ChecksumType) uint64 {
+	switch algo.Size() {
+	case 2:
+		return uint64(ReadUnsignedShortEndian(&stream.Buffer, &stream.Offset, stream.endian))
+	case 4:
+		return uint64(ReadUnsignedIntEndian(&stream.Buffer, &stream.Offset, stream.endian))
+	default:
+		return ReadUnsignedLongEndian(&stream.Buffer, &stream.Offset, stream.endian)
+	}
+}
+
+// PutChecksummedBlock writes the data prefixed with its length, using the string prefix type of the stream,
+// followed by its checksum in the byte order of the stream. An unknown checksum type is set as error on the stream.
+func (stream *Stream) PutChecksummedBlock(algo ChecksumType, data []byte) {
+	if !stream.writable("PutChecksummedBlock", prefixSize(stream.stringPrefix, len(data))+len(data)+algo.Size()) {
+		return
+	}
+	sum, err := checksum(algo, data)
+	if err != nil {
+		stream.SetError(&StreamError{"PutChecksummedBlock", len(stream.Buffer), err})
+		return
+	}
+	stream.PutPrefix(stream.stringPrefix, len(data))
+	stream.PutBytes(data)
+	stream.putChecksum(algo, sum)
+}
+
+// VerifyChecksummedBlock reads a block written by PutChecksummedBlock and returns its data, which shares the buffer
+// of the stream. If the checksum does not match, ErrChecksumMismatch is set on the stream and returned,
+// as is an error for an unknown checksum type.
+func (stream *Stream) VerifyChecksummedBlock(algo ChecksumType) ([]byte, error) {
+	offset := stream.Offset
+	data := stream.getPrefixed("VerifyChecksummedBlock", stream.stringPrefix, stream.maxStringLength)
+	if !stream.check("VerifyChecksummedBlock", algo.Size()) {
+		return nil, stream.err
+	}
+	sum, err := checksum(algo, data)
+	if err != nil {
+		stream.SetError(&StreamError{"VerifyChecksummedBlock", offset, err})
+		return nil, stream.err
+	}
+	if stream.getChecksum(algo) != sum {
+		stream.SetError(&StreamError{"VerifyChecksummedBlock", offset, ErrChecksumMismatch})
+		return nil, stream.err
+	}
+	return data, nil
+}
+
+// crc16 returns the CRC-16/CCITT-FALSE of the data.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// The primes of xxHash64.
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxRound mixes an 8 byte lane into an accumulator of xxHash64.
+func xxRound(acc, lane uint64) uint64 {
+	acc += lane * xxPrime2
+	return bits.RotateLeft64(acc, 31) * xxPrime1
+}
+
+// xxMerge merges an accumulator into the hash of xxHash64.
+func xxMerge(hash, acc uint64) uint64 {
+	hash ^= xxRound(0, acc)
+	return hash*xxPrime1 + xxPrime4
+}
+
+// xxHash64 returns the xxHash64 of the data with seed 0.
+func xxHash64(data []byte) uint64 {
+	n := uint64(len(data))
+	var hash uint64
+	if len(data) >= 32 {
+		prime1 := xxPrime1
+		v1, v2, v3, v4 := prime1+xxPrime2, xxPrime2, uint64(0), -prime1
+		for ; len(data) >= 32; data = data[32:] {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(data))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(data[8:]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(data[16:]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(data[24:]))
+		}
+		hash = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		hash = xxMerge(xxMerge(xxMerge(xxMerge(hash, v1), v2), v3), v4)
+	} else {
+		hash = xxPrime5
+	}
+	hash += n
+	for ; len(data) >= 8; data = data[8:] {
+		hash ^= xxRound(0, binary.LittleEndian.Uint64(data))
+		hash = bits.RotateLeft64(hash, 27)*xxPrime1 + xxPrime4
+	}
+	if len(data) >= 4 {
+		hash ^= uint64(binary.LittleEndian.Uint32(data)) * xxPrime1
+		hash = bits.RotateLeft64(hash, 23)*xxPrime2 + xxPrime3
+		data = data[4:]
+	}
+	for _, b := range data {
+		hash ^= uint64(b) * xxPrime5
+		hash = bits.RotateLeft64(hash, 11) * xxPrime1
+	}
+	hash ^= hash >> 33
+	hash *= xxPrime2
+	hash ^= hash >> 29
+	hash *= xxPrime3
+	hash ^= hash >> 32
+	return hash
+}
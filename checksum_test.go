@@ -0,0 +1,66 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	check := []byte("123456789")
+	for _, test := range []struct {
+		algo ChecksumType
+		data []byte
+		sum  uint64
+	}{
+		{ChecksumCRC32, check, 0xcbf43926},
+		{ChecksumCRC32C, check, 0xe3069283},
+		{ChecksumCRC16, check, 0x29b1},
+		{ChecksumAdler32, check, 0x091e01de},
+		{ChecksumXXHash64, nil, 0xef46db3751d8e999},
+		{ChecksumXXHash64, []byte("abc"), 0x44bc2cf5ad770999},
+		{ChecksumXXHash64, []byte("Nobody inspects the spammish repetition"), 0xfbcea83c8a378bf1},
+	} {
+		sum, err := Checksum(test.algo, test.data)
+		assert.NilError(t, err)
+		assert.Equal(t, sum, test.sum, test.algo.String())
+	}
+
+	stream := &Stream{Buffer: check}
+	sum, err := stream.Checksum(ChecksumCRC32)
+	assert.NilError(t, err)
+	assert.Equal(t, sum, uint64(0xcbf43926))
+	_, err = stream.Checksum(ChecksumType(9))
+	assert.Error(t, err, "binutils: unknown checksum 9")
+}
+
+func TestChecksummedBlock(t *testing.T) {
+	for _, algo := range []ChecksumType{ChecksumCRC32, ChecksumCRC32C, ChecksumCRC16, ChecksumAdler32, ChecksumXXHash64} {
+		stream := NewStream()
+		stream.SetEndianness(LittleEndian)
+		stream.PutChecksummedBlock(algo, []byte("block"))
+		assert.Equal(t, len(stream.Buffer), 6+algo.Size(), algo.String())
+		data, err := stream.VerifyChecksummedBlock(algo)
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), "block")
+
+		stream.Buffer[1] ^= 1
+		stream.Offset = 0
+		_, err = stream.VerifyChecksummedBlock(algo)
+		assert.Assert(t, errors.Is(err, ErrChecksumMismatch))
+	}
+
+	stream := NewStream()
+	stream.PutChecksummedBlock(ChecksumCRC32, []byte("block"))
+	stream.Buffer = stream.Buffer[:len(stream.Buffer)-1]
+	_, err := stream.VerifyChecksummedBlock(ChecksumCRC32)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+
+	stream = NewStream()
+	stream.PutChecksummedBlock(ChecksumType(9), []byte("block"))
+	assert.Error(t, stream.Err(), "binutils: PutChecksummedBlock at offset 0: unknown checksum 9")
+	assert.Equal(t, len(stream.Buffer), 0)
+	stream = &Stream{Buffer: []byte{0}}
+	_, err = stream.VerifyChecksummedBlock(ChecksumType(9))
+	assert.Error(t, err, "binutils: VerifyChecksummedBlock at offset 0: unknown checksum 9")
+}
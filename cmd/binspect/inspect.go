@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/irmine/binutils"
+)
+
+// decoders are the decoding functions of the field types, without their byte order suffix.
+var decoders = map[string]func(stream *binutils.Stream) interface{}{
+	"u8":       func(stream *binutils.Stream) interface{} { return stream.GetUnsignedByte() },
+	"i8":       func(stream *binutils.Stream) interface{} { return int8(stream.GetByte()) },
+	"bool":     func(stream *binutils.Stream) interface{} { return stream.GetBool() },
+	"u16":      func(stream *binutils.Stream) interface{} { return stream.GetUnsignedShort() },
+	"i16":      func(stream *binutils.Stream) interface{} { return stream.GetShort() },
+	"u24":      func(stream *binutils.Stream) interface{} { return stream.GetTriad() },
+	"i24":      func(stream *binutils.Stream) interface{} { return stream.GetInt24() },
+	"u32":      func(stream *binutils.Stream) interface{} { return stream.GetUnsignedInt() },
+	"i32":      func(stream *binutils.Stream) interface{} { return stream.GetInt() },
+	"u64":      func(stream *binutils.Stream) interface{} { return stream.GetUnsignedLong() },
+	"i64":      func(stream *binutils.Stream) interface{} { return stream.GetLong() },
+	"f32":      func(stream *binutils.Stream) interface{} { return stream.GetFloat() },
+	"f64":      func(stream *binutils.Stream) interface{} { return stream.GetDouble() },
+	"varint":   func(stream *binutils.Stream) interface{} { return stream.GetVarInt() },
+	"uvarint":  func(stream *binutils.Stream) interface{} { return stream.GetUnsignedVarInt() },
+	"varlong":  func(stream *binutils.Stream) interface{} { return stream.GetVarLong() },
+	"uvarlong": func(stream *binutils.Stream) interface{} { return stream.GetUnsignedVarLong() },
+	"string":   func(stream *binutils.Stream) interface{} { return fmt.Sprintf("%q", stream.GetString()) },
+	"bytes":    func(stream *binutils.Stream) interface{} { return hex.EncodeToString(stream.GetLengthPrefixedBytes()) },
+	"cstring":  func(stream *binutils.Stream) interface{} { return fmt.Sprintf("%q", stream.GetCString()) },
+}
+
+// parseField returns the decoding function and byte order of a field type.
+func parseField(field string) (func(stream *binutils.Stream) interface{}, binutils.EndianType, error) {
+	name, endian := field, binutils.BigEndian
+	if base, ok := strings.CutSuffix(field, "le"); ok && decoders[base] != nil {
+		name, endian = base, binutils.LittleEndian
+	} else if base, ok := strings.CutSuffix(field, "be"); ok && decoders[base] != nil {
+		name = base
+	}
+	decode := decoders[name]
+	if decode == nil {
+		return nil, 0, fmt.Errorf("unknown field type %q", field)
+	}
+	return decode, endian, nil
+}
+
+// Inspect decodes the fields from the data, writing a line with the offset, type and value of each field to the writer,
+// followed by the amount of bytes left. Decoding stops at the first error, which is returned.
+func Inspect(writer io.Writer, data []byte, fields []string) error {
+	type step struct {
+		field  string
+		decode func(stream *binutils.Stream) interface{}
+		endian binutils.EndianType
+	}
+	steps := make([]step, len(fields))
+	for i, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		decode, endian, err := parseField(field)
+		if err != nil {
+			return err
+		}
+		steps[i] = step{field, decode, endian}
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	stream := &binutils.Stream{Buffer: data}
+	for _, d := range steps {
+		offset := stream.Offset
+		stream.SetEndianness(d.endian)
+		value := d.decode(stream)
+		if err := stream.Err(); err != nil {
+			table.Flush()
+			return err
+		}
+		fmt.Fprintf(table, "0x%04x\t%s\t%v\n", offset, d.field, value)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "%d bytes left\n", len(stream.Buffer)-stream.Offset)
+	return err
+}
+
+// ParseHex decodes a hex string, ignoring white space, colons and 0x prefixes.
+func ParseHex(s string) ([]byte, error) {
+	s = strings.ReplaceAll(s, "0x", "")
+	s = strings.Map(func(r rune) rune {
+		if r == ':' || r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+	return hex.DecodeString(s)
+}
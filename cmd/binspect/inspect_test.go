@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/irmine/binutils"
+	"gotest.tools/assert"
+)
+
+func TestInspect(t *testing.T) {
+	data, err := ParseHex("0x01 02 05 05:73 74 65 76 65 00 00 c0 3f ff")
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	assert.NilError(t, Inspect(&out, data, []string{"u16be", "varint", "string", "f32le"}))
+	assert.Equal(t, out.String(), `0x0000  u16be   258
+0x0002  varint  -3
+0x0003  string  "steve"
+0x0009  f32le   1.5
+1 bytes left
+`)
+}
+
+func TestInspectErrors(t *testing.T) {
+	err := Inspect(&bytes.Buffer{}, nil, []string{"u128"})
+	assert.ErrorContains(t, err, "unknown field type")
+
+	var out bytes.Buffer
+	err = Inspect(&out, []byte{1, 2}, []string{"u8", "u32"})
+	assert.Assert(t, errors.Is(err, binutils.ErrUnexpectedEOF))
+	assert.Equal(t, out.String(), "0x0000  u8  1\n")
+}
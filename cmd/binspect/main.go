@@ -0,0 +1,58 @@
+// Command binspect decodes binary data field by field and prints each decoded value with its offset,
+// which is useful for debugging captured packets.
+//
+// The data is read from a file, from standard input if the file is "-", or from a hex string given with -x.
+// Fields are given as a comma separated list of types:
+//
+//	u8, i8, bool                     single bytes
+//	u16, i16, u24, i24, u32, i32,    fixed width integers and floats, big endian unless suffixed with le,
+//	u64, i64, f32, f64               such as u16le or f32be
+//	varint, uvarint, varlong,        zigzag encoded and unsigned varints
+//	uvarlong
+//	string, bytes                    strings and bytes prefixed with their length as unsigned varint
+//	cstring                          strings terminated by a zero byte
+//
+// Usage:
+//
+//	binspect -f u16be,varint,string,f32le capture.bin
+//	binspect -f u8,uvarint -x "fe 0a"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	fields := flag.String("f", "", "comma separated field types")
+	hexData := flag.String("x", "", "hex encoded data to decode instead of a file")
+	flag.Parse()
+	if *fields == "" || (*hexData == "") == (flag.NArg() == 0) {
+		fmt.Fprintln(os.Stderr, "usage: binspect -f fields (-x hex | file)")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	data, err := readInput(*hexData, flag.Arg(0))
+	if err == nil {
+		err = Inspect(os.Stdout, data, strings.Split(*fields, ","))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "binspect:", err)
+		os.Exit(1)
+	}
+}
+
+// readInput returns the decoded hex data, or the contents of the file if there is no hex data.
+func readInput(hexData, path string) ([]byte, error) {
+	switch {
+	case hexData != "":
+		return ParseHex(hexData)
+	case path == "-":
+		return io.ReadAll(os.Stdin)
+	default:
+		return os.ReadFile(path)
+	}
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// wire describes how numbers of a wire type are written and read.
+type wire struct {
+	// put and get are the stream methods in big endian, little are the ones in little endian if the byte order matters.
+	put, get, littlePut, littleGet string
+	// goType is the Go type the stream methods take and return.
+	goType string
+}
+
+// wires maps the wire types of struct tags to their stream methods.
+var wires = map[string]wire{
+	"bool":     {"PutBool", "GetBool", "", "", "bool"},
+	"int8":     {"PutByte", "GetByte", "", "", "byte"},
+	"uint8":    {"PutByte", "GetByte", "", "", "byte"},
+	"byte":     {"PutByte", "GetByte", "", "", "byte"},
+	"int16":    {"PutShort", "GetShort", "PutLittleShort", "GetLittleShort", "int16"},
+	"uint16":   {"PutUnsignedShort", "GetUnsignedShort", "PutLittleUnsignedShort", "GetLittleUnsignedShort", "uint16"},
+	"int32":    {"PutInt", "GetInt", "PutLittleInt", "GetLittleInt", "int32"},
+	"uint32":   {"PutUnsignedInt", "GetUnsignedInt", "PutLittleUnsignedInt", "GetLittleUnsignedInt", "uint32"},
+	"int64":    {"PutLong", "GetLong", "PutLittleLong", "GetLittleLong", "int64"},
+	"uint64":   {"PutUnsignedLong", "GetUnsignedLong", "PutLittleUnsignedLong", "GetLittleUnsignedLong", "uint64"},
+	"float32":  {"PutFloat", "GetFloat", "PutLittleFloat", "GetLittleFloat", "float32"},
+	"float64":  {"PutDouble", "GetDouble", "PutLittleDouble", "GetLittleDouble", "float64"},
+	"varint":   {"PutVarInt", "GetVarInt", "", "", "int32"},
+	"varlong":  {"PutVarLong", "GetVarLong", "", "", "int64"},
+	"uvarint":  {"PutUnsignedVarInt", "GetUnsignedVarInt", "", "", "uint32"},
+	"uvarlong": {"PutUnsignedVarLong", "GetUnsignedVarLong", "", "", "uint64"},
+	"triad":    {"PutTriad", "GetTriad", "PutLittleTriad", "GetLittleTriad", "uint32"},
+	"int24":    {"PutInt24", "GetInt24", "PutLittleInt24", "GetLittleInt24", "int32"},
+}
+
+// defaultWires maps basic Go types to the wire types used for fields without a wire type in their tag.
+var defaultWires = map[string]string{
+	"bool": "bool", "int8": "int8", "uint8": "uint8", "byte": "uint8", "int16": "int16", "uint16": "uint16",
+	"int32": "int32", "uint32": "uint32", "int64": "int64", "uint64": "uint64", "int": "varlong", "uint": "uvarlong",
+	"float32": "float32", "float64": "float64",
+}
+
+// tag is the parsed `bin` struct tag of a field.
+type tag struct {
+	wire   string
+	little bool
+	prefix string
+}
+
+// parseTag parses a `bin` struct tag.
+func parseTag(s string) (tag, error) {
+	parsed := tag{prefix: "uvarint"}
+	if s == "" {
+		return parsed, nil
+	}
+	for i, option := range strings.Split(s, ",") {
+		switch {
+		case option == "le":
+			parsed.little = true
+		case option == "be":
+			parsed.little = false
+		case strings.HasPrefix(option, "prefix="):
+			prefix := strings.TrimPrefix(option, "prefix=")
+			if w, ok := wires[prefix]; !ok || prefix == "bool" || w.goType == "float32" || w.goType == "float64" {
+				return parsed, fmt.Errorf("invalid length prefix %q", option)
+			}
+			parsed.prefix = prefix
+		case strings.HasPrefix(option, "sizeof=") || strings.HasPrefix(option, "crc32=") || strings.HasPrefix(option, "compute="):
+			return parsed, fmt.Errorf("option %q is not supported", option)
+		case i == 0 && option == "":
+		case i == 0:
+			if _, ok := wires[option]; !ok {
+				return parsed, fmt.Errorf("unknown wire type %q", option)
+			}
+			parsed.wire = option
+		default:
+			return parsed, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return parsed, nil
+}
+
+// generator generates the methods of the struct types of a package.
+type generator struct {
+	types     map[string]ast.Expr
+	buffer    bytes.Buffer
+	generated map[string]bool
+	queue     []string
+	vars      int
+}
+
+// Generate parses the Go files of the package in the directory and returns the source of a file with the name,
+// holding the Encode and Decode methods of the types and the struct types they use.
+func Generate(dir string, types []string, name string) ([]byte, error) {
+	set := token.NewFileSet()
+	pkgs, err := parser.ParseDir(set, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != name
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	g := &generator{types: make(map[string]ast.Expr), generated: make(map[string]bool)}
+	var pkgName string
+	for _, pkg := range pkgs {
+		pkgName = pkg.Name
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+					for _, spec := range decl.Specs {
+						spec := spec.(*ast.TypeSpec)
+						g.types[spec.Name.Name] = spec.Type
+					}
+				}
+			}
+		}
+	}
+
+	fmt.Fprintf(&g.buffer, "// Code generated by binutils-gen. DO NOT EDIT.\n\npackage %s\n\nimport \"github.com/irmine/binutils\"\n", pkgName)
+	sorted := append([]string{}, types...)
+	sort.Strings(sorted)
+	g.queue = sorted
+	for len(g.queue) > 0 {
+		typeName := g.queue[0]
+		g.queue = g.queue[1:]
+		if g.generated[typeName] {
+			continue
+		}
+		g.generated[typeName] = true
+		if err := g.generate(typeName); err != nil {
+			return nil, err
+		}
+	}
+	source, err := format.Source(g.buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return source, nil
+}
+
+// generate writes the methods of the struct type.
+func (g *generator) generate(typeName string) error {
+	structType, ok := g.types[typeName].(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("%s is not a struct type declared in the package", typeName)
+	}
+	var encode, decode bytes.Buffer
+	g.vars = 0
+	for _, field := range structType.Fields.List {
+		var raw string
+		if field.Tag != nil {
+			raw, _ = strconv.Unquote(field.Tag.Value)
+		}
+		if reflect.StructTag(raw).Get("bin") == "-" {
+			continue
+		}
+		fieldTag, err := parseTag(reflect.StructTag(raw).Get("bin"))
+		if err != nil {
+			return fmt.Errorf("%s: %v", typeName, err)
+		}
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded structs are encoded in place, like Marshal flattens them.
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("%s: unsupported embedded field %s", typeName, typeString(field.Type))
+			}
+			names = []*ast.Ident{ident}
+		}
+		for _, name := range names {
+			if !name.IsExported() && len(field.Names) > 0 {
+				continue
+			}
+			if err := g.encode(&encode, "p."+name.Name, field.Type, fieldTag); err != nil {
+				return fmt.Errorf("field %s of %s: %v", name.Name, typeName, err)
+			}
+			if err := g.decode(&decode, "p."+name.Name, field.Type, fieldTag); err != nil {
+				return fmt.Errorf("field %s of %s: %v", name.Name, typeName, err)
+			}
+		}
+	}
+	fmt.Fprintf(&g.buffer, `
+// Encode writes the %[1]s to the stream with the encoding of binutils.Marshal.
+func (p *%[1]s) Encode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+%[2]s}
+
+// Decode reads the %[1]s from the stream with the encoding of binutils.Unmarshal.
+func (p *%[1]s) Decode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+%[3]s}
+`, typeName, encode.String(), decode.String())
+	return nil
+}
+
+// basic returns the basic Go type underlying the type expression, or "" if it is not a basic type.
+func (g *generator) basic(expr ast.Expr) string {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if _, ok := defaultWires[ident.Name]; ok || ident.Name == "string" {
+		return ident.Name
+	}
+	if underlying, ok := g.types[ident.Name]; ok {
+		return g.basic(underlying)
+	}
+	return ""
+}
+
+// numberWire returns the wire type of a number of the basic type with the tag.
+func numberWire(basic string, fieldTag tag) (wire, error) {
+	name := fieldTag.wire
+	if name == "" {
+		name = defaultWires[basic]
+	}
+	w := wires[name]
+	switch {
+	case (basic == "bool") != (name == "bool"):
+		return w, fmt.Errorf("wire type %s does not match type %s", name, basic)
+	case (basic == "float32" || basic == "float64") != (w.goType == "float32" || w.goType == "float64"):
+		return w, fmt.Errorf("wire type %s does not match type %s", name, basic)
+	}
+	return w, nil
+}
+
+// method returns the stream method of the wire type in the byte order of the tag.
+func method(put string, little string, fieldTag tag) string {
+	if fieldTag.little && little != "" {
+		return little
+	}
+	return put
+}
+
+// variable returns a new variable name with the prefix, unique within the methods of a type.
+func (g *generator) variable(prefix string) string {
+	g.vars++
+	return fmt.Sprintf("%s%d", prefix, g.vars)
+}
+
+// encode writes the statements encoding the expression of the type.
+func (g *generator) encode(out *bytes.Buffer, expr string, t ast.Expr, fieldTag tag) error {
+	if basic := g.basic(t); basic != "" {
+		if basic == "string" {
+			g.encodeLength(out, "len("+expr+")", fieldTag)
+			fmt.Fprintf(out, "\tstream.PutBytes([]byte(%s))\n", expr)
+			return nil
+		}
+		w, err := numberWire(basic, fieldTag)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\tstream.%s(%s)\n", method(w.put, w.littlePut, fieldTag), convert(w.goType, typeString(t), expr))
+		return nil
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		if _, ok := g.types[t.Name].(*ast.StructType); !ok {
+			return fmt.Errorf("unsupported type %s", t.Name)
+		}
+		g.queue = append(g.queue, t.Name)
+		fmt.Fprintf(out, "\t%s.Encode(stream)\n", expr)
+		return nil
+	case *ast.ArrayType:
+		if t.Len == nil {
+			if elem := g.basic(t.Elt); (elem == "byte" || elem == "uint8") && fieldTag.wire == "" {
+				g.encodeLength(out, "len("+expr+")", fieldTag)
+				fmt.Fprintf(out, "\tstream.PutBytes(%s)\n", expr)
+				return nil
+			}
+			g.encodeLength(out, "len("+expr+")", fieldTag)
+		}
+		i := g.variable("i")
+		fmt.Fprintf(out, "\tfor %s := range %s {\n", i, expr)
+		if err := g.encode(out, expr+"["+i+"]", t.Elt, fieldTag); err != nil {
+			return err
+		}
+		out.WriteString("\t}\n")
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", typeString(t))
+}
+
+// encodeLength writes the statement encoding a length prefix.
+func (g *generator) encodeLength(out *bytes.Buffer, length string, fieldTag tag) {
+	w := wires[fieldTag.prefix]
+	fmt.Fprintf(out, "\tstream.%s(%s(%s))\n", method(w.put, w.littlePut, fieldTag), w.goType, length)
+}
+
+// decode writes the statements decoding the expression of the type.
+func (g *generator) decode(out *bytes.Buffer, expr string, t ast.Expr, fieldTag tag) error {
+	if basic := g.basic(t); basic != "" {
+		if basic == "string" {
+			n := g.decodeLength(out, fieldTag)
+			fmt.Fprintf(out, "\t%s = %s(stream.Get(%s))\n", expr, typeString(t), n)
+			return nil
+		}
+		w, err := numberWire(basic, fieldTag)
+		if err != nil {
+			return err
+		}
+		value := fmt.Sprintf("stream.%s()", method(w.get, w.littleGet, fieldTag))
+		if fieldTag.wire == "int8" {
+			value = "int8(" + value + ")"
+		}
+		fmt.Fprintf(out, "\t%s = %s\n", expr, convert(typeString(t), w.goType, value))
+		return nil
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		fmt.Fprintf(out, "\t%s.Decode(stream)\n", expr)
+		return nil
+	case *ast.ArrayType:
+		if t.Len == nil {
+			n := g.decodeLength(out, fieldTag)
+			if elem := g.basic(t.Elt); (elem == "byte" || elem == "uint8") && fieldTag.wire == "" {
+				fmt.Fprintf(out, "\t%s = append(%s(nil), stream.Get(%s)...)\n", expr, typeString(t), n)
+				return nil
+			}
+			fmt.Fprintf(out, "\t%s = make(%s, %s)\n", expr, typeString(t), n)
+		}
+		i := g.variable("i")
+		fmt.Fprintf(out, "\tfor %s := range %s {\n", i, expr)
+		if err := g.decode(out, expr+"["+i+"]", t.Elt, fieldTag); err != nil {
+			return err
+		}
+		out.WriteString("\t}\n")
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", typeString(t))
+}
+
+// decodeLength writes the statements decoding a length prefix, returning early if the length exceeds the bytes left,
+// and returns the name of the variable holding the length.
+func (g *generator) decodeLength(out *bytes.Buffer, fieldTag tag) string {
+	w := wires[fieldTag.prefix]
+	n, offset := g.variable("n"), g.variable("offset")
+	fmt.Fprintf(out, "\t%s := stream.Offset\n\t%s := int(stream.%s())\n", offset, n, method(w.get, w.littleGet, fieldTag))
+	fmt.Fprintf(out, "\tif %[1]s < 0 || %[1]s > stream.Remaining() {\n", n)
+	fmt.Fprintf(out, "\t\tif stream.Err() == nil {\n\t\t\tstream.SetError(&binutils.StreamError{Op: \"GetStruct\", Offset: %s, Err: binutils.ErrUnexpectedEOF})\n\t\t}\n\t\treturn\n\t}\n", offset)
+	return n
+}
+
+// convert returns the expression of type from converted to type to, omitting the conversion if the types are equal.
+func convert(to string, from string, expr string) string {
+	if to == from || (to == "byte" && from == "uint8") || (to == "uint8" && from == "byte") {
+		return expr
+	}
+	return to + "(" + expr + ")"
+}
+
+// typeString returns the source of a type expression.
+func typeString(t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + typeString(t.Elt)
+		}
+		if lit, ok := t.Len.(*ast.BasicLit); ok {
+			return "[" + lit.Value + "]" + typeString(t.Elt)
+		}
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	}
+	return fmt.Sprintf("%T", t)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	source, err := Generate("internal/example", []string{"LoginPacket"}, "binutils_gen.go")
+	assert.NilError(t, err)
+	committed, err := os.ReadFile("internal/example/binutils_gen.go")
+	assert.NilError(t, err)
+	assert.Equal(t, string(source), string(committed), "run go generate in internal/example")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate("internal/example", []string{"PacketID"}, "binutils_gen.go")
+	assert.ErrorContains(t, err, "PacketID is not a struct type")
+
+	_, err = parseTag("varint,sizeof=Name")
+	assert.ErrorContains(t, err, `option "sizeof=Name" is not supported`)
+	_, err = parseTag(",prefix=float32")
+	assert.ErrorContains(t, err, "invalid length prefix")
+	_, err = numberWire("float32", tag{wire: "varint"})
+	assert.ErrorContains(t, err, "does not match")
+}
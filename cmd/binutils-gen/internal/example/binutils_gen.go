@@ -0,0 +1,189 @@
+// Code generated by binutils-gen. DO NOT EDIT.
+
+package example
+
+import "github.com/irmine/binutils"
+
+// Encode writes the LoginPacket to the stream with the encoding of binutils.Marshal.
+func (p *LoginPacket) Encode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	p.Header.Encode(stream)
+	stream.PutUnsignedVarInt(uint32(len(p.Name)))
+	stream.PutBytes([]byte(p.Name))
+	stream.PutBool(p.Enabled)
+	for i3 := range p.Position {
+		stream.PutLittleFloat(p.Position[i3])
+	}
+	stream.PutFloat(float32(p.Yaw))
+	stream.PutUnsignedVarInt(uint32(len(p.Scores)))
+	for i5 := range p.Scores {
+		stream.PutVarLong(p.Scores[i5])
+	}
+	stream.PutUnsignedVarInt(uint32(len(p.Items)))
+	for i9 := range p.Items {
+		p.Items[i9].Encode(stream)
+	}
+	stream.PutUnsignedInt(uint32(len(p.Payload)))
+	stream.PutBytes(p.Payload)
+	stream.PutLittleTriad(p.Sequence)
+	stream.PutInt24(p.Delta)
+	stream.PutByte(byte(p.Small))
+	stream.PutUnsignedVarInt(uint32(len(p.Tags)))
+	for i15 := range p.Tags {
+		stream.PutUnsignedVarInt(uint32(len(p.Tags[i15])))
+		for i16 := range p.Tags[i15] {
+			stream.PutUnsignedVarInt(uint32(len(p.Tags[i15][i16])))
+			stream.PutBytes([]byte(p.Tags[i15][i16]))
+		}
+	}
+	p.trailer.Encode(stream)
+}
+
+// Decode reads the LoginPacket from the stream with the encoding of binutils.Unmarshal.
+func (p *LoginPacket) Decode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	p.Header.Decode(stream)
+	offset2 := stream.Offset
+	n1 := int(stream.GetUnsignedVarInt())
+	if n1 < 0 || n1 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset2, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Name = string(stream.Get(n1))
+	p.Enabled = stream.GetBool()
+	for i4 := range p.Position {
+		p.Position[i4] = stream.GetLittleFloat()
+	}
+	p.Yaw = float64(stream.GetFloat())
+	offset7 := stream.Offset
+	n6 := int(stream.GetUnsignedVarInt())
+	if n6 < 0 || n6 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset7, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Scores = make([]int64, n6)
+	for i8 := range p.Scores {
+		p.Scores[i8] = stream.GetVarLong()
+	}
+	offset11 := stream.Offset
+	n10 := int(stream.GetUnsignedVarInt())
+	if n10 < 0 || n10 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset11, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Items = make([]Item, n10)
+	for i12 := range p.Items {
+		p.Items[i12].Decode(stream)
+	}
+	offset14 := stream.Offset
+	n13 := int(stream.GetUnsignedInt())
+	if n13 < 0 || n13 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset14, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Payload = append([]byte(nil), stream.Get(n13)...)
+	p.Sequence = stream.GetLittleTriad()
+	p.Delta = stream.GetInt24()
+	p.Small = int16(int8(stream.GetByte()))
+	offset18 := stream.Offset
+	n17 := int(stream.GetUnsignedVarInt())
+	if n17 < 0 || n17 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset18, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Tags = make([][]string, n17)
+	for i19 := range p.Tags {
+		offset21 := stream.Offset
+		n20 := int(stream.GetUnsignedVarInt())
+		if n20 < 0 || n20 > stream.Remaining() {
+			if stream.Err() == nil {
+				stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset21, Err: binutils.ErrUnexpectedEOF})
+			}
+			return
+		}
+		p.Tags[i19] = make([]string, n20)
+		for i22 := range p.Tags[i19] {
+			offset24 := stream.Offset
+			n23 := int(stream.GetUnsignedVarInt())
+			if n23 < 0 || n23 > stream.Remaining() {
+				if stream.Err() == nil {
+					stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset24, Err: binutils.ErrUnexpectedEOF})
+				}
+				return
+			}
+			p.Tags[i19][i22] = string(stream.Get(n23))
+		}
+	}
+	p.trailer.Decode(stream)
+}
+
+// Encode writes the Header to the stream with the encoding of binutils.Marshal.
+func (p *Header) Encode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	stream.PutByte(byte(p.ID))
+	stream.PutLittleUnsignedShort(p.Flags)
+	stream.PutVarInt(int32(p.Version))
+}
+
+// Decode reads the Header from the stream with the encoding of binutils.Unmarshal.
+func (p *Header) Decode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	p.ID = PacketID(stream.GetByte())
+	p.Flags = stream.GetLittleUnsignedShort()
+	p.Version = int(stream.GetVarInt())
+}
+
+// Encode writes the Item to the stream with the encoding of binutils.Marshal.
+func (p *Item) Encode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	stream.PutShort(p.ID)
+	stream.PutByte(p.Count)
+	stream.PutUnsignedShort(uint16(len(p.Name)))
+	stream.PutBytes([]byte(p.Name))
+}
+
+// Decode reads the Item from the stream with the encoding of binutils.Unmarshal.
+func (p *Item) Decode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	p.ID = stream.GetShort()
+	p.Count = stream.GetByte()
+	offset2 := stream.Offset
+	n1 := int(stream.GetUnsignedShort())
+	if n1 < 0 || n1 > stream.Remaining() {
+		if stream.Err() == nil {
+			stream.SetError(&binutils.StreamError{Op: "GetStruct", Offset: offset2, Err: binutils.ErrUnexpectedEOF})
+		}
+		return
+	}
+	p.Name = string(stream.Get(n1))
+}
+
+// Encode writes the trailer to the stream with the encoding of binutils.Marshal.
+func (p *trailer) Encode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	stream.PutUnsignedInt(p.Checksum)
+}
+
+// Decode reads the trailer from the stream with the encoding of binutils.Unmarshal.
+func (p *trailer) Decode(stream *binutils.Stream) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(binutils.BigEndian)
+	p.Checksum = stream.GetUnsignedInt()
+}
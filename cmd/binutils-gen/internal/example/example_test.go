@@ -0,0 +1,58 @@
+package example
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/irmine/binutils"
+	"gotest.tools/assert"
+)
+
+var packet = LoginPacket{
+	Header:   Header{ID: 0x01, Flags: 0x0102, Version: -3},
+	Name:     "steve",
+	Enabled:  true,
+	Position: [3]float32{1.5, -2, 64},
+	Yaw:      90,
+	Scores:   []int64{1, -1, 300},
+	Items:    []Item{{ID: 1, Count: 64, Name: "stone"}, {ID: -1}},
+	Payload:  []byte{0xde, 0xad},
+	Sequence: 0x030201,
+	Delta:    -3,
+	Small:    -2,
+	Tags:     [][]string{{"a", "b"}, {}},
+	trailer:  trailer{Checksum: 0xcafe},
+}
+
+func TestGeneratedTruncated(t *testing.T) {
+	stream := binutils.NewStream()
+	packet.Encode(stream)
+	for _, n := range []int{3, 6, 30, len(stream.Buffer) - 1} {
+		var decoded LoginPacket
+		truncated := &binutils.Stream{Buffer: stream.Buffer[:n]}
+		decoded.Decode(truncated)
+		assert.Assert(t, errors.Is(truncated.Err(), binutils.ErrUnexpectedEOF), n)
+	}
+}
+
+func TestGeneratedAllocations(t *testing.T) {
+	stream := binutils.NewStream()
+	packet.Encode(stream)
+	allocs := testing.AllocsPerRun(100, func() {
+		stream.Reset()
+		packet.Encode(stream)
+	})
+	assert.Equal(t, allocs, float64(0))
+}
+
+func TestGeneratedWriteLimit(t *testing.T) {
+	stream := binutils.NewStream()
+	packet.Encode(stream)
+	for n := 1; n < len(stream.Buffer); n++ {
+		limited := binutils.NewStream()
+		limited.SetWriteLimit(n)
+		packet.Encode(limited)
+		assert.Assert(t, errors.Is(limited.Err(), binutils.ErrLimitExceeded), n)
+		assert.Assert(t, len(limited.Buffer) <= n, n)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !tinygo && !binutils_minimal
+
+package example
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/irmine/binutils"
+	"gotest.tools/assert"
+)
+
+func TestGeneratedMatchesMarshal(t *testing.T) {
+	expected, err := binutils.Marshal(&packet)
+	assert.NilError(t, err)
+
+	stream := binutils.NewStream()
+	stream.SetEndianness(binutils.LittleEndian)
+	packet.Encode(stream)
+	assert.DeepEqual(t, stream.Buffer, expected)
+	assert.Equal(t, stream.GetEndianness(), binutils.LittleEndian)
+
+	var decoded, unmarshaled LoginPacket
+	decoded.Decode(stream)
+	assert.NilError(t, stream.Err())
+	assert.NilError(t, binutils.Unmarshal(expected, &unmarshaled))
+	assert.DeepEqual(t, decoded, unmarshaled, cmp.AllowUnexported(LoginPacket{}))
+	assert.DeepEqual(t, decoded, packet, cmp.AllowUnexported(LoginPacket{}))
+}
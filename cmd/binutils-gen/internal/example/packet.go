@@ -0,0 +1,45 @@
+// Package example holds packets with generated methods, testing that binutils-gen matches binutils.Marshal.
+package example
+
+//go:generate go run github.com/irmine/binutils/cmd/binutils-gen -type LoginPacket
+
+// PacketID is the ID of a packet.
+type PacketID uint8
+
+// Header is the header of a packet.
+type Header struct {
+	ID      PacketID
+	Flags   uint16 `bin:",le"`
+	Version int    `bin:"varint"`
+}
+
+// Item is an item in an inventory.
+type Item struct {
+	ID    int16
+	Count byte
+	Name  string `bin:",prefix=uint16"`
+}
+
+// trailer is embedded in packets, its fields are encoded in place.
+type trailer struct {
+	Checksum uint32
+}
+
+// LoginPacket is a packet using every supported field type.
+type LoginPacket struct {
+	Header   Header
+	Name     string
+	Enabled  bool
+	Position [3]float32 `bin:",le"`
+	Yaw      float64    `bin:"float32"`
+	Scores   []int64    `bin:"varlong"`
+	Items    []Item
+	Payload  []byte `bin:",prefix=uint32"`
+	Sequence uint32 `bin:"triad,le"`
+	Delta    int32  `bin:"int24"`
+	Small    int16  `bin:"int8"`
+	Tags     [][]string
+	Cache    map[string]int `bin:"-"`
+	hidden   int
+	trailer
+}
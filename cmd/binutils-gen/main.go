@@ -0,0 +1,39 @@
+// Command binutils-gen generates Encode and Decode methods for structs, implementing binutils.Serializable
+// without reflection. The generated methods produce the same encoding as binutils.Marshal, honouring the wire type,
+// byte order and prefix options of `bin` struct tags. Fields tagged `bin:"-"` are skipped, the sizeof, crc32 and compute options are not supported.
+//
+// Struct fields may be booleans, numbers, strings, byte slices, named types of these, structs declared in the same
+// package, and slices and arrays of these. Structs used as fields get methods generated as well.
+//
+// Usage, typically from a go:generate directive in the package declaring the types:
+//
+//	//go:generate binutils-gen -type LoginPacket,TextPacket
+//	binutils-gen [-dir dir] [-o file] -type T1,T2
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package declaring the types")
+	output := flag.String("o", "binutils_gen.go", "output file, relative to the package directory")
+	types := flag.String("type", "", "comma separated names of the struct types")
+	flag.Parse()
+	if *types == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	source, err := Generate(*dir, strings.Split(*types, ","), filepath.Base(*output))
+	if err == nil {
+		err = os.WriteFile(filepath.Join(*dir, *output), source, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "binutils-gen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+// Command binutils-vectors writes the conformance test vectors of binutils as vectors.json and vectors.hex
+// into a directory, so that implementations in other languages can check byte level compatibility.
+//
+// Usage:
+//
+//	binutils-vectors [-o dir]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/irmine/binutils/conformance"
+)
+
+func main() {
+	dir := flag.String("o", ".", "output directory")
+	flag.Parse()
+	if err := run(*dir); err != nil {
+		fmt.Fprintln(os.Stderr, "binutils-vectors:", err)
+		os.Exit(1)
+	}
+}
+
+// run writes the vector files into the directory.
+func run(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, write := range map[string]func(io.Writer) error{
+		"vectors.json": conformance.WriteJSON,
+		"vectors.hex":  conformance.WriteHex,
+	} {
+		if err := writeFile(filepath.Join(dir, name), write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile creates the file and writes it with the function.
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,493 @@
+// Package columnar implements a minimal columnar file format on binutils streams, for embedded analytics
+// where Parquet is too heavy.
+//
+// Rows are grouped into row groups, which store the values of each column in a separate chunk, so that a column is read
+// without decoding the others. Each chunk is encoded plain, run length encoded or dictionary encoded, whichever suits its values.
+// A file is laid out as follows, with the footer length little endian and all other integers as varints:
+//
+//	file:      "BUCO", chunks, footer, uint32 footer length, "BUCO"
+//	footer:    column count, (name, type) per column, row group count,
+//	           (row count, (offset, size, encoding) per column) per row group
+//	plain:     values
+//	rle:       (run length, value) per run
+//	dictionary: dictionary size, values, (run length, index) per run of indices
+//
+// Int64 values are encoded as zigzag varlongs, Float64 values as little endian doubles and String values length prefixed.
+package columnar
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/irmine/binutils"
+)
+
+// magic starts and ends every file.
+const magic = "BUCO"
+
+// DefaultRowGroupSize is the number of rows per row group of writers created with a row group size of 0 or less.
+const DefaultRowGroupSize = 8192
+
+// DefaultMaxRows is the maximum number of values a reader decodes for a column by default.
+const DefaultMaxRows = 1 << 24
+
+// ErrInvalidFile is returned when a file does not start and end with the magic or has an invalid footer.
+var ErrInvalidFile = errors.New("columnar: invalid file")
+
+// Type is the type of the values of a column.
+type Type byte
+
+const (
+	Int64 Type = iota
+	Float64
+	String
+)
+
+// String returns the name of the type.
+func (t Type) String() string {
+	switch t {
+	case Int64:
+		return "int64"
+	case Float64:
+		return "float64"
+	case String:
+		return "string"
+	}
+	return fmt.Sprintf("Type(%d)", byte(t))
+}
+
+// Encoding is the encoding of a column chunk.
+type Encoding byte
+
+const (
+	Plain Encoding = iota
+	RLE
+	Dictionary
+)
+
+// Column is a named, typed column of a schema.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Schema is the list of columns of a file.
+type Schema []Column
+
+// index returns the index of the column with the given name, or -1 if there is none.
+func (schema Schema) index(name string) int {
+	for i, column := range schema {
+		if column.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// chunk is the location and encoding of a column chunk.
+type chunk struct {
+	offset   uint64
+	size     uint64
+	encoding Encoding
+}
+
+// rowGroup is a row group in the footer.
+type rowGroup struct {
+	rows   uint64
+	chunks []chunk
+}
+
+// putValue writes a value of the type.
+func putValue(stream *binutils.Stream, t Type, v interface{}) {
+	switch t {
+	case Int64:
+		stream.PutVarLong(v.(int64))
+	case Float64:
+		stream.PutLittleDouble(v.(float64))
+	default:
+		stream.PutString(v.(string))
+	}
+}
+
+// getValue reads a value of the type.
+func getValue(stream *binutils.Stream, t Type) interface{} {
+	switch t {
+	case Int64:
+		return stream.GetVarLong()
+	case Float64:
+		return stream.GetLittleDouble()
+	default:
+		return stream.GetString()
+	}
+}
+
+// runs returns the number of runs of equal values.
+func runs(values []interface{}) int {
+	n := 0
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			n++
+		}
+	}
+	return n
+}
+
+// encodeChunk writes the values with the encoding that suits them, and returns the encoding.
+// Values are run length encoded if they have few runs, and dictionary encoded if they have few distinct values.
+func encodeChunk(stream *binutils.Stream, t Type, values []interface{}) Encoding {
+	if runs(values)*4 <= len(values) {
+		putRuns(stream, values, func(v interface{}) { putValue(stream, t, v) })
+		return RLE
+	}
+	indices := make(map[interface{}]uint64)
+	var dictionary []interface{}
+	encoded := make([]interface{}, len(values))
+	for i, v := range values {
+		index, ok := indices[v]
+		if !ok {
+			index = uint64(len(dictionary))
+			indices[v] = index
+			dictionary = append(dictionary, v)
+		}
+		encoded[i] = index
+	}
+	if len(dictionary)*2 > len(values) {
+		for _, v := range values {
+			putValue(stream, t, v)
+		}
+		return Plain
+	}
+	stream.PutUnsignedVarLong(uint64(len(dictionary)))
+	for _, v := range dictionary {
+		putValue(stream, t, v)
+	}
+	putRuns(stream, encoded, func(v interface{}) { stream.PutUnsignedVarLong(v.(uint64)) })
+	return Dictionary
+}
+
+// putRuns writes the runs of equal values as their length followed by the value.
+func putRuns(stream *binutils.Stream, values []interface{}, put func(v interface{})) {
+	for start := 0; start < len(values); {
+		end := start + 1
+		for end < len(values) && values[end] == values[start] {
+			end++
+		}
+		stream.PutUnsignedVarLong(uint64(end - start))
+		put(values[start])
+		start = end
+	}
+}
+
+// decodeChunk reads the values of a chunk with the encoding.
+func decodeChunk(stream *binutils.Stream, t Type, encoding Encoding, rows int) ([]interface{}, error) {
+	values := make([]interface{}, 0, min(rows, stream.Remaining()))
+	switch encoding {
+	case Plain:
+		// Every value takes at least a byte.
+		if rows > stream.Remaining() {
+			return nil, ErrInvalidFile
+		}
+		for i := 0; i < rows && stream.Err() == nil; i++ {
+			values = append(values, getValue(stream, t))
+		}
+	case RLE:
+		values = getRuns(stream, values, rows, func() interface{} { return getValue(stream, t) })
+	case Dictionary:
+		size := stream.GetUnsignedVarLong()
+		if size > uint64(stream.Remaining()) {
+			return nil, ErrInvalidFile
+		}
+		dictionary := make([]interface{}, size)
+		for i := range dictionary {
+			dictionary[i] = getValue(stream, t)
+		}
+		values = getRuns(stream, values, rows, func() interface{} {
+			index := stream.GetUnsignedVarLong()
+			if index >= size {
+				stream.SetError(ErrInvalidFile)
+				return nil
+			}
+			return dictionary[index]
+		})
+	default:
+		return nil, ErrInvalidFile
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if len(values) != rows {
+		return nil, ErrInvalidFile
+	}
+	return values, nil
+}
+
+// getRuns reads runs of equal values written by putRuns until rows values are read.
+func getRuns(stream *binutils.Stream, values []interface{}, rows int, get func() interface{}) []interface{} {
+	for len(values) < rows && stream.Err() == nil {
+		n := stream.GetUnsignedVarLong()
+		v := get()
+		if n == 0 || n > uint64(rows-len(values)) {
+			stream.SetError(ErrInvalidFile)
+			break
+		}
+		for ; n > 0; n-- {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Writer writes rows to a columnar file.
+type Writer struct {
+	writer       io.Writer
+	schema       Schema
+	rowGroupSize int
+	columns      [][]interface{}
+	groups       []rowGroup
+	offset       uint64
+	stream       *binutils.Stream
+}
+
+// NewWriter returns a writer writing a file with the schema to the writer, with row groups of rowGroupSize rows.
+func NewWriter(writer io.Writer, schema Schema, rowGroupSize int) (*Writer, error) {
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	for _, column := range schema {
+		if column.Type > String {
+			return nil, fmt.Errorf("columnar: column %s has invalid type %s", column.Name, column.Type)
+		}
+	}
+	if _, err := io.WriteString(writer, magic); err != nil {
+		return nil, err
+	}
+	return &Writer{writer: writer, schema: schema, rowGroupSize: rowGroupSize, columns: make([][]interface{}, len(schema)),
+		offset: uint64(len(magic)), stream: binutils.NewStream()}, nil
+}
+
+// Write writes a row with a value per column of the schema: an int64, float64 or string matching the column type.
+// The row group is written once it is full.
+func (writer *Writer) Write(row ...interface{}) error {
+	if len(row) != len(writer.schema) {
+		return fmt.Errorf("columnar: row has %d values, schema has %d columns", len(row), len(writer.schema))
+	}
+	for i, v := range row {
+		var ok bool
+		switch writer.schema[i].Type {
+		case Int64:
+			_, ok = v.(int64)
+		case Float64:
+			_, ok = v.(float64)
+		default:
+			_, ok = v.(string)
+		}
+		if !ok {
+			return fmt.Errorf("columnar: value %v of type %T for column %s of type %s", v, v, writer.schema[i].Name, writer.schema[i].Type)
+		}
+	}
+	for i, v := range row {
+		writer.columns[i] = append(writer.columns[i], v)
+	}
+	if len(writer.columns[0]) >= writer.rowGroupSize {
+		return writer.Flush()
+	}
+	return nil
+}
+
+// Flush writes the current row group, even if it is not full.
+func (writer *Writer) Flush() error {
+	if len(writer.schema) == 0 || len(writer.columns[0]) == 0 {
+		return nil
+	}
+	group := rowGroup{rows: uint64(len(writer.columns[0])), chunks: make([]chunk, len(writer.schema))}
+	for i, column := range writer.schema {
+		writer.stream.Reset()
+		encoding := encodeChunk(writer.stream, column.Type, writer.columns[i])
+		if _, err := writer.writer.Write(writer.stream.Buffer); err != nil {
+			return err
+		}
+		group.chunks[i] = chunk{writer.offset, uint64(len(writer.stream.Buffer)), encoding}
+		writer.offset += uint64(len(writer.stream.Buffer))
+		writer.columns[i] = writer.columns[i][:0]
+	}
+	writer.groups = append(writer.groups, group)
+	return nil
+}
+
+// Close writes the current row group and the footer. The underlying writer is not closed.
+func (writer *Writer) Close() error {
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	stream := writer.stream
+	stream.Reset()
+	stream.PutUnsignedVarLong(uint64(len(writer.schema)))
+	for _, column := range writer.schema {
+		stream.PutString(column.Name)
+		stream.PutByte(byte(column.Type))
+	}
+	stream.PutUnsignedVarLong(uint64(len(writer.groups)))
+	for _, group := range writer.groups {
+		stream.PutUnsignedVarLong(group.rows)
+		for _, chunk := range group.chunks {
+			stream.PutUnsignedVarLong(chunk.offset)
+			stream.PutUnsignedVarLong(chunk.size)
+			stream.PutByte(byte(chunk.encoding))
+		}
+	}
+	stream.PutLittleUnsignedInt(uint32(len(stream.Buffer)))
+	stream.PutBytes([]byte(magic))
+	_, err := writer.writer.Write(stream.Buffer)
+	return err
+}
+
+// Reader reads columns from a columnar file.
+type Reader struct {
+	reader  io.ReaderAt
+	schema  Schema
+	groups  []rowGroup
+	rows    int
+	maxRows int
+}
+
+// NewReader returns a reader of the file of the given size read from the reader.
+func NewReader(reader io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, ErrInvalidFile
+	}
+	header := make([]byte, len(magic))
+	if _, err := reader.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	trailer := make([]byte, 4+len(magic))
+	if _, err := reader.ReadAt(trailer, size-int64(len(trailer))); err != nil {
+		return nil, err
+	}
+	stream := &binutils.Stream{Buffer: trailer}
+	footerSize := int64(stream.GetLittleUnsignedInt())
+	if string(header) != magic || string(stream.Get(len(magic))) != magic || footerSize > size-int64(2*len(magic)+4) {
+		return nil, ErrInvalidFile
+	}
+	footer := make([]byte, footerSize)
+	if _, err := reader.ReadAt(footer, size-int64(len(trailer))-footerSize); err != nil {
+		return nil, err
+	}
+	chunksEnd := uint64(size - int64(len(trailer)) - footerSize)
+
+	stream = &binutils.Stream{Buffer: footer}
+	r := &Reader{reader: reader, maxRows: DefaultMaxRows}
+	columns := stream.GetUnsignedVarLong()
+	if columns > uint64(len(footer)) {
+		return nil, ErrInvalidFile
+	}
+	r.schema = make(Schema, columns)
+	for i := range r.schema {
+		r.schema[i] = Column{stream.GetString(), Type(stream.GetByte())}
+		if r.schema[i].Type > String {
+			return nil, ErrInvalidFile
+		}
+	}
+	groups := stream.GetUnsignedVarLong()
+	if groups > uint64(len(footer)) {
+		return nil, ErrInvalidFile
+	}
+	r.groups = make([]rowGroup, groups)
+	for i := range r.groups {
+		group := rowGroup{rows: stream.GetUnsignedVarLong(), chunks: make([]chunk, columns)}
+		for j := range group.chunks {
+			c := chunk{stream.GetUnsignedVarLong(), stream.GetUnsignedVarLong(), Encoding(stream.GetByte())}
+			// The sum of offset and size could overflow.
+			if c.size > chunksEnd || c.offset > chunksEnd-c.size {
+				return nil, ErrInvalidFile
+			}
+			group.chunks[j] = c
+		}
+		if stream.Err() != nil {
+			break
+		}
+		if group.rows > uint64(math.MaxInt32-r.rows) {
+			return nil, ErrInvalidFile
+		}
+		r.groups[i] = group
+		r.rows += int(group.rows)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("columnar: footer: %w", err)
+	}
+	return r, nil
+}
+
+// SetMaxRows sets the maximum number of values decoded for a column, as run length encoded chunks expand
+// few bytes into many values. Columns of more rows are rejected with an error matching binutils.ErrLimitExceeded.
+// The default is DefaultMaxRows.
+func (reader *Reader) SetMaxRows(rows int) {
+	reader.maxRows = rows
+}
+
+// Schema returns the schema of the file.
+func (reader *Reader) Schema() Schema {
+	return reader.schema
+}
+
+// Rows returns the number of rows in the file.
+func (reader *Reader) Rows() int {
+	return reader.rows
+}
+
+// column reads the values of the column with the given name and type, reading only its chunks.
+func (reader *Reader) column(name string, t Type) ([]interface{}, error) {
+	i := reader.schema.index(name)
+	if i < 0 {
+		return nil, fmt.Errorf("columnar: unknown column %s", name)
+	}
+	if reader.schema[i].Type != t {
+		return nil, fmt.Errorf("columnar: column %s has type %s, not %s", name, reader.schema[i].Type, t)
+	}
+	if reader.rows > reader.maxRows {
+		return nil, fmt.Errorf("columnar: column %s: %w", name, &binutils.LengthError{Length: reader.rows, Limit: reader.maxRows})
+	}
+	var values []interface{}
+	for _, group := range reader.groups {
+		chunk := group.chunks[i]
+		buffer := make([]byte, chunk.size)
+		if _, err := reader.reader.ReadAt(buffer, int64(chunk.offset)); err != nil {
+			return nil, err
+		}
+		decoded, err := decodeChunk(&binutils.Stream{Buffer: buffer}, t, chunk.encoding, int(group.rows))
+		if err != nil {
+			return nil, fmt.Errorf("columnar: column %s at offset %d: %w", name, chunk.offset, err)
+		}
+		values = append(values, decoded...)
+	}
+	return values, nil
+}
+
+// Int64s returns the values of an Int64 column.
+func (reader *Reader) Int64s(name string) ([]int64, error) {
+	return typedColumn[int64](reader, name, Int64)
+}
+
+// Float64s returns the values of a Float64 column.
+func (reader *Reader) Float64s(name string) ([]float64, error) {
+	return typedColumn[float64](reader, name, Float64)
+}
+
+// Strings returns the values of a String column.
+func (reader *Reader) Strings(name string) ([]string, error) {
+	return typedColumn[string](reader, name, String)
+}
+
+// typedColumn returns the values of a column as a slice of T.
+func typedColumn[T int64 | float64 | string](reader *Reader, name string, t Type) ([]T, error) {
+	values, err := reader.column(name, t)
+	if err != nil {
+		return nil, err
+	}
+	typed := make([]T, len(values))
+	for i, v := range values {
+		typed[i] = v.(T)
+	}
+	return typed, nil
+}
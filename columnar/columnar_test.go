@@ -0,0 +1,151 @@
+package columnar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/irmine/binutils"
+	"gotest.tools/assert"
+)
+
+func TestColumnar(t *testing.T) {
+	var buffer bytes.Buffer
+	schema := Schema{{"id", Int64}, {"score", Float64}, {"country", String}, {"level", Int64}}
+	writer, err := NewWriter(&buffer, schema, 64)
+	assert.NilError(t, err)
+	countries := []string{"nl", "de", "uk"}
+	for i := 0; i < 100; i++ {
+		assert.NilError(t, writer.Write(int64(i-50), float64(i)/4, countries[i%3], int64(i/40)))
+	}
+	assert.ErrorContains(t, writer.Write(int64(1), 2, "x", int64(0)), "column score")
+	assert.ErrorContains(t, writer.Write(int64(1)), "1 values")
+	assert.NilError(t, writer.Close())
+
+	reader, err := NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, reader.Schema(), schema)
+	assert.Equal(t, reader.Rows(), 100)
+	assert.Equal(t, len(reader.groups), 2)
+	assert.Equal(t, reader.groups[0].chunks[0].encoding, Plain)
+	assert.Equal(t, reader.groups[0].chunks[2].encoding, Dictionary)
+	assert.Equal(t, reader.groups[0].chunks[3].encoding, RLE)
+
+	ids, err := reader.Int64s("id")
+	assert.NilError(t, err)
+	scores, err := reader.Float64s("score")
+	assert.NilError(t, err)
+	names, err := reader.Strings("country")
+	assert.NilError(t, err)
+	levels, err := reader.Int64s("level")
+	assert.NilError(t, err)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, ids[i], int64(i-50), fmt.Sprint(i))
+		assert.Equal(t, scores[i], float64(i)/4)
+		assert.Equal(t, names[i], countries[i%3])
+		assert.Equal(t, levels[i], int64(i/40))
+	}
+
+	_, err = reader.Strings("id")
+	assert.ErrorContains(t, err, "has type int64")
+	_, err = reader.Int64s("missing")
+	assert.ErrorContains(t, err, "unknown column")
+}
+
+func TestColumnarCorrupt(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(&buffer, Schema{{"name", String}}, 0)
+	assert.NilError(t, err)
+	assert.NilError(t, writer.Write("steve"))
+	assert.NilError(t, writer.Close())
+
+	data := buffer.Bytes()
+	data[4] = 0xff
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	_, err = reader.Strings("name")
+	assert.Assert(t, errors.Is(err, binutils.ErrUnexpectedEOF))
+
+	_, err = NewReader(bytes.NewReader(data[1:]), int64(len(data)-1))
+	assert.Assert(t, errors.Is(err, ErrInvalidFile))
+	_, err = NewReader(bytes.NewReader(data[:len(data)-1]), int64(len(data)-1))
+	assert.Assert(t, errors.Is(err, ErrInvalidFile))
+}
+
+func TestColumnarHostileChunk(t *testing.T) {
+	// The offset and size of the chunk add up to 0, wrapping around.
+	data := testFile(1<<63, 1<<63, 0)
+	_, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Assert(t, errors.Is(err, ErrInvalidFile))
+
+	// The footer ends within the chunk of the group.
+	data = testFile(0, 0, 2)
+	_, err = NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Assert(t, errors.Is(err, binutils.ErrUnexpectedEOF))
+}
+
+func TestColumnarHostileRows(t *testing.T) {
+	data := rowsFile(1<<63, Plain, []byte{2})
+	_, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Assert(t, errors.Is(err, ErrInvalidFile))
+
+	// A single run of a chunk of 6 bytes expands into a billion values.
+	run := binutils.NewStream()
+	run.PutUnsignedVarLong(1 << 30)
+	run.PutVarLong(1)
+	data = rowsFile(1<<30, RLE, run.Buffer)
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	_, err = reader.Int64s("id")
+	assert.Assert(t, errors.Is(err, binutils.ErrLimitExceeded))
+
+	data = rowsFile(2, Plain, []byte{2})
+	reader, err = NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	_, err = reader.Int64s("id")
+	assert.Assert(t, errors.Is(err, ErrInvalidFile))
+}
+
+// rowsFile returns a file of a single group of the given amount of rows, with an Int64 column of a single chunk.
+func rowsFile(rows uint64, encoding Encoding, chunk []byte) []byte {
+	footer := binutils.NewStream()
+	footer.PutUnsignedVarLong(1)
+	footer.PutString("id")
+	footer.PutByte(byte(Int64))
+	footer.PutUnsignedVarLong(1)
+	footer.PutUnsignedVarLong(rows)
+	footer.PutUnsignedVarLong(uint64(len(magic)))
+	footer.PutUnsignedVarLong(uint64(len(chunk)))
+	footer.PutByte(byte(encoding))
+
+	stream := binutils.NewStream()
+	stream.PutBytes([]byte(magic))
+	stream.PutBytes(chunk)
+	stream.PutBytes(footer.Buffer)
+	stream.PutLittleUnsignedInt(uint32(len(footer.Buffer)))
+	stream.PutBytes([]byte(magic))
+	return stream.Buffer
+}
+
+// testFile returns a file of a single group with a chunk of the given offset and size,
+// with the given amount of bytes cut off the end of the footer.
+func testFile(offset, size uint64, cut int) []byte {
+	footer := binutils.NewStream()
+	footer.PutUnsignedVarLong(1)
+	footer.PutString("name")
+	footer.PutByte(byte(String))
+	footer.PutUnsignedVarLong(1)
+	footer.PutUnsignedVarLong(1)
+	footer.PutUnsignedVarLong(offset)
+	footer.PutUnsignedVarLong(size)
+	footer.PutByte(byte(Plain))
+	footer.Buffer = footer.Buffer[:len(footer.Buffer)-cut]
+
+	stream := binutils.NewStream()
+	stream.PutBytes([]byte(magic))
+	stream.PutBytes(footer.Buffer)
+	stream.PutLittleUnsignedInt(uint32(len(footer.Buffer)))
+	stream.PutBytes([]byte(magic))
+	return stream.Buffer
+}
@@ -0,0 +1,132 @@
+package binutils
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Codec is a configuration for encoding values of type T, such as varints or fixed size integers,
+// with or without compression, compared with another configuration by Compare.
+type Codec[T any] struct {
+	// Name identifies the codec in reports.
+	Name string
+	// Encode writes a value to the stream.
+	Encode func(stream *Stream, v T)
+	// Decode reads a value from the stream. If nil, decoding is not measured.
+	Decode func(stream *Stream) T
+	// Pipeline transforms the encoding of every value, such as compressing it.
+	Pipeline Pipeline
+}
+
+// CodecResult is the size and time of encoding and decoding values with a codec.
+type CodecResult struct {
+	Name       string
+	Values     int
+	Bytes      int
+	EncodeTime time.Duration
+	DecodeTime time.Duration
+}
+
+// Comparison is the result of Compare, comparing codec B against codec A.
+type Comparison struct {
+	A CodecResult
+	B CodecResult
+}
+
+// Compare encodes the values with both codecs, and decodes them again with codecs that have a Decode function,
+// measuring the total encoded size and time taken by each. Every value is encoded on its own, like a packet.
+// Errors set on the stream or returned by pipelines are returned, as is a decode that leaves bytes unread.
+func Compare[T any](a, b Codec[T], values []T) (Comparison, error) {
+	resultA, err := measureCodec(a, values)
+	if err != nil {
+		return Comparison{}, err
+	}
+	resultB, err := measureCodec(b, values)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return Comparison{resultA, resultB}, nil
+}
+
+// measureCodec encodes and decodes the values with the codec.
+func measureCodec[T any](codec Codec[T], values []T) (CodecResult, error) {
+	result := CodecResult{Name: codec.Name, Values: len(values)}
+	encoded := make([][]byte, len(values))
+	stream := NewStream()
+	start := time.Now()
+	for i, v := range values {
+		stream.Reset()
+		codec.Encode(stream, v)
+		if err := stream.Err(); err != nil {
+			return result, fmt.Errorf("binutils: codec %s: value %d: %w", codec.Name, i, err)
+		}
+		data, err := codec.Pipeline.Encode(append([]byte(nil), stream.Buffer...))
+		if err != nil {
+			return result, fmt.Errorf("binutils: codec %s: value %d: %w", codec.Name, i, err)
+		}
+		encoded[i] = data
+		result.Bytes += len(data)
+	}
+	result.EncodeTime = time.Since(start)
+
+	if codec.Decode == nil {
+		return result, nil
+	}
+	start = time.Now()
+	for i, data := range encoded {
+		data, err := codec.Pipeline.Decode(data)
+		if err == nil {
+			stream := &Stream{Buffer: data}
+			codec.Decode(stream)
+			if err = stream.Err(); err == nil && stream.Remaining() > 0 {
+				err = fmt.Errorf("%d bytes left unread", stream.Remaining())
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("binutils: codec %s: value %d: %w", codec.Name, i, err)
+		}
+	}
+	result.DecodeTime = time.Since(start)
+	return result, nil
+}
+
+// SizeChange returns the relative change in encoded size of B from A, such as -0.25 if B is a quarter smaller.
+func (comparison Comparison) SizeChange() float64 {
+	return change(float64(comparison.A.Bytes), float64(comparison.B.Bytes))
+}
+
+// EncodeTimeChange returns the relative change in encoding time of B from A.
+func (comparison Comparison) EncodeTimeChange() float64 {
+	return change(float64(comparison.A.EncodeTime), float64(comparison.B.EncodeTime))
+}
+
+// DecodeTimeChange returns the relative change in decoding time of B from A.
+func (comparison Comparison) DecodeTimeChange() float64 {
+	return change(float64(comparison.A.DecodeTime), float64(comparison.B.DecodeTime))
+}
+
+// change returns the relative change from a to b, or 0 if a is 0.
+func change(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	return (b - a) / a
+}
+
+// String returns a table of the sizes and times of both codecs and the change between them.
+func (comparison Comparison) String() string {
+	var report strings.Builder
+	w := tabwriter.NewWriter(&report, 0, 4, 2, ' ', 0)
+	a, b := comparison.A, comparison.B
+	fmt.Fprintf(w, "\t%s\t%s\tchange\n", a.Name, b.Name)
+	fmt.Fprintf(w, "bytes\t%d\t%d\t%+.1f%%\n", a.Bytes, b.Bytes, 100*comparison.SizeChange())
+	if a.Values > 0 {
+		fmt.Fprintf(w, "bytes/value\t%.1f\t%.1f\t\n", float64(a.Bytes)/float64(a.Values), float64(b.Bytes)/float64(b.Values))
+	}
+	fmt.Fprintf(w, "encode\t%v\t%v\t%+.1f%%\n", a.EncodeTime, b.EncodeTime, 100*comparison.EncodeTimeChange())
+	fmt.Fprintf(w, "decode\t%v\t%v\t%+.1f%%\n", a.DecodeTime, b.DecodeTime, 100*comparison.DecodeTimeChange())
+	w.Flush()
+	return report.String()
+}
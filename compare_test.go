@@ -0,0 +1,45 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	values := make([]uint32, 100)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	fixed := Codec[uint32]{
+		Name:   "fixed",
+		Encode: func(stream *Stream, v uint32) { stream.PutUnsignedInt(v) },
+		Decode: func(stream *Stream) uint32 { return stream.GetUnsignedInt() },
+	}
+	varint := Codec[uint32]{
+		Name:   "varint",
+		Encode: func(stream *Stream, v uint32) { stream.PutUnsignedVarInt(v) },
+		Decode: func(stream *Stream) uint32 { return stream.GetUnsignedVarInt() },
+	}
+	comparison, err := Compare(fixed, varint, values)
+	assert.NilError(t, err)
+	assert.Equal(t, comparison.A.Bytes, 400)
+	assert.Equal(t, comparison.B.Bytes, 100)
+	assert.Equal(t, comparison.B.Values, 100)
+	assert.Equal(t, comparison.SizeChange(), -0.75)
+	assert.Assert(t, strings.Contains(comparison.String(), "-75.0%"), comparison.String())
+
+	compressed := fixed
+	compressed.Name = "zlib"
+	compressed.Pipeline = NewPipeline(ZlibTransformer(9))
+	comparison, err = Compare(fixed, compressed, values)
+	assert.NilError(t, err)
+	assert.Assert(t, comparison.B.Bytes > comparison.A.Bytes)
+
+	// A decoder reading less than was encoded is reported.
+	short := fixed
+	short.Name = "short"
+	short.Decode = func(stream *Stream) uint32 { return uint32(stream.GetUnsignedShort()) }
+	_, err = Compare(fixed, short, values)
+	assert.ErrorContains(t, err, "codec short: value 0: 2 bytes left unread")
+}
@@ -0,0 +1,227 @@
+package binutils
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// CompressionType is a compression algorithm.
+type CompressionType byte
+
+const (
+	CompressionZlib CompressionType = iota
+	CompressionGzip
+	// CompressionDeflate is raw deflate without header, as used by Bedrock Edition batches.
+	CompressionDeflate
+	// CompressionLZ4 must be registered with RegisterCompression before use.
+	CompressionLZ4
+)
+
+// compressionNames holds the names of the compression types.
+var compressionNames = map[CompressionType]string{
+	CompressionZlib:    "zlib",
+	CompressionGzip:    "gzip",
+	CompressionDeflate: "deflate",
+	CompressionLZ4:     "lz4",
+}
+
+// String returns the name of the compression type.
+func (algo CompressionType) String() string {
+	if name, ok := compressionNames[algo]; ok {
+		return name
+	}
+	return fmt.Sprintf("compression %d", byte(algo))
+}
+
+var (
+	compressionMutex sync.RWMutex
+	compressions     = map[CompressionType]Transformer{
+		CompressionZlib:    ZlibTransformer(zlib.DefaultCompression),
+		CompressionGzip:    GzipTransformer(gzip.DefaultCompression),
+		CompressionDeflate: DeflateTransformer(flate.DefaultCompression),
+	}
+)
+
+// RegisterCompression registers the transformer compressing and decompressing data for the compression type,
+// replacing any transformer registered before.
+// Compression types not provided by the standard library, such as LZ4, are registered this way.
+// A nil transformer unregisters the compression type.
+func RegisterCompression(algo CompressionType, transformer Transformer) {
+	compressionMutex.Lock()
+	defer compressionMutex.Unlock()
+	if transformer == nil {
+		delete(compressions, algo)
+		return
+	}
+	compressions[algo] = transformer
+}
+
+// compression returns the transformer of the compression type.
+func compression(algo CompressionType) (Transformer, error) {
+	compressionMutex.RLock()
+	defer compressionMutex.RUnlock()
+	transformer, ok := compressions[algo]
+	if !ok {
+		return nil, fmt.Errorf("%s is not registered", algo)
+	}
+	return transformer, nil
+}
+
+// GzipTransformer returns a transformer compressing data with gzip at the given level.
+func GzipTransformer(level int) Transformer {
+	return compressor{func(data []byte) ([]byte, error) {
+		var buffer bytes.Buffer
+		writer, err := gzip.NewWriterLevel(&buffer, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}, func(reader io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(reader)
+	}}
+}
+
+// DeflateTransformer returns a transformer compressing data with raw deflate at the given level.
+func DeflateTransformer(level int) Transformer {
+	return compressor{func(data []byte) ([]byte, error) {
+		var buffer bytes.Buffer
+		writer, err := flate.NewWriter(&buffer, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}, func(reader io.Reader) (io.ReadCloser, error) {
+		return flate.NewReader(reader), nil
+	}}
+}
+
+// PutCompressed writes the data compressed with the algorithm, prefixed with its compressed length
+// as the string prefix type. A compression error is set on the stream.
+func (stream *Stream) PutCompressed(data []byte, algo CompressionType) {
+	if !stream.writable("PutCompressed", 0) {
+		return
+	}
+	compressed, err := compress(algo, data)
+	if err != nil {
+		stream.SetError(&StreamError{"PutCompressed", len(stream.Buffer), err})
+		return
+	}
+	stream.PutLengthPrefixedBytes(compressed)
+}
+
+// GetCompressed reads data written by PutCompressed and decompresses it with the algorithm.
+// A decompression error is set on the stream.
+func (stream *Stream) GetCompressed(algo CompressionType) []byte {
+	offset := stream.Offset
+	compressed := stream.getPrefixed("GetCompressed", stream.stringPrefix, stream.maxStringLength)
+	if stream.err != nil {
+		return nil
+	}
+	data, err := stream.decompress(algo, compressed)
+	if err != nil {
+		stream.SetError(&StreamError{"GetCompressed", offset, err})
+		return nil
+	}
+	return data
+}
+
+// Compress replaces the buffer with the buffer compressed with the algorithm, and resets the offset.
+func (stream *Stream) Compress(algo CompressionType) error {
+	if !stream.writable("Compress", 0) {
+		return stream.err
+	}
+	compressed, err := compress(algo, stream.Buffer)
+	if err != nil {
+		return err
+	}
+	if !stream.fits("Compress", len(compressed)) {
+		return stream.err
+	}
+	stream.Buffer = compressed
+	stream.Offset = 0
+	return nil
+}
+
+// Decompress replaces the buffer with the buffer decompressed with the algorithm, and resets the offset.
+func (stream *Stream) Decompress(algo CompressionType) error {
+	if !stream.writable("Decompress", 0) {
+		return stream.err
+	}
+	data, err := stream.decompress(algo, stream.Buffer)
+	if err != nil {
+		return err
+	}
+	if !stream.fits("Decompress", len(data)) {
+		return stream.err
+	}
+	stream.Buffer = data
+	stream.Offset = 0
+	return nil
+}
+
+// compress compresses the data with the algorithm.
+func compress(algo CompressionType, data []byte) ([]byte, error) {
+	transformer, err := compression(algo)
+	if err != nil {
+		return nil, err
+	}
+	return transformer.Encode(data)
+}
+
+// limitedDecoder is implemented by transformers bounding the size of the data they decode while decoding.
+type limitedDecoder interface {
+	DecodeLimit(data []byte, max int) ([]byte, error)
+}
+
+// decompress decompresses the data with the algorithm, returning an error matching ErrLimitExceeded
+// if more than max bytes come out. Transformers registered without DecodeLimit are checked after decoding.
+func decompress(algo CompressionType, data []byte, max int) ([]byte, error) {
+	transformer, err := compression(algo)
+	if err != nil {
+		return nil, err
+	}
+	if decoder, ok := transformer.(limitedDecoder); ok {
+		return decoder.DecodeLimit(data, max)
+	}
+	if data, err = transformer.Decode(data); err == nil && len(data) > max {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes: %w", max, ErrLimitExceeded)
+	}
+	return data, err
+}
+
+// decompress decompresses the data with the algorithm, up to the maximum decompressed size of the stream.
+func (stream *Stream) decompress(algo CompressionType, data []byte) ([]byte, error) {
+	return decompress(algo, data, stream.GetMaxDecompressedSize())
+}
+
+// SetMaxDecompressedSize sets the maximum amount of bytes decompressed by GetCompressed, Decompress and the batch
+// functions. Larger output is rejected with an error matching ErrLimitExceeded.
+// A size of 0 or less uses DefaultMaxDecompressedSize, which is the default.
+func (stream *Stream) SetMaxDecompressedSize(size int) {
+	stream.maxDecompressedSize = size
+}
+
+// GetMaxDecompressedSize returns the maximum amount of bytes decompressed from the stream.
+func (stream *Stream) GetMaxDecompressedSize() int {
+	if stream.maxDecompressedSize <= 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return stream.maxDecompressedSize
+}
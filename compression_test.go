@@ -0,0 +1,88 @@
+package binutils
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"gotest.tools/assert"
+	"io"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("batch"), 100)
+	for _, algo := range []CompressionType{CompressionZlib, CompressionGzip, CompressionDeflate} {
+		stream := NewStream()
+		stream.PutCompressed(data, algo)
+		stream.PutByte(1)
+		assert.Assert(t, len(stream.Buffer) < len(data), algo)
+		assert.DeepEqual(t, stream.GetCompressed(algo), data)
+		assert.Equal(t, stream.GetByte(), byte(1))
+		assert.NilError(t, stream.Err())
+
+		stream = &Stream{Buffer: append([]byte{}, data...)}
+		stream.GetByte()
+		assert.NilError(t, stream.Compress(algo))
+		assert.Equal(t, stream.Offset, 0)
+		assert.NilError(t, stream.Decompress(algo))
+		assert.DeepEqual(t, stream.Buffer, data)
+	}
+}
+
+func TestCompressionErrors(t *testing.T) {
+	stream := NewStream()
+	stream.PutCompressed(nil, CompressionLZ4)
+	assert.Error(t, stream.Err(), "binutils: PutCompressed at offset 0: lz4 is not registered")
+
+	stream = NewStream()
+	stream.PutLengthPrefixedBytes([]byte("not zlib"))
+	assert.Assert(t, stream.GetCompressed(CompressionZlib) == nil)
+	assert.ErrorContains(t, stream.Err(), "binutils: GetCompressed at offset 0: zlib: invalid header")
+
+	reverse := func(data []byte) ([]byte, error) {
+		reversed := make([]byte, len(data))
+		for i, c := range data {
+			reversed[len(data)-1-i] = c
+		}
+		return reversed, nil
+	}
+	RegisterCompression(CompressionLZ4, NewTransformer(reverse, reverse))
+	defer RegisterCompression(CompressionLZ4, nil)
+	stream = NewStream()
+	stream.PutCompressed([]byte{1, 2}, CompressionLZ4)
+	assert.DeepEqual(t, stream.Buffer, b(2, 2, 1))
+	assert.Equal(t, CompressionType(9).String(), "compression 9")
+}
+
+func TestCompressionLimit(t *testing.T) {
+	gzipWriter := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	flateWriter := func(w io.Writer) io.WriteCloser {
+		writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return writer
+	}
+	for _, test := range []struct {
+		transformer Transformer
+		writer      func(io.Writer) io.WriteCloser
+	}{{GzipTransformer(6), gzipWriter}, {DeflateTransformer(6), flateWriter}} {
+		var buffer bytes.Buffer
+		writer := test.writer(&buffer)
+		chunk := make([]byte, 1<<20)
+		for i := 0; i < DefaultMaxDecompressedSize/len(chunk); i++ {
+			writer.Write(chunk)
+		}
+		writer.Close()
+		data, err := test.transformer.Decode(buffer.Bytes())
+		assert.NilError(t, err)
+		assert.Equal(t, len(data), DefaultMaxDecompressedSize)
+
+		buffer.Reset()
+		writer = test.writer(&buffer)
+		for i := 0; i <= DefaultMaxDecompressedSize/len(chunk); i++ {
+			writer.Write(chunk)
+		}
+		writer.Close()
+		_, err = test.transformer.Decode(buffer.Bytes())
+		assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	}
+}
@@ -0,0 +1,42 @@
+package binutils
+
+import (
+	"net"
+)
+
+// PutStream appends the bytes left to read in the other stream, without consuming them.
+func (stream *Stream) PutStream(other *Stream) {
+	if !stream.writable("PutStream", 0) {
+		return
+	}
+	if other.Offset < len(other.Buffer) {
+		stream.PutBytes(other.Buffer[max(other.Offset, 0):])
+	}
+}
+
+// Concat returns a new stream holding the bytes left to read in the streams, in order.
+// The buffer is allocated once with the total size of the streams.
+func Concat(streams ...*Stream) *Stream {
+	size := 0
+	for _, stream := range streams {
+		size += stream.Remaining()
+	}
+	concat := NewStreamWithCapacity(size)
+	for _, stream := range streams {
+		concat.PutStream(stream)
+	}
+	return concat
+}
+
+// Gather returns the bytes left to read in the streams as net.Buffers, sharing their buffers, so that they can be
+// written without copying them into one buffer, using a single writev system call where supported.
+// Like Concat, the streams are not consumed.
+func Gather(streams ...*Stream) net.Buffers {
+	buffers := make(net.Buffers, 0, len(streams))
+	for _, stream := range streams {
+		if stream.Offset < len(stream.Buffer) {
+			buffers = append(buffers, stream.Buffer[max(stream.Offset, 0):])
+		}
+	}
+	return buffers
+}
@@ -0,0 +1,43 @@
+package binutils
+
+import (
+	"bytes"
+	"gotest.tools/assert"
+	"net"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	header := NewStream()
+	header.PutUnsignedShort(0x0102)
+	body := NewStream()
+	body.PutString("abc")
+	body.GetByte()
+
+	stream := NewStream()
+	stream.PutStream(header)
+	stream.PutStream(body)
+	assert.DeepEqual(t, stream.Buffer, b(1, 2, 'a', 'b', 'c'))
+	assert.Equal(t, body.Offset, 1)
+
+	concat := Concat(header, body, NewStream())
+	assert.DeepEqual(t, concat.Buffer, stream.Buffer)
+	assert.Equal(t, cap(concat.Buffer), 5)
+}
+
+func TestGather(t *testing.T) {
+	header := NewStream()
+	header.PutUnsignedShort(0x0102)
+	body := NewStream()
+	body.PutString("abc")
+	body.GetByte()
+
+	buffers := Gather(header, NewStream(), body)
+	assert.DeepEqual(t, buffers, net.Buffers{b(1, 2), b('a', 'b', 'c')})
+	assert.Equal(t, &buffers[0][0], &header.Buffer[0])
+
+	var out bytes.Buffer
+	_, err := buffers.WriteTo(&out)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, out.Bytes(), Concat(header, body).Buffer)
+}
@@ -0,0 +1,250 @@
+// Package conformance provides canonical test vectors of the binutils encodings, and a suite running them
+// against a codec, so that alternative implementations, such as PHP or Java peers wrapped in a Codec,
+// can verify byte level compatibility with this package.
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/irmine/binutils"
+)
+
+// Kind is the encoding of a vector.
+type Kind string
+
+// The kinds of vectors, with the Go type of their values.
+const (
+	Short           Kind = "short"            // int16
+	UnsignedShort   Kind = "unsigned_short"   // uint16
+	Int             Kind = "int"              // int32
+	UnsignedInt     Kind = "unsigned_int"     // uint32
+	Long            Kind = "long"             // int64
+	UnsignedLong    Kind = "unsigned_long"    // uint64
+	Float           Kind = "float"            // float32
+	Double          Kind = "double"           // float64
+	Triad           Kind = "triad"            // uint32
+	VarInt          Kind = "varint"           // int32, zigzag encoded
+	VarLong         Kind = "varlong"          // int64, zigzag encoded
+	UnsignedVarInt  Kind = "unsigned_varint"  // uint32
+	UnsignedVarLong Kind = "unsigned_varlong" // uint64
+	String          Kind = "string"           // string, prefixed with its length as unsigned varint
+)
+
+// Vector is a value with its canonical encoding.
+type Vector struct {
+	Name string
+	Kind Kind
+	// Endian is the byte order of fixed width kinds. It is ignored by varints and strings.
+	Endian  binutils.EndianType
+	Value   interface{}
+	Encoded []byte
+}
+
+// Codec is an implementation of the encodings under test.
+type Codec interface {
+	// Encode returns the encoding of the value of the vector, with the kind and byte order of the vector.
+	Encode(vector Vector) ([]byte, error)
+	// Decode decodes the data with the kind and byte order of the vector, and returns a value of the Go type of the kind.
+	// It returns an error if the data is not fully consumed.
+	Decode(vector Vector, data []byte) (interface{}, error)
+}
+
+// b returns its arguments as a byte slice.
+func b(bytes ...byte) []byte {
+	return bytes
+}
+
+// vectors are the canonical test vectors.
+var vectors = []Vector{
+	{"short/-2", Short, binutils.BigEndian, int16(-2), b(0xff, 0xfe)},
+	{"short/-2", Short, binutils.LittleEndian, int16(-2), b(0xfe, 0xff)},
+	{"unsigned_short/0x0102", UnsignedShort, binutils.BigEndian, uint16(0x0102), b(0x01, 0x02)},
+	{"unsigned_short/0x0102", UnsignedShort, binutils.LittleEndian, uint16(0x0102), b(0x02, 0x01)},
+	{"int/-2", Int, binutils.BigEndian, int32(-2), b(0xff, 0xff, 0xff, 0xfe)},
+	{"int/0x01020304", Int, binutils.LittleEndian, int32(0x01020304), b(0x04, 0x03, 0x02, 0x01)},
+	{"unsigned_int/0xdeadbeef", UnsignedInt, binutils.BigEndian, uint32(0xdeadbeef), b(0xde, 0xad, 0xbe, 0xef)},
+	{"unsigned_int/0xdeadbeef", UnsignedInt, binutils.LittleEndian, uint32(0xdeadbeef), b(0xef, 0xbe, 0xad, 0xde)},
+	{"long/-2", Long, binutils.BigEndian, int64(-2), b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe)},
+	{"long/min", Long, binutils.LittleEndian, int64(math.MinInt64), b(0, 0, 0, 0, 0, 0, 0, 0x80)},
+	{"unsigned_long/0x0102030405060708", UnsignedLong, binutils.BigEndian, uint64(0x0102030405060708), b(1, 2, 3, 4, 5, 6, 7, 8)},
+	{"unsigned_long/0x0102030405060708", UnsignedLong, binutils.LittleEndian, uint64(0x0102030405060708), b(8, 7, 6, 5, 4, 3, 2, 1)},
+	{"float/1.5", Float, binutils.BigEndian, float32(1.5), b(0x3f, 0xc0, 0x00, 0x00)},
+	{"float/1.5", Float, binutils.LittleEndian, float32(1.5), b(0x00, 0x00, 0xc0, 0x3f)},
+	{"float/-0", Float, binutils.BigEndian, float32(math.Copysign(0, -1)), b(0x80, 0x00, 0x00, 0x00)},
+	{"double/1.5", Double, binutils.BigEndian, 1.5, b(0x3f, 0xf8, 0, 0, 0, 0, 0, 0)},
+	{"double/1.5", Double, binutils.LittleEndian, 1.5, b(0, 0, 0, 0, 0, 0, 0xf8, 0x3f)},
+	{"double/+inf", Double, binutils.BigEndian, math.Inf(1), b(0x7f, 0xf0, 0, 0, 0, 0, 0, 0)},
+	{"triad/0x010203", Triad, binutils.BigEndian, uint32(0x010203), b(0x01, 0x02, 0x03)},
+	{"triad/0x010203", Triad, binutils.LittleEndian, uint32(0x010203), b(0x03, 0x02, 0x01)},
+	{"triad/0xfedcba", Triad, binutils.BigEndian, uint32(0xfedcba), b(0xfe, 0xdc, 0xba)},
+	{"triad/0xfedcba", Triad, binutils.LittleEndian, uint32(0xfedcba), b(0xba, 0xdc, 0xfe)},
+	{"unsigned_varint/0", UnsignedVarInt, binutils.BigEndian, uint32(0), b(0x00)},
+	{"unsigned_varint/127", UnsignedVarInt, binutils.BigEndian, uint32(127), b(0x7f)},
+	{"unsigned_varint/128", UnsignedVarInt, binutils.BigEndian, uint32(128), b(0x80, 0x01)},
+	{"unsigned_varint/300", UnsignedVarInt, binutils.BigEndian, uint32(300), b(0xac, 0x02)},
+	{"unsigned_varint/max", UnsignedVarInt, binutils.BigEndian, uint32(math.MaxUint32), b(0xff, 0xff, 0xff, 0xff, 0x0f)},
+	{"varint/0", VarInt, binutils.BigEndian, int32(0), b(0x00)},
+	{"varint/-1", VarInt, binutils.BigEndian, int32(-1), b(0x01)},
+	{"varint/1", VarInt, binutils.BigEndian, int32(1), b(0x02)},
+	{"varint/-64", VarInt, binutils.BigEndian, int32(-64), b(0x7f)},
+	{"varint/64", VarInt, binutils.BigEndian, int32(64), b(0x80, 0x01)},
+	{"varint/max", VarInt, binutils.BigEndian, int32(math.MaxInt32), b(0xfe, 0xff, 0xff, 0xff, 0x0f)},
+	{"varint/min", VarInt, binutils.BigEndian, int32(math.MinInt32), b(0xff, 0xff, 0xff, 0xff, 0x0f)},
+	{"unsigned_varlong/1", UnsignedVarLong, binutils.BigEndian, uint64(1), b(0x01)},
+	{"unsigned_varlong/max", UnsignedVarLong, binutils.BigEndian, uint64(math.MaxUint64), b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01)},
+	{"varlong/-1", VarLong, binutils.BigEndian, int64(-1), b(0x01)},
+	{"varlong/min", VarLong, binutils.BigEndian, int64(math.MinInt64), b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01)},
+	{"string/empty", String, binutils.BigEndian, "", b(0x00)},
+	{"string/hello", String, binutils.BigEndian, "hello", b(0x05, 'h', 'e', 'l', 'l', 'o')},
+	{"string/utf8", String, binutils.BigEndian, "héllo", b(0x06, 'h', 0xc3, 0xa9, 'l', 'l', 'o')},
+}
+
+// Vectors returns a copy of the canonical test vectors.
+func Vectors() []Vector {
+	copied := make([]Vector, len(vectors))
+	for i, vector := range vectors {
+		vector.Encoded = append([]byte{}, vector.Encoded...)
+		copied[i] = vector
+	}
+	return copied
+}
+
+// endianName returns the name of the byte order of a vector.
+func endianName(endian binutils.EndianType) string {
+	if endian == binutils.LittleEndian {
+		return "le"
+	}
+	return "be"
+}
+
+// equal compares decoded values, comparing floats by their bits so that -0 and NaN are compared exactly.
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case float32:
+		b, ok := b.(float32)
+		return ok && math.Float32bits(a) == math.Float32bits(b)
+	case float64:
+		b, ok := b.(float64)
+		return ok && math.Float64bits(a) == math.Float64bits(b)
+	}
+	return a == b
+}
+
+// Run runs every test vector against the codec as a subtest, checking that the codec encodes the value
+// of the vector to its canonical encoding, and decodes the canonical encoding back to the value.
+func Run(t *testing.T, codec Codec) {
+	for _, vector := range Vectors() {
+		vector := vector
+		t.Run(vector.Name+"/"+endianName(vector.Endian), func(t *testing.T) {
+			encoded, err := codec.Encode(vector)
+			if err != nil {
+				t.Errorf("encode %v: %v", vector.Value, err)
+			} else if !bytes.Equal(encoded, vector.Encoded) {
+				t.Errorf("encode %v: got % x, want % x", vector.Value, encoded, vector.Encoded)
+			}
+			decoded, err := codec.Decode(vector, vector.Encoded)
+			if err != nil {
+				t.Errorf("decode % x: %v", vector.Encoded, err)
+			} else if !equal(decoded, vector.Value) {
+				t.Errorf("decode % x: got %v (%T), want %v (%T)", vector.Encoded, decoded, decoded, vector.Value, vector.Value)
+			}
+		})
+	}
+}
+
+// Native is the codec of this package.
+var Native Codec = native{}
+
+// native implements Codec with binutils streams.
+type native struct{}
+
+func (native) Encode(vector Vector) (encoded []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("conformance: value %v (%T) does not match kind %s", vector.Value, vector.Value, vector.Kind)
+		}
+	}()
+	stream := binutils.NewStream()
+	stream.SetEndianness(vector.Endian)
+	switch vector.Kind {
+	case Short:
+		stream.PutShort(vector.Value.(int16))
+	case UnsignedShort:
+		stream.PutUnsignedShort(vector.Value.(uint16))
+	case Int:
+		stream.PutInt(vector.Value.(int32))
+	case UnsignedInt:
+		stream.PutUnsignedInt(vector.Value.(uint32))
+	case Long:
+		stream.PutLong(vector.Value.(int64))
+	case UnsignedLong:
+		stream.PutUnsignedLong(vector.Value.(uint64))
+	case Float:
+		stream.PutFloat(vector.Value.(float32))
+	case Double:
+		stream.PutDouble(vector.Value.(float64))
+	case Triad:
+		stream.PutTriad(vector.Value.(uint32))
+	case VarInt:
+		stream.PutVarInt(vector.Value.(int32))
+	case VarLong:
+		stream.PutVarLong(vector.Value.(int64))
+	case UnsignedVarInt:
+		stream.PutUnsignedVarInt(vector.Value.(uint32))
+	case UnsignedVarLong:
+		stream.PutUnsignedVarLong(vector.Value.(uint64))
+	case String:
+		stream.PutString(vector.Value.(string))
+	default:
+		return nil, fmt.Errorf("conformance: unknown kind %s", vector.Kind)
+	}
+	return stream.Buffer, nil
+}
+
+func (native) Decode(vector Vector, data []byte) (interface{}, error) {
+	stream := &binutils.Stream{Buffer: data}
+	stream.SetEndianness(vector.Endian)
+	var v interface{}
+	switch vector.Kind {
+	case Short:
+		v = stream.GetShort()
+	case UnsignedShort:
+		v = stream.GetUnsignedShort()
+	case Int:
+		v = stream.GetInt()
+	case UnsignedInt:
+		v = stream.GetUnsignedInt()
+	case Long:
+		v = stream.GetLong()
+	case UnsignedLong:
+		v = stream.GetUnsignedLong()
+	case Float:
+		v = stream.GetFloat()
+	case Double:
+		v = stream.GetDouble()
+	case Triad:
+		v = stream.GetTriad()
+	case VarInt:
+		v = stream.GetVarInt()
+	case VarLong:
+		v = stream.GetVarLong()
+	case UnsignedVarInt:
+		v = stream.GetUnsignedVarInt()
+	case UnsignedVarLong:
+		v = stream.GetUnsignedVarLong()
+	case String:
+		v = stream.GetString()
+	default:
+		return nil, fmt.Errorf("conformance: unknown kind %s", vector.Kind)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if stream.Remaining() > 0 {
+		return nil, fmt.Errorf("conformance: %d trailing bytes", stream.Remaining())
+	}
+	return v, nil
+}
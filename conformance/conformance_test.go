@@ -0,0 +1,44 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestNative(t *testing.T) {
+	Run(t, Native)
+}
+
+func TestVectorsCopied(t *testing.T) {
+	Vectors()[0].Encoded[0] = 0
+	assert.Equal(t, vectors[0].Encoded[0], byte(0xff))
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NilError(t, WriteJSON(&buffer))
+	var out []exported
+	assert.NilError(t, json.Unmarshal(buffer.Bytes(), &out))
+	assert.Equal(t, len(out), len(vectors))
+	assert.DeepEqual(t, out[0], exported{"short/-2", Short, "be", "-2", "fffe"})
+
+	values := map[string]string{}
+	for _, vector := range out {
+		values[vector.Name] = vector.Value
+	}
+	assert.Equal(t, values["float/-0"], "-0")
+	assert.Equal(t, values["double/+inf"], "+Inf")
+	assert.Equal(t, values["unsigned_varlong/max"], "18446744073709551615")
+}
+
+func TestWriteHex(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NilError(t, WriteHex(&buffer))
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Equal(t, len(lines), len(vectors))
+	assert.Equal(t, lines[0], "short\tbe\tfffe\t-2")
+	assert.Equal(t, lines[len(lines)-1], "string\tbe\t0668c3a96c6c6f\t\"héllo\"")
+}
@@ -0,0 +1,69 @@
+package conformance
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// exported is the JSON form of a vector.
+type exported struct {
+	Name   string `json:"name"`
+	Kind   Kind   `json:"kind"`
+	Endian string `json:"endian"`
+	Value  string `json:"value"`
+	Hex    string `json:"hex"`
+}
+
+// FormatValue formats the value of a vector for export. Integers are formatted in decimal and floats with the shortest
+// representation that parses back to the same value, such as "1.5", "-0" or "+Inf", so that 64-bit values survive
+// JSON parsers of other languages. Strings are returned as is.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatFloat formats a float with the given bit size, keeping the sign of zero.
+func formatFloat(f float64, bitSize int) string {
+	if f == 0 && math.Signbit(f) {
+		return "-0"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// WriteJSON writes the test vectors as a JSON array of objects with the name, kind, byte order ("be" or "le"),
+// value formatted by FormatValue, and hex encoding of every vector.
+func WriteJSON(writer io.Writer) error {
+	var out []exported
+	for _, vector := range vectors {
+		out = append(out, exported{vector.Name, vector.Kind, endianName(vector.Endian), FormatValue(vector.Value), hex.EncodeToString(vector.Encoded)})
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(out)
+}
+
+// WriteHex writes the test vectors as lines of the kind, byte order, hex encoding and value formatted by FormatValue,
+// separated by tabs, for parsers without JSON support. Values of strings are quoted with Go syntax.
+func WriteHex(writer io.Writer) error {
+	for _, vector := range vectors {
+		value := FormatValue(vector.Value)
+		if vector.Kind == String {
+			value = strconv.Quote(value)
+		}
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%x\t%s\n", vector.Kind, endianName(vector.Endian), vector.Encoded, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
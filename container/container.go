@@ -0,0 +1,229 @@
+// Package container implements a block based container of records with an index footer.
+//
+// Records are grouped into blocks of about the configured block size, which are compressed and checksummed.
+// The index at the end of the container lists the blocks with the range of records they hold, so that
+// a record is read by decompressing only its block.
+//
+// A container is laid out as the blocks, followed by the index entries and the footer. All integers are little endian.
+//
+//	block:   compressed records, each prefixed with its length as unsigned varint
+//	entry:   uint64 block offset, uint32 compressed size, uint32 CRC-32 of the compressed block,
+//	         uint64 index of the first record, uint32 record count
+//	footer:  uint64 index offset, uint32 block count, uint8 compression type, "BUCF"
+package container
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"github.com/irmine/binutils"
+)
+
+const (
+	// entrySize is the encoded size of an index entry.
+	entrySize = 28
+	// footerSize is the encoded size of the footer.
+	footerSize = 17
+	// magic ends every container.
+	magic = "BUCF"
+)
+
+// DefaultBlockSize is the uncompressed block size of writers created with a block size of 0 or less.
+const DefaultBlockSize = 64 * 1024
+
+// ErrInvalidContainer is returned when a container does not end with a valid footer and index.
+var ErrInvalidContainer = errors.New("container: invalid container")
+
+// entry is an index entry of a block.
+type entry struct {
+	offset uint64
+	size   uint32
+	crc    uint32
+	first  uint64
+	count  uint32
+}
+
+// Writer writes records to a container.
+type Writer struct {
+	writer    io.Writer
+	blockSize int
+	algo      binutils.CompressionType
+	block     *binutils.Stream
+	count     uint32
+	offset    uint64
+	records   uint64
+	index     []entry
+}
+
+// NewWriter returns a writer writing a container to the writer, with blocks of about blockSize uncompressed
+// bytes compressed with the algorithm.
+func NewWriter(writer io.Writer, blockSize int, algo binutils.CompressionType) *Writer {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	return &Writer{writer: writer, blockSize: blockSize, algo: algo, block: binutils.NewStreamWithCapacity(blockSize)}
+}
+
+// Append appends a record. The block holding the record is written once it is full.
+func (writer *Writer) Append(record []byte) error {
+	writer.block.PutUnsignedVarInt(uint32(len(record)))
+	writer.block.PutBytes(record)
+	writer.count++
+	if len(writer.block.Buffer) >= writer.blockSize {
+		return writer.Flush()
+	}
+	return nil
+}
+
+// Flush writes the current block, even if it is not full.
+func (writer *Writer) Flush() error {
+	if writer.count == 0 {
+		return nil
+	}
+	if err := writer.block.Compress(writer.algo); err != nil {
+		return err
+	}
+	compressed := writer.block.Buffer
+	if _, err := writer.writer.Write(compressed); err != nil {
+		return err
+	}
+	writer.index = append(writer.index, entry{writer.offset, uint32(len(compressed)), crc32.ChecksumIEEE(compressed), writer.records, writer.count})
+	writer.offset += uint64(len(compressed))
+	writer.records += uint64(writer.count)
+	writer.count = 0
+	writer.block.Buffer = writer.block.Buffer[:0]
+	return nil
+}
+
+// Close writes the current block, the index and the footer. The underlying writer is not closed.
+func (writer *Writer) Close() error {
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	stream := binutils.NewStreamWithCapacity(len(writer.index)*entrySize + footerSize)
+	for _, entry := range writer.index {
+		stream.PutLittleUnsignedLong(entry.offset)
+		stream.PutLittleUnsignedInt(entry.size)
+		stream.PutLittleUnsignedInt(entry.crc)
+		stream.PutLittleUnsignedLong(entry.first)
+		stream.PutLittleUnsignedInt(entry.count)
+	}
+	stream.PutLittleUnsignedLong(writer.offset)
+	stream.PutLittleUnsignedInt(uint32(len(writer.index)))
+	stream.PutByte(byte(writer.algo))
+	stream.PutBytes([]byte(magic))
+	_, err := stream.WriteTo(writer.writer)
+	return err
+}
+
+// Reader reads records from a container.
+type Reader struct {
+	reader io.ReaderAt
+	algo   binutils.CompressionType
+	index  []entry
+	count  uint64
+
+	// block caches the records of the last block read.
+	block        int
+	blockCache   [][]byte
+	maxBlockSize int
+}
+
+// NewReader returns a reader of the container of the given size read from the reader.
+func NewReader(reader io.ReaderAt, size int64) (*Reader, error) {
+	if size < footerSize {
+		return nil, ErrInvalidContainer
+	}
+	footer := make([]byte, footerSize)
+	if _, err := reader.ReadAt(footer, size-footerSize); err != nil {
+		return nil, err
+	}
+	stream := &binutils.Stream{Buffer: footer}
+	indexOffset := stream.GetLittleUnsignedLong()
+	blocks := stream.GetLittleUnsignedInt()
+	algo := binutils.CompressionType(stream.GetByte())
+	// The checks are ordered so that hostile footers cannot overflow the size of the index.
+	end := uint64(size - footerSize)
+	if string(stream.Get(len(magic))) != magic || uint64(blocks) > end/entrySize || indexOffset > end ||
+		indexOffset+uint64(blocks)*entrySize != end {
+		return nil, ErrInvalidContainer
+	}
+
+	index := make([]byte, int(blocks)*entrySize)
+	if _, err := reader.ReadAt(index, int64(indexOffset)); err != nil {
+		return nil, err
+	}
+	stream = &binutils.Stream{Buffer: index}
+	r := &Reader{reader: reader, algo: algo, index: make([]entry, blocks), block: -1, maxBlockSize: binutils.DefaultMaxDecompressedSize}
+	for i := range r.index {
+		e := entry{stream.GetLittleUnsignedLong(), stream.GetLittleUnsignedInt(), stream.GetLittleUnsignedInt(),
+			stream.GetLittleUnsignedLong(), stream.GetLittleUnsignedInt()}
+		if e.first != r.count || e.offset > indexOffset || uint64(e.size) > indexOffset-e.offset {
+			return nil, ErrInvalidContainer
+		}
+		r.index[i] = e
+		r.count += uint64(e.count)
+	}
+	return r, nil
+}
+
+// SetMaxBlockSize sets the maximum decompressed size of blocks read. Larger blocks are rejected with an error
+// matching binutils.ErrLimitExceeded. The default is binutils.DefaultMaxDecompressedSize.
+func (reader *Reader) SetMaxBlockSize(size int) {
+	reader.maxBlockSize = size
+}
+
+// Len returns the number of records in the container.
+func (reader *Reader) Len() int {
+	return int(reader.count)
+}
+
+// Record returns the record with the given index, reading and decompressing only its block.
+// It returns binutils.ErrChecksumMismatch if the block is corrupt.
+func (reader *Reader) Record(i int) ([]byte, error) {
+	if i < 0 || uint64(i) >= reader.count {
+		return nil, fmt.Errorf("container: record %d out of range [0, %d)", i, reader.count)
+	}
+	block := sort.Search(len(reader.index), func(block int) bool {
+		return reader.index[block].first+uint64(reader.index[block].count) > uint64(i)
+	})
+	if block != reader.block {
+		records, err := reader.readBlock(reader.index[block])
+		if err != nil {
+			return nil, err
+		}
+		reader.block, reader.blockCache = block, records
+	}
+	return reader.blockCache[uint64(i)-reader.index[block].first], nil
+}
+
+// readBlock reads, verifies and decompresses a block, and returns its records.
+func (reader *Reader) readBlock(e entry) ([][]byte, error) {
+	compressed := make([]byte, e.size)
+	if _, err := reader.reader.ReadAt(compressed, int64(e.offset)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(compressed) != e.crc {
+		return nil, fmt.Errorf("container: block at offset %d: %w", e.offset, binutils.ErrChecksumMismatch)
+	}
+	stream := &binutils.Stream{Buffer: compressed}
+	stream.SetMaxDecompressedSize(reader.maxBlockSize)
+	if err := stream.Decompress(reader.algo); err != nil {
+		return nil, err
+	}
+	// Every record takes at least the byte of its length prefix.
+	if uint64(e.count) > uint64(len(stream.Buffer)) {
+		return nil, fmt.Errorf("container: block at offset %d: %w", e.offset, ErrInvalidContainer)
+	}
+	records := make([][]byte, e.count)
+	for i := range records {
+		records[i] = stream.GetLengthPrefixedBytes()
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,82 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/irmine/binutils"
+	"gotest.tools/assert"
+)
+
+func TestContainer(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer, 64, binutils.CompressionZlib)
+	for i := 0; i < 100; i++ {
+		assert.NilError(t, writer.Append([]byte(fmt.Sprintf("record %d", i))))
+	}
+	assert.NilError(t, writer.Close())
+
+	reader, err := NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NilError(t, err)
+	assert.Equal(t, reader.Len(), 100)
+	assert.Assert(t, len(reader.index) > 1)
+	for _, i := range []int{42, 0, 99, 43} {
+		record, err := reader.Record(i)
+		assert.NilError(t, err)
+		assert.Equal(t, string(record), fmt.Sprintf("record %d", i))
+	}
+	_, err = reader.Record(100)
+	assert.ErrorContains(t, err, "out of range")
+}
+
+func TestContainerCorrupt(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer, 0, binutils.CompressionDeflate)
+	assert.NilError(t, writer.Append([]byte("record")))
+	assert.NilError(t, writer.Close())
+
+	data := buffer.Bytes()
+	data[0] ^= 0xff
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	_, err = reader.Record(0)
+	assert.Assert(t, errors.Is(err, binutils.ErrChecksumMismatch))
+
+	_, err = NewReader(bytes.NewReader(data[:len(data)-1]), int64(len(data)-1))
+	assert.Assert(t, errors.Is(err, ErrInvalidContainer))
+}
+
+func TestContainerHostile(t *testing.T) {
+	// The index offset wraps around so that the footer seems to describe 2^32-1 blocks in 28 bytes.
+	blocks := uint32(0xffffffff)
+	stream := binutils.NewStream()
+	stream.PutBytes(make([]byte, entrySize))
+	stream.PutLittleUnsignedLong(entrySize - uint64(blocks)*entrySize)
+	stream.PutLittleUnsignedInt(blocks)
+	stream.PutByte(byte(binutils.CompressionZlib))
+	stream.PutBytes([]byte(magic))
+	_, err := NewReader(bytes.NewReader(stream.Buffer), int64(len(stream.Buffer)))
+	assert.Assert(t, errors.Is(err, ErrInvalidContainer))
+
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer, 0, binutils.CompressionZlib)
+	assert.NilError(t, writer.Append([]byte("record")))
+	assert.NilError(t, writer.Close())
+	data := buffer.Bytes()
+
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	reader.SetMaxBlockSize(4)
+	_, err = reader.Record(0)
+	assert.Assert(t, errors.Is(err, binutils.ErrLimitExceeded))
+
+	// The record count of the only block is patched to 2^31.
+	count := len(data) - footerSize - 4
+	copy(data[count:], []byte{0, 0, 0, 0x80})
+	reader, err = NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NilError(t, err)
+	_, err = reader.Record(1 << 30)
+	assert.Assert(t, errors.Is(err, ErrInvalidContainer))
+}
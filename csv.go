@@ -0,0 +1,215 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// csvColumns returns the CSV columns of the struct type: the paths of its fields, as used by PatchField.
+// Nested structs and arrays are flattened into a column per field or element.
+func csvColumns(t reflect.Type, prefix string) ([]string, error) {
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, err := structFields(t)
+		if err != nil {
+			return nil, err
+		}
+		var columns []string
+		for _, field := range fields {
+			path := field.name
+			if prefix != "" {
+				path = prefix + "." + field.name
+			}
+			nested, err := csvColumns(t.FieldByIndex(field.index).Type, path)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, nested...)
+		}
+		return columns, nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return []string{prefix}, nil
+		}
+		var columns []string
+		for i := 0; i < t.Len(); i++ {
+			nested, err := csvColumns(t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, nested...)
+		}
+		return columns, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return []string{prefix}, nil
+		}
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{prefix}, nil
+	}
+	return nil, fmt.Errorf("binutils: field %s of type %s cannot be mapped to a CSV column", prefix, t)
+}
+
+// formatCSV formats a value of a CSV column. Byte slices and arrays are formatted as hex.
+func formatCSV(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.String:
+		return value.String()
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	}
+	b := make([]byte, value.Len())
+	reflect.Copy(reflect.ValueOf(b), value)
+	return hex.EncodeToString(b)
+}
+
+// parseCSV parses the value of a CSV column into the field.
+func parseCSV(value reflect.Value, s string) error {
+	var err error
+	switch value.Kind() {
+	case reflect.Bool:
+		var v bool
+		v, err = strconv.ParseBool(s)
+		value.SetBool(v)
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		v, err = strconv.ParseFloat(s, value.Type().Bits())
+		value.SetFloat(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		v, err = strconv.ParseInt(s, 10, value.Type().Bits())
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		v, err = strconv.ParseUint(s, 10, value.Type().Bits())
+		value.SetUint(v)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("type %s cannot be mapped to a CSV column", value.Type())
+		}
+		var b []byte
+		b, err = hex.DecodeString(s)
+		value.SetBytes(b)
+	case reflect.Array:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("type %s cannot be mapped to a CSV column", value.Type())
+		}
+		var b []byte
+		if b, err = hex.DecodeString(s); err == nil && len(b) != value.Len() {
+			err = fmt.Errorf("expected %d bytes, got %d", value.Len(), len(b))
+		}
+		reflect.Copy(value, reflect.ValueOf(b))
+	default:
+		return fmt.Errorf("type %s cannot be mapped to a CSV column", value.Type())
+	}
+	return err
+}
+
+// csvRecord checks that v is a pointer to a struct and returns the struct.
+func csvRecord(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return value, fmt.Errorf("binutils: CSV records require a non-nil pointer to a struct, got %T", v)
+	}
+	return value.Elem(), nil
+}
+
+// ExportCSV decodes records of the struct type v points to from the stream until no bytes are left,
+// and writes them to the writer as CSV, with a header row of the field paths, such as Header.ID or Position[0].
+// Byte slices and arrays are written as hex. v holds the last record read. ExportCSV returns the number of records written.
+func ExportCSV(writer io.Writer, stream *Stream, v interface{}) (int, error) {
+	record, err := csvRecord(v)
+	if err != nil {
+		return 0, err
+	}
+	columns, err := csvColumns(record.Type(), "")
+	if err != nil {
+		return 0, err
+	}
+	w := csv.NewWriter(writer)
+	if err := w.Write(columns); err != nil {
+		return 0, err
+	}
+	row := make([]string, len(columns))
+	n := 0
+	for stream.Remaining() > 0 {
+		record.Set(reflect.Zero(record.Type()))
+		if err := stream.GetStruct(v); err != nil {
+			return n, err
+		}
+		for i, column := range columns {
+			field, _, err := resolvePath(record, column)
+			if err != nil {
+				return n, err
+			}
+			row[i] = formatCSV(field)
+		}
+		if err := w.Write(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	w.Flush()
+	return n, w.Error()
+}
+
+// ImportCSV reads CSV rows from the reader and writes them to the stream as records of the struct type v points to.
+// The header row names the field of each column, in any order, as written by ExportCSV. Fields without a column are zero.
+// ImportCSV returns the number of records written.
+func ImportCSV(stream *Stream, reader io.Reader, v interface{}) (int, error) {
+	record, err := csvRecord(v)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := csvColumns(record.Type(), ""); err != nil {
+		return 0, err
+	}
+	r := csv.NewReader(reader)
+	columns, err := r.Read()
+	if err != nil {
+		return 0, err
+	}
+	r.FieldsPerRecord = len(columns)
+	n := 0
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		record.Set(reflect.Zero(record.Type()))
+		for i, column := range columns {
+			field, _, err := resolvePath(record, column)
+			if err != nil {
+				return n, err
+			}
+			if err := parseCSV(field, row[i]); err != nil {
+				return n, fmt.Errorf("binutils: record %d: column %s: %v", n, column, err)
+			}
+		}
+		if err := stream.PutStruct(v); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
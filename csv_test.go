@@ -0,0 +1,54 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bytes"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+type testCSVRecord struct {
+	Header   testHeader
+	Name     string `bin:",prefix=uint16"`
+	Position [2]float32
+	Payload  []byte
+	Enabled  bool
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	stream := NewStream()
+	assert.NilError(t, stream.PutStruct(testCSVRecord{Header: testHeader{ID: 1, Version: -3}, Name: "steve", Position: [2]float32{1.5, -2}, Payload: b(0xde, 0xad), Enabled: true}))
+	assert.NilError(t, stream.PutStruct(testCSVRecord{Name: "a, \"b\""}))
+	encoded := append([]byte{}, stream.Buffer...)
+
+	var out bytes.Buffer
+	var record testCSVRecord
+	n, err := ExportCSV(&out, stream, &record)
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+	assert.Equal(t, out.String(), "Header.ID,Header.Flags,Header.Version,Name,Position[0],Position[1],Payload,Enabled\n"+
+		"1,0,-3,steve,1.5,-2,dead,true\n"+
+		"0,0,0,\"a, \"\"b\"\"\",0,0,,false\n")
+
+	imported := NewStream()
+	n, err = ImportCSV(imported, &out, &record)
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+	assert.DeepEqual(t, imported.Buffer, encoded)
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	var record testCSVRecord
+	_, err := ImportCSV(NewStream(), strings.NewReader("Name,Header.ID\nsteve,70000\n"), &record)
+	assert.ErrorContains(t, err, "column Header.ID")
+	_, err = ImportCSV(NewStream(), strings.NewReader("Unknown\n1\n"), &record)
+	assert.ErrorContains(t, err, "unknown field Unknown")
+
+	var scores struct {
+		Scores []int64
+	}
+	_, err = ExportCSV(&bytes.Buffer{}, NewStream(), &scores)
+	assert.ErrorContains(t, err, "cannot be mapped to a CSV column")
+}
@@ -0,0 +1,61 @@
+package binutils
+
+import (
+	"fmt"
+)
+
+// DefaultMaxDepth is the nesting depth limit of streams without a configured limit.
+const DefaultMaxDepth = 512
+
+// DepthError is the error set on a stream when nested structures exceed its depth limit.
+// It matches ErrLimitExceeded with errors.Is.
+type DepthError struct {
+	Limit int
+}
+
+func (err *DepthError) Error() string {
+	return fmt.Sprintf("nesting depth exceeds limit of %d", err.Limit)
+}
+
+// Is checks if the target is ErrLimitExceeded.
+func (err *DepthError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// SetMaxDepth sets the maximum nesting depth of structures decoded from the stream.
+// A depth of 0 or less uses DefaultMaxDepth.
+func (stream *Stream) SetMaxDepth(depth int) {
+	stream.maxDepth = depth
+}
+
+// GetMaxDepth returns the maximum nesting depth of structures decoded from the stream.
+func (stream *Stream) GetMaxDepth() int {
+	if stream.maxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return stream.maxDepth
+}
+
+// Depth returns the current nesting depth.
+func (stream *Stream) Depth() int {
+	return stream.depth
+}
+
+// Enter enters a nested structure, such as a compound or a nested TLV, before decoding or encoding it.
+// If the nesting depth would exceed the limit, a *DepthError is set on the stream and false is returned.
+// Every successful call must be paired with a call to Leave once the structure is decoded.
+func (stream *Stream) Enter() bool {
+	if stream.depth >= stream.GetMaxDepth() {
+		stream.SetError(&StreamError{"Enter", stream.Offset, &DepthError{stream.GetMaxDepth()}})
+		return false
+	}
+	stream.depth++
+	return true
+}
+
+// Leave leaves a nested structure entered with Enter.
+func (stream *Stream) Leave() {
+	if stream.depth > 0 {
+		stream.depth--
+	}
+}
@@ -0,0 +1,40 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDepthLimitChild(t *testing.T) {
+	stream := NewStream()
+	stream.SetMaxDepth(2)
+	assert.Assert(t, stream.Enter())
+
+	// Child streams continue at the depth of their parent.
+	child := stream.child([]byte{})
+	assert.Equal(t, child.Depth(), 1)
+	assert.Assert(t, child.Enter())
+	assert.Assert(t, !child.Enter())
+	assert.Assert(t, errors.Is(child.Err(), ErrLimitExceeded))
+}
+
+func TestDepthLimit(t *testing.T) {
+	stream := NewStream()
+	stream.SetMaxDepth(2)
+
+	assert.Assert(t, stream.Enter())
+	assert.Assert(t, stream.Enter())
+	assert.Assert(t, !stream.Enter())
+	assert.Equal(t, stream.Depth(), 2)
+
+	var depthErr *DepthError
+	assert.Assert(t, errors.As(stream.Err(), &depthErr))
+	assert.Equal(t, depthErr.Limit, 2)
+	assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+	assert.Equal(t, CategoryOf(stream.Err()), CategoryLimitExceeded)
+
+	stream.Leave()
+	stream.Leave()
+	assert.Equal(t, stream.Depth(), 0)
+}
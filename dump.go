@@ -0,0 +1,222 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxDumpBytes is the maximum number of bytes shown in hex per field by Sdump.
+const maxDumpBytes = 16
+
+// DumpFormat is the text format of dumps returned by SdumpFormat.
+type DumpFormat int
+
+const (
+	// DumpText is a table with a line per field, as returned by Sdump.
+	DumpText DumpFormat = iota
+	// DumpJSON is an indented JSON object, with the fields of structs and elements of slices nested in "fields".
+	DumpJSON
+	// DumpYAML is a YAML document with the same structure as DumpJSON.
+	DumpYAML
+	// DumpSExpr is an s-expression with a list per field, holding the lists of its nested fields.
+	DumpSExpr
+)
+
+// dumpNode is a field in the tree of a dump.
+type dumpNode struct {
+	Name   string      `json:"name,omitempty"`
+	Type   string      `json:"type,omitempty"`
+	Offset int         `json:"offset"`
+	Size   int         `json:"size"`
+	Value  string      `json:"value,omitempty"`
+	Bytes  string      `json:"bytes,omitempty"`
+	Fields []*dumpNode `json:"fields,omitempty"`
+
+	// group is set for structs, which are shown by their fields only.
+	group bool
+	depth int
+}
+
+// Sdump returns a description of v, which must be a struct or a pointer to a struct,
+// listing the offset, value and encoded bytes of every field as encoded by Marshal.
+// Integer fields of types with a registered enum are shown by name.
+// It is intended for logging and test failure output.
+func Sdump(v interface{}) string {
+	return SdumpFormat(v, DumpText)
+}
+
+// SdumpFormat returns a description of v like Sdump in the given format, so that dumps can be pasted into
+// configuration files or compared with standard text tools.
+func SdumpFormat(v interface{}, format DumpFormat) string {
+	root, err := dumpTree(v)
+	if err != nil {
+		return err.Error()
+	}
+	var dump bytes.Buffer
+	switch format {
+	case DumpJSON:
+		b, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		dump.Write(b)
+		dump.WriteByte('\n')
+	case DumpYAML:
+		writeYAML(&dump, root, "")
+	case DumpSExpr:
+		writeSExpr(&dump, root, "")
+		dump.WriteByte('\n')
+	default:
+		writeText(&dump, root)
+	}
+	return dump.String()
+}
+
+// dumpTree encodes v and returns the tree of its fields.
+func dumpTree(v interface{}) (*dumpNode, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("binutils: cannot dump %T, expected a struct", v)
+	}
+	m := &marshaler{stream: NewStream(), track: true}
+	if err := m.encode(value, fieldTag{}, ""); err != nil {
+		return nil, err
+	}
+
+	root := &dumpNode{Type: value.Type().String(), Size: len(m.stream.Buffer)}
+	nodes := map[string]*dumpNode{"": root}
+	for _, field := range m.fields {
+		// Only structs and their elements are tracked, so the closest tracked ancestor is the parent.
+		parent := field.path
+		for ok := false; !ok; _, ok = nodes[parent] {
+			parent = parent[:max(strings.LastIndex(parent, "."), strings.LastIndex(parent, "["), 0)]
+		}
+		name := strings.TrimPrefix(field.path[len(parent):], ".")
+		node := &dumpNode{Name: name, Offset: field.Start, Size: field.End - field.Start, depth: nodes[parent].depth + 1}
+		node.group = field.value.Kind() == reflect.Struct
+		if !node.group {
+			node.Value = formatValue(field.value)
+			node.Bytes = formatBytes(m.stream.Buffer[field.Start:field.End])
+		}
+		nodes[parent].Fields = append(nodes[parent].Fields, node)
+		nodes[field.path] = node
+	}
+	return root, nil
+}
+
+// writeText writes the fields of the tree as table.
+func writeText(dump *bytes.Buffer, root *dumpNode) {
+	fmt.Fprintf(dump, "%s (%d bytes)\n", root.Type, root.Size)
+	w := tabwriter.NewWriter(dump, 0, 4, 2, ' ', 0)
+	var write func(node *dumpNode)
+	write = func(node *dumpNode) {
+		indent := strings.Repeat("  ", node.depth-1)
+		if node.group {
+			fmt.Fprintf(w, "%04x\t%s%s\t\t\n", node.Offset, indent, node.Name)
+		} else {
+			fmt.Fprintf(w, "%04x\t%s%s\t%s\t%s\n", node.Offset, indent, node.Name, node.Value, node.Bytes)
+		}
+		for _, field := range node.Fields {
+			write(field)
+		}
+	}
+	for _, field := range root.Fields {
+		write(field)
+	}
+	w.Flush()
+}
+
+// writeYAML writes the node as YAML mapping, with its keys indented by indent.
+func writeYAML(dump *bytes.Buffer, node *dumpNode, indent string) {
+	first := true
+	key := func(name string) string {
+		if first {
+			first = false
+			return name
+		}
+		return indent + name
+	}
+	if node.Name != "" {
+		fmt.Fprintf(dump, "%s: %s\n", key("name"), strconv.Quote(node.Name))
+	}
+	if node.Type != "" {
+		fmt.Fprintf(dump, "%s: %s\n", key("type"), strconv.Quote(node.Type))
+	}
+	fmt.Fprintf(dump, "%s: %d\n", key("offset"), node.Offset)
+	fmt.Fprintf(dump, "%s: %d\n", key("size"), node.Size)
+	if node.Value != "" {
+		fmt.Fprintf(dump, "%s: %s\n", key("value"), strconv.Quote(node.Value))
+	}
+	if node.Bytes != "" {
+		fmt.Fprintf(dump, "%s: %s\n", key("bytes"), strconv.Quote(node.Bytes))
+	}
+	if len(node.Fields) > 0 {
+		fmt.Fprintf(dump, "%s:\n", key("fields"))
+		for _, field := range node.Fields {
+			fmt.Fprintf(dump, "%s  - ", indent)
+			writeYAML(dump, field, indent+"    ")
+		}
+	}
+}
+
+// writeSExpr writes the node as list of its name, attributes and fields, with nested lists indented by indent.
+func writeSExpr(dump *bytes.Buffer, node *dumpNode, indent string) {
+	name := node.Name
+	if name == "" {
+		name = node.Type
+	}
+	fmt.Fprintf(dump, "(%s :offset %d :size %d", strconv.Quote(name), node.Offset, node.Size)
+	if node.Value != "" {
+		fmt.Fprintf(dump, " :value %s", strconv.Quote(node.Value))
+	}
+	if node.Bytes != "" {
+		fmt.Fprintf(dump, " :bytes %s", strconv.Quote(node.Bytes))
+	}
+	for _, field := range node.Fields {
+		fmt.Fprintf(dump, "\n%s  ", indent)
+		writeSExpr(dump, field, indent+"  ")
+	}
+	dump.WriteByte(')')
+}
+
+// formatValue formats the value of a field for Sdump.
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if enum, ok := EnumByType(value.Type()); ok {
+			return enum.Format(value.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if enum, ok := EnumByType(value.Type()); ok {
+			return enum.Format(int64(value.Uint()))
+		}
+	case reflect.String:
+		return fmt.Sprintf("%q", value.String())
+	case reflect.Slice, reflect.Array:
+		switch value.Type().Elem().Kind() {
+		case reflect.Struct:
+			return fmt.Sprintf("%d elements", value.Len())
+		case reflect.Uint8:
+			return fmt.Sprintf("%d bytes", value.Len())
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// formatBytes formats encoded bytes in hex for Sdump, shortening them to maxDumpBytes.
+func formatBytes(bytes []byte) string {
+	if len(bytes) > maxDumpBytes {
+		return fmt.Sprintf("% x ... (%d bytes)", bytes[:maxDumpBytes], len(bytes))
+	}
+	return fmt.Sprintf("% x", bytes)
+}
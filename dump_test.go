@@ -0,0 +1,76 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"encoding/json"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+type testDumpPacket struct {
+	ID      testPacketID
+	Headers []testHeader
+	Name    string
+}
+
+func TestSdump(t *testing.T) {
+	RegisterEnum(NewEnum("PacketID", map[int64]string{1: "PacketLogin"}), testPacketID(0))
+	dump := Sdump(&testDumpPacket{ID: 1, Headers: []testHeader{{ID: 0x0102}}, Name: "steve"})
+	lines := strings.Split(dump, "\n")
+	assert.Equal(t, lines[0], "binutils.testDumpPacket (12 bytes)")
+	assert.Equal(t, lines[1], "0000  ID           PacketLogin (0x01)  01")
+	assert.Equal(t, lines[2], "0001  Headers      1 elements          01 02 01 00 00")
+	assert.Equal(t, strings.TrimSpace(lines[3]), "0002    [0]")
+	assert.Equal(t, lines[4], "0002      ID       258                 02 01")
+	assert.Equal(t, lines[7], "0006  Name         \"steve\"             05 73 74 65 76 65")
+
+	assert.Equal(t, Sdump(1), "binutils: cannot dump int, expected a struct")
+}
+
+func TestSdumpFormat(t *testing.T) {
+	RegisterEnum(NewEnum("PacketID", map[int64]string{1: "PacketLogin"}), testPacketID(0))
+	packet := &testDumpPacket{ID: 1, Headers: []testHeader{{ID: 0x0102}}, Name: "steve"}
+	var tree struct {
+		Type   string
+		Size   int
+		Fields []struct {
+			Name   string
+			Offset int
+			Value  string
+			Fields []struct {
+				Name   string
+				Fields []struct{ Name, Bytes string }
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal([]byte(SdumpFormat(packet, DumpJSON)), &tree))
+	assert.Equal(t, tree.Type, "binutils.testDumpPacket")
+	assert.Equal(t, tree.Size, 12)
+	assert.Equal(t, tree.Fields[2].Name, "Name")
+	assert.Equal(t, tree.Fields[2].Offset, 6)
+	assert.Equal(t, tree.Fields[2].Value, `"steve"`)
+	assert.Equal(t, tree.Fields[1].Fields[0].Name, "[0]")
+	assert.Equal(t, tree.Fields[1].Fields[0].Fields[0].Bytes, "02 01")
+
+	yaml := strings.Split(SdumpFormat(packet, DumpYAML), "\n")
+	assert.DeepEqual(t, yaml[:9], []string{
+		`type: "binutils.testDumpPacket"`,
+		`offset: 0`,
+		`size: 12`,
+		`fields:`,
+		`  - name: "ID"`,
+		`    offset: 0`,
+		`    size: 1`,
+		`    value: "PacketLogin (0x01)"`,
+		`    bytes: "01"`,
+	})
+
+	sexpr := strings.Split(SdumpFormat(packet, DumpSExpr), "\n")
+	assert.Equal(t, sexpr[0], `("binutils.testDumpPacket" :offset 0 :size 12`)
+	assert.Equal(t, sexpr[3], `    ("[0]" :offset 2 :size 4`)
+	assert.Equal(t, sexpr[4], `      ("ID" :offset 2 :size 2 :value "258" :bytes "02 01")`)
+
+	assert.Equal(t, SdumpFormat(1, DumpJSON), "binutils: cannot dump int, expected a struct")
+}
@@ -0,0 +1,98 @@
+package binutils
+
+import (
+	"unsafe"
+)
+
+// hostEndian is the byte order of the machine.
+var hostEndian = func() EndianType {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return LittleEndian
+	}
+	return BigEndian
+}()
+
+// Encoder encodes large homogeneous columns of fixed width values into a stream in bulk, for struct-of-arrays
+// layouts such as telemetry records. Each column is copied into the buffer with a single copy, and its byte order
+// swapped in place if it differs from the byte order of the machine, instead of appending values one by one.
+// Columns are written in the byte order of the stream, without a length prefix.
+type Encoder struct {
+	stream *Stream
+}
+
+// NewEncoder returns a new encoder writing to the stream.
+func NewEncoder(stream *Stream) *Encoder {
+	return &Encoder{stream}
+}
+
+// Stream returns the stream the encoder writes to.
+func (encoder *Encoder) Stream() *Stream {
+	return encoder.stream
+}
+
+// encodeColumn writes the values for the operation op.
+func encodeColumn[T Number](encoder *Encoder, op string, values []T) {
+	stream := encoder.stream
+	size := sizeOf[T]()
+	n := len(values) * size
+	if !stream.writable(op, n) || n == 0 {
+		return
+	}
+	stream.growFree(n)
+	start := len(stream.Buffer)
+	stream.Buffer = stream.Buffer[:start+n]
+	column := stream.Buffer[start:]
+	copy(column, unsafe.Slice((*byte)(unsafe.Pointer(&values[0])), n))
+	if stream.endian == hostEndian {
+		return
+	}
+	switch size {
+	case 2:
+		SwapUint16s(column)
+	case 4:
+		SwapUint32s(column)
+	case 8:
+		SwapUint64s(column)
+	}
+}
+
+// EncodeInt16Column writes a column of int16 values.
+func (encoder *Encoder) EncodeInt16Column(values []int16) {
+	encodeColumn(encoder, "EncodeInt16Column", values)
+}
+
+// EncodeUint16Column writes a column of uint16 values.
+func (encoder *Encoder) EncodeUint16Column(values []uint16) {
+	encodeColumn(encoder, "EncodeUint16Column", values)
+}
+
+// EncodeInt32Column writes a column of int32 values.
+func (encoder *Encoder) EncodeInt32Column(values []int32) {
+	encodeColumn(encoder, "EncodeInt32Column", values)
+}
+
+// EncodeUint32Column writes a column of uint32 values.
+func (encoder *Encoder) EncodeUint32Column(values []uint32) {
+	encodeColumn(encoder, "EncodeUint32Column", values)
+}
+
+// EncodeInt64Column writes a column of int64 values.
+func (encoder *Encoder) EncodeInt64Column(values []int64) {
+	encodeColumn(encoder, "EncodeInt64Column", values)
+}
+
+// EncodeUint64Column writes a column of uint64 values.
+func (encoder *Encoder) EncodeUint64Column(values []uint64) {
+	encodeColumn(encoder, "EncodeUint64Column", values)
+}
+
+// EncodeFloat32Column writes a column of float32 values.
+func (encoder *Encoder) EncodeFloat32Column(values []float32) {
+	encodeColumn(encoder, "EncodeFloat32Column", encodeFloats(encoder.stream, values))
+}
+
+// EncodeFloat64Column writes a column of float64 values.
+func (encoder *Encoder) EncodeFloat64Column(values []float64) {
+	encodeColumn(encoder, "EncodeFloat64Column", encodeFloats(encoder.stream, values))
+}
@@ -0,0 +1,52 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestEncoderColumns(t *testing.T) {
+	for _, endian := range []EndianType{BigEndian, LittleEndian} {
+		stream := NewStream()
+		stream.SetEndianness(endian)
+		encoder := NewEncoder(stream)
+		encoder.EncodeInt16Column([]int16{-1, 2})
+		encoder.EncodeUint16Column([]uint16{0x0102})
+		encoder.EncodeInt32Column([]int32{-3})
+		encoder.EncodeUint32Column(nil)
+		encoder.EncodeUint32Column([]uint32{0x01020304, 5})
+		encoder.EncodeInt64Column([]int64{math.MinInt64})
+		encoder.EncodeUint64Column([]uint64{7})
+		encoder.EncodeFloat32Column([]float32{1.5})
+		encoder.EncodeFloat64Column([]float64{-0.25})
+
+		expected := NewStream()
+		expected.SetEndianness(endian)
+		expected.PutShort(-1)
+		expected.PutShort(2)
+		expected.PutUnsignedShort(0x0102)
+		expected.PutInt(-3)
+		expected.PutUnsignedInt(0x01020304)
+		expected.PutUnsignedInt(5)
+		expected.PutLong(math.MinInt64)
+		expected.PutUnsignedLong(7)
+		expected.PutFloat(1.5)
+		expected.PutDouble(-0.25)
+		assert.DeepEqual(t, encoder.Stream().Buffer, expected.Buffer)
+	}
+}
+
+func BenchmarkEncodeUint32Column(b *testing.B) {
+	values := make([]uint32, 1<<16)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	stream := NewStreamWithCapacity(len(values) * 4)
+	encoder := NewEncoder(stream)
+	b.SetBytes(int64(len(values) * 4))
+	for i := 0; i < b.N; i++ {
+		stream.Reset()
+		encoder.EncodeUint32Column(values)
+	}
+}
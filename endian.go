@@ -0,0 +1,375 @@
+package binutils
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// String returns the name of the byte order.
+func (endian EndianType) String() string {
+	if endian == LittleEndian {
+		return "little endian"
+	}
+	return "big endian"
+}
+
+// WriteShortEndian writes a int16 to the buffer in the given byte order.
+func WriteShortEndian(buffer *[]byte, v int16, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleShort(buffer, v)
+		return
+	}
+	WriteShort(buffer, v)
+}
+
+// ReadShortEndian reads a int16 from the buffer in the given byte order.
+func ReadShortEndian(buffer *[]byte, offset *int, endian EndianType) int16 {
+	if endian == LittleEndian {
+		return ReadLittleShort(buffer, offset)
+	}
+	return ReadShort(buffer, offset)
+}
+
+// WriteUnsignedShortEndian writes a uint16 to the buffer in the given byte order.
+func WriteUnsignedShortEndian(buffer *[]byte, v uint16, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleUnsignedShort(buffer, v)
+		return
+	}
+	WriteUnsignedShort(buffer, v)
+}
+
+// ReadUnsignedShortEndian reads a uint16 from the buffer in the given byte order.
+func ReadUnsignedShortEndian(buffer *[]byte, offset *int, endian EndianType) uint16 {
+	if endian == LittleEndian {
+		return ReadLittleUnsignedShort(buffer, offset)
+	}
+	return ReadUnsignedShort(buffer, offset)
+}
+
+// WriteIntEndian writes a int32 to the buffer in the given byte order.
+func WriteIntEndian(buffer *[]byte, v int32, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleInt(buffer, v)
+		return
+	}
+	WriteInt(buffer, v)
+}
+
+// ReadIntEndian reads a int32 from the buffer in the given byte order.
+func ReadIntEndian(buffer *[]byte, offset *int, endian EndianType) int32 {
+	if endian == LittleEndian {
+		return ReadLittleInt(buffer, offset)
+	}
+	return ReadInt(buffer, offset)
+}
+
+// WriteUnsignedIntEndian writes a uint32 to the buffer in the given byte order.
+func WriteUnsignedIntEndian(buffer *[]byte, v uint32, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleUnsignedInt(buffer, v)
+		return
+	}
+	WriteUnsignedInt(buffer, v)
+}
+
+// ReadUnsignedIntEndian reads a uint32 from the buffer in the given byte order.
+func ReadUnsignedIntEndian(buffer *[]byte, offset *int, endian EndianType) uint32 {
+	if endian == LittleEndian {
+		return ReadLittleUnsignedInt(buffer, offset)
+	}
+	return ReadUnsignedInt(buffer, offset)
+}
+
+// WriteLongEndian writes a int64 to the buffer in the given byte order.
+func WriteLongEndian(buffer *[]byte, v int64, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleLong(buffer, v)
+		return
+	}
+	WriteLong(buffer, v)
+}
+
+// ReadLongEndian reads a int64 from the buffer in the given byte order.
+func ReadLongEndian(buffer *[]byte, offset *int, endian EndianType) int64 {
+	if endian == LittleEndian {
+		return ReadLittleLong(buffer, offset)
+	}
+	return ReadLong(buffer, offset)
+}
+
+// WriteUnsignedLongEndian writes a uint64 to the buffer in the given byte order.
+func WriteUnsignedLongEndian(buffer *[]byte, v uint64, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleUnsignedLong(buffer, v)
+		return
+	}
+	WriteUnsignedLong(buffer, v)
+}
+
+// ReadUnsignedLongEndian reads a uint64 from the buffer in the given byte order.
+func ReadUnsignedLongEndian(buffer *[]byte, offset *int, endian EndianType) uint64 {
+	if endian == LittleEndian {
+		return ReadLittleUnsignedLong(buffer, offset)
+	}
+	return ReadUnsignedLong(buffer, offset)
+}
+
+// WriteFloatEndian writes a float32 to the buffer in the given byte order.
+func WriteFloatEndian(buffer *[]byte, v float32, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleFloat(buffer, v)
+		return
+	}
+	WriteFloat(buffer, v)
+}
+
+// ReadFloatEndian reads a float32 from the buffer in the given byte order.
+func ReadFloatEndian(buffer *[]byte, offset *int, endian EndianType) float32 {
+	if endian == LittleEndian {
+		return ReadLittleFloat(buffer, offset)
+	}
+	return ReadFloat(buffer, offset)
+}
+
+// WriteDoubleEndian writes a float64 to the buffer in the given byte order.
+func WriteDoubleEndian(buffer *[]byte, v float64, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleDouble(buffer, v)
+		return
+	}
+	WriteDouble(buffer, v)
+}
+
+// ReadDoubleEndian reads a float64 from the buffer in the given byte order.
+func ReadDoubleEndian(buffer *[]byte, offset *int, endian EndianType) float64 {
+	if endian == LittleEndian {
+		return ReadLittleDouble(buffer, offset)
+	}
+	return ReadDouble(buffer, offset)
+}
+
+// WriteTriadEndian writes a triad to the buffer in the given byte order.
+func WriteTriadEndian(buffer *[]byte, v uint32, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleTriad(buffer, v)
+		return
+	}
+	WriteBigTriad(buffer, v)
+}
+
+// ReadTriadEndian reads a triad from the buffer in the given byte order.
+func ReadTriadEndian(buffer *[]byte, offset *int, endian EndianType) uint32 {
+	if endian == LittleEndian {
+		return ReadLittleTriad(buffer, offset)
+	}
+	return ReadBigTriad(buffer, offset)
+}
+
+// WriteInt24Endian writes a signed 24 bit integer to the buffer in the given byte order.
+func WriteInt24Endian(buffer *[]byte, v int32, endian EndianType) {
+	if endian == LittleEndian {
+		WriteLittleInt24(buffer, v)
+		return
+	}
+	WriteInt24(buffer, v)
+}
+
+// ReadInt24Endian reads a signed 24 bit integer from the buffer in the given byte order.
+func ReadInt24Endian(buffer *[]byte, offset *int, endian EndianType) int32 {
+	if endian == LittleEndian {
+		return ReadLittleInt24(buffer, offset)
+	}
+	return ReadInt24(buffer, offset)
+}
+
+// SwapUint16s reverses the byte order of every 16 bit value in the buffer in place.
+// Trailing bytes not forming a full value are left unchanged.
+func SwapUint16s(buffer []byte) {
+	for i := 0; i+2 <= len(buffer); i += 2 {
+		binary.LittleEndian.PutUint16(buffer[i:], bits.ReverseBytes16(binary.LittleEndian.Uint16(buffer[i:])))
+	}
+}
+
+// SwapUint32s reverses the byte order of every 32 bit value in the buffer in place.
+// Trailing bytes not forming a full value are left unchanged.
+func SwapUint32s(buffer []byte) {
+	for i := 0; i+4 <= len(buffer); i += 4 {
+		binary.LittleEndian.PutUint32(buffer[i:], bits.ReverseBytes32(binary.LittleEndian.Uint32(buffer[i:])))
+	}
+}
+
+// SwapUint64s reverses the byte order of every 64 bit value in the buffer in place.
+// Trailing bytes not forming a full value are left unchanged.
+func SwapUint64s(buffer []byte) {
+	for i := 0; i+8 <= len(buffer); i += 8 {
+		binary.LittleEndian.PutUint64(buffer[i:], bits.ReverseBytes64(binary.LittleEndian.Uint64(buffer[i:])))
+	}
+}
+
+// NewStreamEndian returns a new stream using the given byte order for the Get and Put functions without an explicit one.
+func NewStreamEndian(endian EndianType) *Stream {
+	return &Stream{Buffer: []byte{}, endian: endian}
+}
+
+// PutShortEndian writes a int16 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutShortEndian(v int16, endian EndianType) {
+	if !stream.writable("PutShortEndian", 2) {
+		return
+	}
+	WriteShortEndian(&stream.Buffer, v, endian)
+}
+
+// GetShortEndian reads a int16 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetShortEndian(endian EndianType) int16 {
+	if !stream.check("GetShortEndian", 2) {
+		return 0
+	}
+	return ReadShortEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutUnsignedShortEndian writes a uint16 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutUnsignedShortEndian(v uint16, endian EndianType) {
+	if !stream.writable("PutUnsignedShortEndian", 2) {
+		return
+	}
+	WriteUnsignedShortEndian(&stream.Buffer, v, endian)
+}
+
+// GetUnsignedShortEndian reads a uint16 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetUnsignedShortEndian(endian EndianType) uint16 {
+	if !stream.check("GetUnsignedShortEndian", 2) {
+		return 0
+	}
+	return ReadUnsignedShortEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutIntEndian writes a int32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutIntEndian(v int32, endian EndianType) {
+	if !stream.writable("PutIntEndian", 4) {
+		return
+	}
+	WriteIntEndian(&stream.Buffer, v, endian)
+}
+
+// GetIntEndian reads a int32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetIntEndian(endian EndianType) int32 {
+	if !stream.check("GetIntEndian", 4) {
+		return 0
+	}
+	return ReadIntEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutUnsignedIntEndian writes a uint32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutUnsignedIntEndian(v uint32, endian EndianType) {
+	if !stream.writable("PutUnsignedIntEndian", 4) {
+		return
+	}
+	WriteUnsignedIntEndian(&stream.Buffer, v, endian)
+}
+
+// GetUnsignedIntEndian reads a uint32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetUnsignedIntEndian(endian EndianType) uint32 {
+	if !stream.check("GetUnsignedIntEndian", 4) {
+		return 0
+	}
+	return ReadUnsignedIntEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutLongEndian writes a int64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutLongEndian(v int64, endian EndianType) {
+	if !stream.writable("PutLongEndian", 8) {
+		return
+	}
+	WriteLongEndian(&stream.Buffer, v, endian)
+}
+
+// GetLongEndian reads a int64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetLongEndian(endian EndianType) int64 {
+	if !stream.check("GetLongEndian", 8) {
+		return 0
+	}
+	return ReadLongEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutUnsignedLongEndian writes a uint64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutUnsignedLongEndian(v uint64, endian EndianType) {
+	if !stream.writable("PutUnsignedLongEndian", 8) {
+		return
+	}
+	WriteUnsignedLongEndian(&stream.Buffer, v, endian)
+}
+
+// GetUnsignedLongEndian reads a uint64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetUnsignedLongEndian(endian EndianType) uint64 {
+	if !stream.check("GetUnsignedLongEndian", 8) {
+		return 0
+	}
+	return ReadUnsignedLongEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutFloatEndian writes a float32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutFloatEndian(v float32, endian EndianType) {
+	if !stream.writable("PutFloatEndian", 4) {
+		return
+	}
+	WriteFloatEndian(&stream.Buffer, encodeFloat(stream, v), endian)
+}
+
+// GetFloatEndian reads a float32 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetFloatEndian(endian EndianType) float32 {
+	if !stream.check("GetFloatEndian", 4) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetFloatEndian", offset, ReadFloatEndian(&stream.Buffer, &stream.Offset, endian))
+}
+
+// PutDoubleEndian writes a float64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutDoubleEndian(v float64, endian EndianType) {
+	if !stream.writable("PutDoubleEndian", 8) {
+		return
+	}
+	WriteDoubleEndian(&stream.Buffer, encodeFloat(stream, v), endian)
+}
+
+// GetDoubleEndian reads a float64 in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetDoubleEndian(endian EndianType) float64 {
+	if !stream.check("GetDoubleEndian", 8) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetDoubleEndian", offset, ReadDoubleEndian(&stream.Buffer, &stream.Offset, endian))
+}
+
+// PutTriadEndian writes a triad in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutTriadEndian(v uint32, endian EndianType) {
+	if !stream.writable("PutTriadEndian", 3) {
+		return
+	}
+	WriteTriadEndian(&stream.Buffer, v, endian)
+}
+
+// GetTriadEndian reads a triad in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetTriadEndian(endian EndianType) uint32 {
+	if !stream.check("GetTriadEndian", 3) {
+		return 0
+	}
+	return ReadTriadEndian(&stream.Buffer, &stream.Offset, endian)
+}
+
+// PutInt24Endian writes a signed 24 bit integer in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) PutInt24Endian(v int32, endian EndianType) {
+	if !stream.writable("PutInt24Endian", 3) {
+		return
+	}
+	WriteInt24Endian(&stream.Buffer, v, endian)
+}
+
+// GetInt24Endian reads a signed 24 bit integer in the given byte order, regardless of the byte order of the stream.
+func (stream *Stream) GetInt24Endian(endian EndianType) int32 {
+	if !stream.check("GetInt24Endian", 3) {
+		return 0
+	}
+	return ReadInt24Endian(&stream.Buffer, &stream.Offset, endian)
+}
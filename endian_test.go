@@ -0,0 +1,93 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestStreamEndianness(t *testing.T) {
+	stream := NewStream()
+	stream.SetEndianness(LittleEndian)
+	stream.PutShort(-2)
+	stream.PutUnsignedInt(0x01020304)
+	stream.PutDouble(1.5)
+	stream.PutTriad(0x010203)
+	stream.PutInt(7)
+
+	expected := []byte{}
+	WriteLittleShort(&expected, -2)
+	WriteLittleUnsignedInt(&expected, 0x01020304)
+	WriteLittleDouble(&expected, 1.5)
+	WriteLittleTriad(&expected, 0x010203)
+	WriteIntEndian(&expected, 7, LittleEndian)
+	assert.DeepEqual(t, stream.Buffer, expected)
+
+	assert.Equal(t, stream.GetShort(), int16(-2))
+	assert.Equal(t, stream.GetUnsignedInt(), uint32(0x01020304))
+	assert.Equal(t, stream.GetDouble(), 1.5)
+	assert.Equal(t, stream.GetTriad(), uint32(0x010203))
+
+	stream.SetEndianness(BigEndian)
+	assert.Equal(t, stream.GetInt(), int32(0x07000000))
+}
+
+func TestSwap(t *testing.T) {
+	buffer := b(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	SwapUint16s(buffer)
+	assert.DeepEqual(t, buffer, b(2, 1, 4, 3, 6, 5, 8, 7, 9))
+	SwapUint32s(buffer)
+	assert.DeepEqual(t, buffer, b(3, 4, 1, 2, 7, 8, 5, 6, 9))
+	SwapUint64s(buffer)
+	assert.DeepEqual(t, buffer, b(6, 5, 8, 7, 2, 1, 4, 3, 9))
+}
+
+func TestTriadHighByte(t *testing.T) {
+	stream := NewStream()
+	stream.PutTriad(0xfedcba)
+	stream.PutLittleTriad(0xfedcba)
+	assert.Equal(t, stream.GetTriad(), uint32(0xfedcba))
+	assert.Equal(t, stream.GetLittleTriad(), uint32(0xfedcba))
+
+	LegacyTriads = true
+	defer func() { LegacyTriads = false }()
+	stream.Offset = 0
+	assert.Equal(t, stream.GetTriad(), uint32(0x0edcba))
+	assert.Equal(t, stream.GetLittleTriad(), uint32(0x0edcba))
+}
+
+func TestInt24(t *testing.T) {
+	for _, endian := range []EndianType{BigEndian, LittleEndian} {
+		stream := NewStream()
+		stream.SetEndianness(endian)
+		for _, v := range []int32{0, 1, -1, 1<<23 - 1, -1 << 23} {
+			stream.PutInt24(v)
+			stream.PutLittleInt24(v)
+			assert.Equal(t, stream.GetInt24(), v)
+			assert.Equal(t, stream.GetLittleInt24(), v)
+		}
+	}
+	buffer := []byte{}
+	WriteInt24(&buffer, -2)
+	WriteLittleInt24(&buffer, -2)
+	assert.DeepEqual(t, buffer, b(0xff, 0xff, 0xfe, 0xfe, 0xff, 0xff))
+}
+
+func TestEndianOverride(t *testing.T) {
+	stream := NewStreamEndian(LittleEndian)
+	assert.Equal(t, stream.GetEndianness(), LittleEndian)
+	stream.PutInt(1)
+	stream.PutUnsignedIntEndian(0x01020304, BigEndian)
+	stream.PutShortEndian(-2, LittleEndian)
+	stream.PutTriadEndian(0x010203, BigEndian)
+	stream.PutDoubleEndian(1, BigEndian)
+	assert.DeepEqual(t, stream.Buffer, b(0x01, 0, 0, 0, 0x01, 0x02, 0x03, 0x04, 0xfe, 0xff, 0x01, 0x02, 0x03, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0))
+
+	assert.Equal(t, stream.GetIntEndian(BigEndian), int32(0x01000000))
+	assert.Equal(t, stream.GetUnsignedIntEndian(LittleEndian), uint32(0x04030201))
+	assert.Equal(t, stream.GetShortEndian(LittleEndian), int16(-2))
+	assert.Equal(t, stream.GetTriadEndian(BigEndian), uint32(0x010203))
+	assert.Equal(t, stream.GetDoubleEndian(BigEndian), float64(1))
+	assert.Equal(t, stream.GetInt24Endian(BigEndian), int32(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
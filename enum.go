@@ -0,0 +1,90 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Enum maps the values of an enumeration, such as packet IDs, to their names.
+// Registered enums are used to show names instead of raw numbers when dumping decoded values.
+type Enum struct {
+	name   string
+	names  map[int64]string
+	values map[string]int64
+}
+
+// NewEnum returns a new enum with the given name, mapping values to their names.
+func NewEnum(name string, names map[int64]string) *Enum {
+	enum := &Enum{name, make(map[int64]string, len(names)), make(map[string]int64, len(names))}
+	for value, valueName := range names {
+		enum.names[value] = valueName
+		enum.values[valueName] = value
+	}
+	return enum
+}
+
+// Name returns the name of the enum.
+func (enum *Enum) Name() string {
+	return enum.name
+}
+
+// ValueName returns the name of the value, and whether the value is part of the enum.
+func (enum *Enum) ValueName(value int64) (string, bool) {
+	name, ok := enum.names[value]
+	return name, ok
+}
+
+// Value returns the value with the given name, and whether the name is part of the enum.
+func (enum *Enum) Value(name string) (int64, bool) {
+	value, ok := enum.values[name]
+	return value, ok
+}
+
+// Format formats the value with its name, such as "PacketLogin (0x01)".
+// Values that are not part of the enum are formatted as number only.
+func (enum *Enum) Format(value int64) string {
+	number := fmt.Sprintf("0x%02x", value)
+	if value < 0 {
+		number = fmt.Sprint(value)
+	}
+	if name, ok := enum.names[value]; ok {
+		return name + " (" + number + ")"
+	}
+	return number
+}
+
+var (
+	enumMutex   sync.RWMutex
+	enumsByName = make(map[string]*Enum)
+	enumsByType = make(map[reflect.Type]*Enum)
+)
+
+// RegisterEnum registers the enum by its name, and for the types of the given samples,
+// so that values of those types are shown by name.
+func RegisterEnum(enum *Enum, samples ...interface{}) {
+	enumMutex.Lock()
+	defer enumMutex.Unlock()
+	enumsByName[enum.name] = enum
+	for _, sample := range samples {
+		enumsByType[reflect.TypeOf(sample)] = enum
+	}
+}
+
+// EnumByName returns the enum registered with the given name.
+func EnumByName(name string) (*Enum, bool) {
+	enumMutex.RLock()
+	defer enumMutex.RUnlock()
+	enum, ok := enumsByName[name]
+	return enum, ok
+}
+
+// EnumByType returns the enum registered for the given type.
+func EnumByType(t reflect.Type) (*Enum, bool) {
+	enumMutex.RLock()
+	defer enumMutex.RUnlock()
+	enum, ok := enumsByType[t]
+	return enum, ok
+}
@@ -0,0 +1,32 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"reflect"
+	"testing"
+)
+
+type testPacketID uint8
+
+func TestEnum(t *testing.T) {
+	enum := NewEnum("PacketID", map[int64]string{1: "PacketLogin", 2: "PacketPlay"})
+	RegisterEnum(enum, testPacketID(0))
+
+	assert.Equal(t, enum.Format(1), "PacketLogin (0x01)")
+	assert.Equal(t, enum.Format(9), "0x09")
+	assert.Equal(t, enum.Format(-1), "-1")
+
+	value, ok := enum.Value("PacketPlay")
+	assert.Assert(t, ok)
+	assert.Equal(t, value, int64(2))
+
+	registered, ok := EnumByType(reflect.TypeOf(testPacketID(0)))
+	assert.Assert(t, ok)
+	assert.Equal(t, registered, enum)
+
+	registered, ok = EnumByName("PacketID")
+	assert.Assert(t, ok)
+	assert.Equal(t, registered.Name(), "PacketID")
+}
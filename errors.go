@@ -0,0 +1,65 @@
+package binutils
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnexpectedEOF is used when a read runs past the end of the buffer.
+	ErrUnexpectedEOF = errors.New("unexpected end of buffer")
+	// ErrMalformedVarInt is used when a varint is longer than its maximum encoded size.
+	ErrMalformedVarInt = errors.New("malformed varint")
+	// ErrNegativeLength is used when a length prefix is negative.
+	ErrNegativeLength = errors.New("negative length")
+	// ErrLimitExceeded is used when a value exceeds a limit configured on a stream.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrChecksumMismatch is used when a checksum does not match the data it covers.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrUnknownPacket is used when a packet ID is not registered.
+	ErrUnknownPacket = errors.New("unknown packet")
+	// ErrUnexpectedPacket is used when a packet is not allowed in the current protocol state.
+	ErrUnexpectedPacket = errors.New("unexpected packet")
+	// ErrFrozen is used when a frozen stream is written to.
+	ErrFrozen = errors.New("stream is frozen")
+	// ErrInvalidAddress is used when an address has an unknown IP version or cannot be encoded.
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrNonCanonicalFloat is used when a strict stream reads a NaN or negative zero other than the canonical encoding.
+	ErrNonCanonicalFloat = errors.New("non-canonical float")
+	// ErrWriterClosed is used when a frame is written to a closed async writer.
+	ErrWriterClosed = errors.New("async writer closed")
+)
+
+// StreamError is an error that occurred during an operation on a Stream.
+// It carries the name of the operation and the offset at which it started.
+type StreamError struct {
+	Op     string
+	Offset int
+	Err    error
+}
+
+func (err *StreamError) Error() string {
+	return fmt.Sprintf("binutils: %s at offset %d: %v", err.Op, err.Offset, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *StreamError) Unwrap() error {
+	return err.Err
+}
+
+// ValidationError is used when a decoded value breaks an invariant checked by a validation rule,
+// such as a count field not matching the length of a slice.
+type ValidationError struct {
+	// Rule is the name of the rule broken.
+	Rule string
+	Err  error
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("validation %s failed: %v", err.Rule, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *ValidationError) Unwrap() error {
+	return err.Err
+}
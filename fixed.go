@@ -0,0 +1,75 @@
+package binutils
+
+import (
+	"fmt"
+	"math"
+)
+
+// checkFixed checks if width and fracBits describe a signed fixed-point format for the operation op,
+// setting an error on the stream otherwise.
+func (stream *Stream) checkFixed(op string, fracBits, width int) bool {
+	switch width {
+	case 8, 16, 24, 32, 64:
+		if fracBits >= 0 && fracBits < width {
+			return true
+		}
+	}
+	stream.SetError(&StreamError{op, stream.Offset, fmt.Errorf("invalid fixed-point format with %d fraction bits in %d bits", fracBits, width)})
+	return false
+}
+
+// PutFixed writes the value as a signed fixed-point number of width bits, which is one of 8, 16, 24, 32 or 64,
+// with fracBits fraction bits, such as Q4.12 for a width of 16 and 12 fraction bits. The value is rounded to the
+// nearest representable number, and values out of range are clamped to the smallest or largest number.
+// Numbers are written as two's complement integers in the byte order of the stream.
+func (stream *Stream) PutFixed(value float64, fracBits, width int) {
+	if !stream.checkFixed("PutFixed", fracBits, width) {
+		return
+	}
+	scaled := math.Round(math.Ldexp(value, fracBits))
+	lowest, highest := -math.Ldexp(1, width-1), math.Ldexp(1, width-1)-1
+	var v int64
+	switch {
+	case math.IsNaN(scaled):
+	case scaled <= lowest:
+		v = int64(lowest)
+	case scaled >= highest:
+		// The largest 64 bit number is not representable as float64, so it is computed separately.
+		v = math.MaxInt64 >> (64 - width)
+	default:
+		v = int64(scaled)
+	}
+	switch width {
+	case 8:
+		stream.PutByte(byte(v))
+	case 16:
+		stream.PutShort(int16(v))
+	case 24:
+		stream.PutInt24(int32(v))
+	case 32:
+		stream.PutInt(int32(v))
+	default:
+		stream.PutLong(v)
+	}
+}
+
+// GetFixed reads a signed fixed-point number written by PutFixed.
+func (stream *Stream) GetFixed(fracBits, width int) float64 {
+	if !stream.checkFixed("GetFixed", fracBits, width) {
+		return 0
+	}
+	var v int64
+	switch width {
+	case 8:
+		v = int64(int8(stream.GetByte()))
+	case 16:
+		v = int64(stream.GetShort())
+	case 24:
+		v = int64(stream.GetInt24())
+	case 32:
+		v = int64(stream.GetInt())
+	default:
+		v = stream.GetLong()
+	}
+	return math.Ldexp(float64(v), -fracBits)
+}
@@ -0,0 +1,30 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestFixed(t *testing.T) {
+	stream := NewStream()
+	stream.PutFixed(1.5, 12, 16)
+	stream.PutFixed(-0.1, 8, 24)
+	stream.PutFixed(1000, 4, 8)
+	stream.PutFixed(-1000, 4, 8)
+	stream.PutFixed(math.Inf(1), 16, 64)
+	stream.PutFixed(-2.25, 16, 32)
+	assert.DeepEqual(t, stream.Buffer[:3], b(0x18, 0x00, 0xff))
+	assert.NilError(t, stream.Err())
+
+	assert.Equal(t, stream.GetFixed(12, 16), 1.5)
+	assert.Equal(t, stream.GetFixed(8, 24), -26.0/256)
+	assert.Equal(t, stream.GetFixed(4, 8), 127.0/16)
+	assert.Equal(t, stream.GetFixed(4, 8), -8.0)
+	assert.Equal(t, stream.GetFixed(16, 64), math.Ldexp(math.MaxInt64, -16))
+	assert.Equal(t, stream.GetFixed(16, 32), -2.25)
+	assert.NilError(t, stream.Err())
+
+	stream.PutFixed(1, 16, 16)
+	assert.ErrorContains(t, stream.Err(), "invalid fixed-point format")
+}
@@ -0,0 +1,108 @@
+package binutils
+
+import "math"
+
+// FloatMode controls how a stream encodes and decodes floating point numbers.
+type FloatMode int
+
+const (
+	// FloatsAsIs writes and reads floats bit for bit. It is the default.
+	FloatsAsIs FloatMode = iota
+	// FloatsCanonical writes every NaN as the canonical quiet NaN and negative zero as zero,
+	// so that equal values always have the same encoding, such as for hashes in consensus formats.
+	FloatsCanonical
+	// FloatsStrict writes floats like FloatsCanonical, and rejects reading any other NaN or negative zero
+	// with ErrNonCanonicalFloat, so that every accepted encoding is the one FloatsCanonical writes.
+	FloatsStrict
+)
+
+const (
+	// canonicalNaN32 and canonicalNaN64 are the bits of the quiet NaNs without payload and sign.
+	canonicalNaN32 = 0x7fc00000
+	canonicalNaN64 = 0x7ff8000000000000
+)
+
+// SetFloatMode sets how floats are encoded and decoded by the Float and Double functions, float slices,
+// PutNumber, GetNumber and the float columns of an Encoder.
+func (stream *Stream) SetFloatMode(mode FloatMode) {
+	stream.floatMode = mode
+}
+
+// GetFloatMode returns how floats are encoded and decoded by the stream.
+func (stream *Stream) GetFloatMode() FloatMode {
+	return stream.floatMode
+}
+
+// canonical returns the canonical value of v: the canonical quiet NaN for NaNs and zero for negative zero.
+// Integers are returned unchanged.
+func canonical[T Number](v T) T {
+	switch {
+	case v != v && sizeOf[T]() == 4:
+		return T(math.Float32frombits(canonicalNaN32))
+	case v != v:
+		return T(math.Float64frombits(canonicalNaN64))
+	case v == 0:
+		return 0
+	}
+	return v
+}
+
+// isCanonical checks if v is encoded as its canonical value.
+func isCanonical[T Number](v T) bool {
+	if !isFloat[T]() {
+		return true
+	}
+	if sizeOf[T]() == 4 {
+		return math.Float32bits(float32(v)) == math.Float32bits(float32(canonical(v)))
+	}
+	return math.Float64bits(float64(v)) == math.Float64bits(float64(canonical(v)))
+}
+
+// encodeFloat returns the value to write for v in the float mode of the stream.
+func encodeFloat[T Number](stream *Stream, v T) T {
+	if stream.floatMode == FloatsAsIs {
+		return v
+	}
+	return canonical(v)
+}
+
+// encodeFloats returns the values to write in the float mode of the stream, copying them only if any changes.
+func encodeFloats[T Number](stream *Stream, values []T) []T {
+	if stream.floatMode == FloatsAsIs {
+		return values
+	}
+	for i, v := range values {
+		if !isCanonical(v) {
+			values = append([]T(nil), values...)
+			for j := i; j < len(values); j++ {
+				values[j] = canonical(values[j])
+			}
+			break
+		}
+	}
+	return values
+}
+
+// decodeFloat returns v read by the operation op at offset, setting ErrNonCanonicalFloat on the stream
+// if it is not canonical and the stream is strict.
+func decodeFloat[T Number](stream *Stream, op string, offset int, v T) T {
+	if stream.floatMode == FloatsStrict && !isCanonical(v) {
+		stream.SetError(&StreamError{op, offset, ErrNonCanonicalFloat})
+		return 0
+	}
+	return v
+}
+
+// decodeFloats checks the values read by the operation op at offset like decodeFloat.
+func decodeFloats[T Number](stream *Stream, op string, offset int, values []T) []T {
+	if stream.floatMode != FloatsStrict {
+		return values
+	}
+	for _, v := range values {
+		if !isCanonical(v) {
+			stream.SetError(&StreamError{op, offset, ErrNonCanonicalFloat})
+			return nil
+		}
+	}
+	return values
+}
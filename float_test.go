@@ -0,0 +1,70 @@
+package binutils
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestFloatModeCanonical(t *testing.T) {
+	payloadNaN := math.Float64frombits(0xfff8000000000123)
+	negativeZero := math.Copysign(0, -1)
+
+	stream := NewStream()
+	stream.PutDouble(negativeZero)
+	assert.DeepEqual(t, stream.Buffer, b(0x80, 0, 0, 0, 0, 0, 0, 0))
+
+	stream.Reset()
+	stream.SetFloatMode(FloatsCanonical)
+	assert.Equal(t, stream.GetFloatMode(), FloatsCanonical)
+	stream.PutDouble(negativeZero)
+	stream.PutDouble(payloadNaN)
+	stream.PutLittleFloat(float32(math.Inf(-1)) * 0)
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 0, 0, 0, 0, 0, 0x7f, 0xf8, 0, 0, 0, 0, 0, 0, 0, 0, 0xc0, 0x7f))
+
+	values := []float64{1, payloadNaN}
+	stream.Reset()
+	stream.PutDoubleSlice(values)
+	PutNumber(stream, float32(negativeZero))
+	NewEncoder(stream).EncodeFloat64Column(values)
+	assert.Equal(t, math.Float64bits(values[1]), uint64(0xfff8000000000123))
+	assert.DeepEqual(t, stream.Buffer[9:17], b(0x7f, 0xf8, 0, 0, 0, 0, 0, 0))
+	assert.DeepEqual(t, stream.Buffer[17:21], b(0, 0, 0, 0))
+	assert.DeepEqual(t, stream.Buffer[29:], b(0x7f, 0xf8, 0, 0, 0, 0, 0, 0))
+}
+
+func TestFloatModeStrict(t *testing.T) {
+	stream := NewStream()
+	stream.PutDouble(math.Copysign(0, -1))
+	stream.PutFloat(float32(math.NaN()))
+	stream.PutDoubleSlice([]float64{math.Float64frombits(0x7ff0000000000001)})
+
+	reader := &Stream{Buffer: stream.Buffer}
+	assert.Equal(t, math.Signbit(reader.GetDouble()), true)
+	assert.Assert(t, math.IsNaN(float64(reader.GetFloat())))
+	assert.Equal(t, len(reader.GetDoubleSlice()), 1)
+	assert.NilError(t, reader.Err())
+
+	reader = &Stream{Buffer: stream.Buffer}
+	reader.SetFloatMode(FloatsStrict)
+	assert.Equal(t, reader.GetDouble(), float64(0))
+	var err *StreamError
+	assert.Assert(t, errors.As(reader.Err(), &err))
+	assert.Equal(t, err.Op, "GetDouble")
+	assert.Assert(t, errors.Is(err, ErrNonCanonicalFloat))
+
+	reader = &Stream{Buffer: stream.Buffer[12:]}
+	reader.SetFloatMode(FloatsStrict)
+	assert.Assert(t, reader.GetDoubleSlice() == nil)
+	assert.Assert(t, errors.Is(reader.Err(), ErrNonCanonicalFloat))
+
+	canonical := NewStream()
+	canonical.SetFloatMode(FloatsStrict)
+	canonical.PutFloat(float32(math.NaN()))
+	canonical.PutDouble(math.Copysign(0, -1))
+	assert.Assert(t, math.IsNaN(float64(canonical.GetFloat())))
+	assert.Equal(t, math.Signbit(canonical.GetDouble()), false)
+	assert.NilError(t, canonical.Err())
+}
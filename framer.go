@@ -0,0 +1,197 @@
+package binutils
+
+import (
+	"io"
+)
+
+// DefaultMaxFrameSize is the maximum frame size of framers without a configured maximum.
+const DefaultMaxFrameSize = 1 << 20
+
+// Framer reads and writes frames prefixed with their length on a connection, such as a net.Conn.
+// Bytes read past the end of a frame are buffered for the next frames.
+type Framer struct {
+	conn     io.ReadWriter
+	prefix   PrefixType
+	maxSize  int
+	buffer   *Stream
+	async    *AsyncWriter
+	recorder *Recorder
+	pipeline Pipeline
+	tracer   Tracer
+}
+
+// NewFramer returns a new framer on the connection, prefixing frames with their length as the given prefix type.
+// The maximum frame size is DefaultMaxFrameSize, or the largest length the prefix type holds if smaller.
+func NewFramer(conn io.ReadWriter, prefix PrefixType) *Framer {
+	return &Framer{conn: conn, prefix: prefix, maxSize: min(DefaultMaxFrameSize, prefixMax(prefix)), buffer: NewStreamWithCapacity(readFromChunk)}
+}
+
+// SetMaxFrameSize sets the maximum size of frames read and written, capped at the largest length the prefix type holds.
+// Larger frames are rejected with a *LengthError.
+func (framer *Framer) SetMaxFrameSize(size int) {
+	framer.maxSize = min(size, prefixMax(framer.prefix))
+}
+
+// GetMaxFrameSize returns the maximum size of frames.
+func (framer *Framer) GetMaxFrameSize() int {
+	return framer.maxSize
+}
+
+// SetPipeline sets the pipeline applied to every frame, such as compression or encryption configured once
+// per connection. Frames are encoded before they are prefixed with their length, so that the maximum frame size
+// applies to the encoded frames, and decoded after they are read. Frames are recorded unencoded.
+func (framer *Framer) SetPipeline(pipeline Pipeline) {
+	framer.pipeline = pipeline
+}
+
+// GetPipeline returns the pipeline of the framer.
+func (framer *Framer) GetPipeline() Pipeline {
+	return framer.pipeline
+}
+
+// SetTracer sets the tracer the framer reports the frames it writes and reads to, as "frame" encode and decode events
+// carrying the encoded size of the frames and the errors of writing or reading them. Passing nil disables tracing.
+func (framer *Framer) SetTracer(tracer Tracer) {
+	framer.tracer = tracer
+}
+
+// SetRecorder sets the recorder keeping the last frames read and written by the framer, or nil to stop recording.
+func (framer *Framer) SetRecorder(recorder *Recorder) {
+	framer.recorder = recorder
+}
+
+// StartAsync makes the framer write frames asynchronously through an AsyncWriter on the connection,
+// queueing up to maxFrames frames per priority with the overflow policy applied once a queue is full.
+// It returns the async writer, which must be closed to write the frames left and stop it.
+func (framer *Framer) StartAsync(maxFrames int, policy OverflowPolicy) *AsyncWriter {
+	framer.async = NewAsyncWriter(framer.conn, maxFrames, policy)
+	return framer.async
+}
+
+// WriteFrame writes the frame prefixed with its length in a single write to the connection,
+// or queues it with PriorityNormal if the framer writes asynchronously.
+func (framer *Framer) WriteFrame(frame []byte) error {
+	return framer.WriteFramePriority(frame, PriorityNormal)
+}
+
+// WriteFramePriority writes the frame like WriteFrame, queueing it with the priority if the framer writes
+// asynchronously. Frames are written in order if not.
+func (framer *Framer) WriteFramePriority(frame []byte, priority Priority) error {
+	if framer.recorder != nil {
+		framer.recorder.Record(true, frame)
+	}
+	stream := AcquireStream()
+	defer ReleaseStream(stream)
+	stream.SetTracer(framer.tracer)
+	defer stream.TraceEncode("frame")()
+	err := framer.write(stream, frame, priority)
+	stream.SetError(err)
+	return err
+}
+
+// write encodes the frame with the pipeline and writes it prefixed with its length through the stream.
+func (framer *Framer) write(stream *Stream, frame []byte, priority Priority) error {
+	frame, err := framer.pipeline.Encode(frame)
+	if err != nil {
+		return err
+	}
+	if len(frame) > framer.maxSize {
+		return &LengthError{len(frame), framer.maxSize}
+	}
+	stream.PutPrefix(framer.prefix, len(frame))
+	stream.PutBytes(frame)
+	if framer.async != nil {
+		_, err := framer.async.WritePriority(stream.Buffer, priority)
+		return err
+	}
+	_, err = stream.WriteTo(framer.conn)
+	return err
+}
+
+// ReadFrame reads the next frame from the connection, blocking until the full frame is read.
+// It returns io.EOF if the connection is closed between frames, and io.ErrUnexpectedEOF if it is closed within a frame.
+func (framer *Framer) ReadFrame() ([]byte, error) {
+	buffer := framer.buffer
+	for {
+		if frame, ok, err := framer.next(); ok || err != nil {
+			return framer.decode(frame, err)
+		}
+		buffer.Compact()
+		buffer.growFree(readFromChunk)
+		n, err := framer.conn.Read(buffer.Buffer[len(buffer.Buffer):cap(buffer.Buffer)])
+		buffer.Buffer = buffer.Buffer[:len(buffer.Buffer)+n]
+		if err == io.EOF && n == 0 {
+			if buffer.Remaining() > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+}
+
+// decode decodes a frame read with the pipeline. The frame is reported to the tracer of the framer,
+// with the error of reading or decoding it, if any.
+func (framer *Framer) decode(encoded []byte, err error) ([]byte, error) {
+	end := noopTrace
+	var trace *Stream
+	if framer.tracer != nil {
+		trace = &Stream{Buffer: encoded, tracer: framer.tracer}
+		end = trace.TraceDecode("frame")
+	}
+	var frame []byte
+	if err == nil {
+		frame, err = framer.pipeline.Decode(encoded)
+	}
+	if trace != nil {
+		trace.Offset = len(encoded)
+		trace.SetError(err)
+	}
+	end()
+	if err != nil {
+		return nil, err
+	}
+	if framer.recorder != nil {
+		framer.recorder.Record(false, frame)
+	}
+	return frame, nil
+}
+
+// next returns the next frame if it was fully buffered.
+func (framer *Framer) next() ([]byte, bool, error) {
+	buffer := framer.buffer
+	if !framer.prefixBuffered() {
+		return nil, false, nil
+	}
+	stream := &Stream{Buffer: buffer.Buffer[buffer.Offset:]}
+	length := stream.GetPrefix(framer.prefix)
+	if stream.err != nil {
+		return nil, false, stream.err
+	}
+	if length > framer.maxSize {
+		return nil, false, &LengthError{length, framer.maxSize}
+	}
+	if stream.Remaining() < length {
+		return nil, false, nil
+	}
+	buffer.Offset += stream.Offset + length
+	return append([]byte{}, stream.Buffer[stream.Offset:stream.Offset+length]...), true, nil
+}
+
+// prefixBuffered checks if the length prefix of the next frame was fully buffered.
+// Varint prefixes are complete once a byte without continuation bit is buffered,
+// or once they are longer than their maximum size, which is reported when reading them.
+func (framer *Framer) prefixBuffered() bool {
+	rest := framer.buffer.Buffer[framer.buffer.Offset:]
+	if size := framer.prefix.size(); size > 0 {
+		return len(rest) >= size
+	}
+	for i := 0; i < len(rest); i++ {
+		if rest[i] < 0x80 || i >= 4 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"io"
+	"net"
+	"testing"
+	"testing/iotest"
+)
+
+type testConn struct {
+	io.Reader
+	io.Writer
+}
+
+func TestFramer(t *testing.T) {
+	for _, prefix := range []PrefixType{PrefixUnsignedShort, PrefixLittleUnsignedShort, PrefixUnsignedInt, PrefixUnsignedVarInt} {
+		var written bytes.Buffer
+		framer := NewFramer(testConn{nil, &written}, prefix)
+		assert.NilError(t, framer.WriteFrame([]byte("hello")))
+		assert.NilError(t, framer.WriteFrame(nil))
+		assert.NilError(t, framer.WriteFrame(bytes.Repeat([]byte{1}, 300)))
+
+		framer = NewFramer(testConn{iotest.OneByteReader(&written), nil}, prefix)
+		frame, err := framer.ReadFrame()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, frame, []byte("hello"))
+		frame, err = framer.ReadFrame()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, frame, []byte{})
+		frame, err = framer.ReadFrame()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, frame, bytes.Repeat([]byte{1}, 300))
+		_, err = framer.ReadFrame()
+		assert.Equal(t, err, io.EOF)
+	}
+}
+
+func TestFramerAsync(t *testing.T) {
+	var written bytes.Buffer
+	framer := NewFramer(testConn{nil, &written}, PrefixUnsignedVarInt)
+	writer := framer.StartAsync(16, OverflowBlock)
+	assert.NilError(t, framer.WriteFrame([]byte("hello")))
+	assert.NilError(t, framer.WriteFramePriority([]byte("ping"), PriorityControl))
+	assert.NilError(t, writer.Close())
+	assert.Equal(t, framer.WriteFrame(nil), ErrWriterClosed)
+
+	framer = NewFramer(testConn{&written, nil}, PrefixUnsignedVarInt)
+	frames := map[string]bool{}
+	for range 2 {
+		frame, err := framer.ReadFrame()
+		assert.NilError(t, err)
+		frames[string(frame)] = true
+	}
+	assert.DeepEqual(t, frames, map[string]bool{"hello": true, "ping": true})
+}
+
+func TestFramerPipeline(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	pipeline := NewPipeline(ZlibTransformer(-1), CRC32Transformer())
+	writer := NewFramer(client, PrefixUnsignedVarInt)
+	writer.SetPipeline(pipeline)
+	reader := NewFramer(server, PrefixUnsignedVarInt)
+	reader.SetPipeline(pipeline)
+
+	frame := bytes.Repeat([]byte("steve"), 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- writer.WriteFrame(frame)
+	}()
+	decoded, err := reader.ReadFrame()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decoded, frame)
+	assert.NilError(t, <-done)
+
+	// Frames are encoded before being prefixed, so the prefix holds the compressed length.
+	var written bytes.Buffer
+	framer := NewFramer(testConn{nil, &written}, PrefixUnsignedVarInt)
+	framer.SetPipeline(pipeline)
+	assert.NilError(t, framer.WriteFrame(frame))
+	assert.Assert(t, written.Len() < len(frame))
+	written.Bytes()[written.Len()-1] ^= 0xff
+	framer = NewFramer(testConn{&written, nil}, PrefixUnsignedVarInt)
+	framer.SetPipeline(pipeline)
+	_, err = framer.ReadFrame()
+	assert.Assert(t, errors.Is(err, ErrChecksumMismatch))
+}
+
+func TestFramerErrors(t *testing.T) {
+	framer := NewFramer(testConn{bytes.NewReader(b(0, 5, 1, 2)), io.Discard}, PrefixUnsignedShort)
+	_, err := framer.ReadFrame()
+	assert.Equal(t, err, io.ErrUnexpectedEOF)
+
+	framer = NewFramer(testConn{bytes.NewReader(b(0xff, 0xff, 0xff, 0xff, 0x0f)), io.Discard}, PrefixUnsignedVarInt)
+	framer.SetMaxFrameSize(16)
+	_, err = framer.ReadFrame()
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Assert(t, errors.Is(framer.WriteFrame(make([]byte, 17)), ErrLimitExceeded))
+
+	// Frames longer than the prefix holds would be truncated, corrupting the connection.
+	var written bytes.Buffer
+	framer = NewFramer(testConn{nil, &written}, PrefixByte)
+	assert.Equal(t, framer.GetMaxFrameSize(), 255)
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(framer.WriteFrame(make([]byte, 300)), &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{300, 255})
+	framer.SetMaxFrameSize(1 << 10)
+	assert.Assert(t, errors.Is(framer.WriteFrame(make([]byte, 256)), ErrLimitExceeded))
+	assert.NilError(t, framer.WriteFrame(make([]byte, 255)))
+	assert.Equal(t, written.Len(), 256)
+
+	framer = NewFramer(testConn{bytes.NewReader(b(0xff, 0xff, 0xff, 0xff, 0xff, 0x01)), io.Discard}, PrefixUnsignedVarInt)
+	_, err = framer.ReadFrame()
+	assert.Assert(t, errors.Is(err, ErrMalformedVarInt))
+}
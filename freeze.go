@@ -0,0 +1,59 @@
+package binutils
+
+// Freeze makes the buffer of the stream immutable: the Put functions, and other functions modifying the buffer,
+// set ErrFrozen on the stream instead, so that accidental writes to shared buffers are explicit errors.
+// Sub-streams taken from a frozen stream, such as by Sub or Records, are frozen as well.
+// Reading is not affected. Freezing does not protect writes to the Buffer field itself.
+//
+// A frozen buffer can safely be shared across goroutines, as long as every goroutine reads it with its own stream,
+// such as one returned by Reader.
+func (stream *Stream) Freeze() {
+	stream.frozen = true
+}
+
+// Frozen checks if the stream is frozen.
+func (stream *Stream) Frozen() bool {
+	return stream.frozen
+}
+
+// Reader returns a new frozen stream reading the buffer of the stream from its start, with the configuration
+// of the stream. The buffer is shared, so the stream should be frozen before readers are handed to other goroutines.
+func (stream *Stream) Reader() *Stream {
+	reader := stream.child(stream.Buffer)
+	reader.frozen = true
+	return reader
+}
+
+// Fork returns a new writable stream with the buffer, offset and configuration of the stream, freezing the stream.
+// The fork shares the frozen buffer until it is first modified, at which point it copies the buffer,
+// so that a packet can be cheaply modified and forwarded while the original is still read elsewhere.
+func (stream *Stream) Fork() *Stream {
+	stream.Freeze()
+	fork := stream.child(stream.Buffer)
+	fork.Offset = stream.Offset
+	fork.frozen = false
+	fork.shared = true
+	return fork
+}
+
+// Shared checks if the stream is a fork still sharing the buffer of a frozen stream.
+func (stream *Stream) Shared() bool {
+	return stream.shared
+}
+
+// writable checks if the operation op may write n more bytes to the stream. If the stream is frozen, ErrFrozen is set
+// as error of the operation, and the write limit of the stream is checked like fits.
+// A forked stream copies the shared buffer before its first modification.
+func (stream *Stream) writable(op string, n int) bool {
+	if stream.shared {
+		stream.Buffer = append(make([]byte, 0, len(stream.Buffer)), stream.Buffer...)
+		stream.shared = false
+	}
+	if stream.frozen {
+		if stream.err == nil {
+			stream.SetError(&StreamError{op, len(stream.Buffer), ErrFrozen})
+		}
+		return false
+	}
+	return stream.fits(op, len(stream.Buffer)+n)
+}
@@ -0,0 +1,117 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	stream := NewStream()
+	stream.PutInt(7)
+	stream.PutString("steve")
+	stream.Freeze()
+	assert.Assert(t, stream.Frozen())
+
+	stream.PutByte(1)
+	stream.PutLittleLong(2)
+	assert.Equal(t, len(stream.Buffer), 10)
+	var err *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &err))
+	assert.Equal(t, err.Op, "PutByte")
+	assert.Assert(t, errors.Is(err, ErrFrozen))
+
+	stream.ClearError()
+	assert.Equal(t, stream.GetInt(), int32(7))
+	assert.Equal(t, stream.GetString(), "steve")
+	assert.NilError(t, stream.Err())
+
+	_, werr := NewReadWriter(stream).Write(b(1))
+	assert.Assert(t, errors.Is(werr, ErrFrozen))
+}
+
+func TestFreezeReader(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedShort(0x0102)
+	stream.Freeze()
+
+	reader := stream.Reader()
+	assert.Assert(t, reader.Frozen())
+	assert.Equal(t, reader.GetUnsignedShort(), uint16(0x0102))
+	assert.Equal(t, stream.Offset, 0)
+	reader.PutByte(3)
+	assert.Assert(t, errors.Is(reader.Err(), ErrFrozen))
+	assert.NilError(t, stream.Err())
+}
+
+func TestFreezeReset(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedShort(0x0102)
+	stream.Freeze()
+	frozen := stream.Buffer
+
+	stream.Reset()
+	assert.Assert(t, !stream.Frozen())
+	stream.PutUnsignedShort(0x0304)
+	assert.DeepEqual(t, stream.Buffer, b(3, 4))
+	assert.DeepEqual(t, frozen, b(1, 2))
+}
+
+func TestFork(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedShort(0x0102)
+	stream.PutByte(3)
+	stream.Offset = 2
+
+	fork := stream.Fork()
+	assert.Assert(t, stream.Frozen())
+	assert.Assert(t, fork.Shared() && !fork.Frozen())
+	assert.Equal(t, fork.Offset, 2)
+	assert.Equal(t, &fork.Buffer[0], &stream.Buffer[0])
+
+	fork.SetBitsAt(0, 0, 8, 0xff)
+	fork.PutByte(4)
+	assert.NilError(t, fork.Err())
+	assert.Assert(t, !fork.Shared())
+	assert.DeepEqual(t, fork.Buffer, b(0xff, 2, 3, 4))
+	assert.DeepEqual(t, stream.Buffer, b(1, 2, 3))
+
+	other := stream.Fork()
+	other.Reset()
+	other.PutByte(5)
+	assert.DeepEqual(t, stream.Buffer, b(1, 2, 3))
+}
+
+func TestFreezeChildren(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedInt(0)
+	stream.PutUnsignedVarInt(1)
+	stream.PutByte(2)
+	stream.Freeze()
+
+	sub, err := stream.Sub(4)
+	assert.NilError(t, err)
+	assert.Assert(t, sub.Frozen())
+	sub.SetBitsAt(0, 0, 8, 0xff)
+	assert.Assert(t, errors.Is(sub.Err(), ErrFrozen))
+	for record := range stream.Records(PrefixUnsignedVarInt) {
+		record.PutByte(3)
+		assert.Assert(t, errors.Is(record.Err(), ErrFrozen))
+	}
+	reader, err := stream.Reader().Sub(4)
+	assert.NilError(t, err)
+	reader.SetBitsAt(0, 0, 8, 0xff)
+	assert.Assert(t, errors.Is(reader.Err(), ErrFrozen))
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 0, 1, 2))
+
+	// Children of a fork copy the shared buffer instead.
+	fork := stream.Fork()
+	fork.Offset = 0
+	forkSub, err := fork.Sub(4)
+	assert.NilError(t, err)
+	assert.Assert(t, forkSub.Shared() && !forkSub.Frozen())
+	forkSub.SetBitsAt(0, 0, 8, 0xff)
+	assert.NilError(t, forkSub.Err())
+	assert.DeepEqual(t, forkSub.Buffer, b(0xff, 0, 0, 0))
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 0, 1, 2))
+}
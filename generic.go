@@ -0,0 +1,123 @@
+package binutils
+
+import (
+	"encoding/binary"
+	"math"
+	"unsafe"
+)
+
+// Integer is the set of fixed size integer types, including named types such as enums.
+// int and uint are excluded, as their size depends on the platform.
+type Integer interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Float is the set of floating point types.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is the set of types read and written by the generic number functions.
+type Number interface {
+	Integer | Float
+}
+
+// sizeOf returns the encoded size of T.
+func sizeOf[T Number]() int {
+	var v T
+	return int(unsafe.Sizeof(v))
+}
+
+// isFloat checks if T is a floating point type, without reflection: halving one truncates to zero for integers only.
+func isFloat[T Number]() bool {
+	one := T(1)
+	return one/2 != 0
+}
+
+// putNumber encodes v into the first bytes of b in the byte order.
+func putNumber[T Number](b []byte, order binary.ByteOrder, v T) {
+	var bits uint64
+	switch {
+	case isFloat[T]() && sizeOf[T]() == 4:
+		bits = uint64(math.Float32bits(float32(v)))
+	case isFloat[T]():
+		bits = math.Float64bits(float64(v))
+	default:
+		bits = uint64(v)
+	}
+	switch sizeOf[T]() {
+	case 1:
+		b[0] = byte(bits)
+	case 2:
+		order.PutUint16(b, uint16(bits))
+	case 4:
+		order.PutUint32(b, uint32(bits))
+	default:
+		order.PutUint64(b, bits)
+	}
+}
+
+// number decodes a T from the first bytes of b in the byte order.
+func number[T Number](b []byte, order binary.ByteOrder) T {
+	var bits uint64
+	switch sizeOf[T]() {
+	case 1:
+		bits = uint64(b[0])
+	case 2:
+		bits = uint64(order.Uint16(b))
+	case 4:
+		bits = uint64(order.Uint32(b))
+	default:
+		bits = order.Uint64(b)
+	}
+	switch {
+	case isFloat[T]() && sizeOf[T]() == 4:
+		return T(math.Float32frombits(uint32(bits)))
+	case isFloat[T]():
+		return T(math.Float64frombits(bits))
+	}
+	return T(bits)
+}
+
+// byteOrder returns the binary.ByteOrder of the endian type.
+func byteOrder(endian EndianType) binary.ByteOrder {
+	if endian == LittleEndian {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// WriteNumber writes a number of any fixed size type to the buffer in the given byte order.
+func WriteNumber[T Number](buffer *[]byte, v T, endian EndianType) {
+	size := sizeOf[T]()
+	*buffer = append(*buffer, make([]byte, size)...)
+	putNumber((*buffer)[len(*buffer)-size:], byteOrder(endian), v)
+}
+
+// ReadNumber reads a number of any fixed size type at the offset of the buffer in the given byte order.
+// Unlike the other Read functions, it returns ErrUnexpectedEOF instead of panicking if the buffer is too short.
+func ReadNumber[T Number](buffer []byte, offset int, endian EndianType) (T, error) {
+	size := sizeOf[T]()
+	if offset < 0 || offset+size > len(buffer) {
+		return 0, ErrUnexpectedEOF
+	}
+	return number[T](buffer[offset:], byteOrder(endian)), nil
+}
+
+// PutNumber writes a number of any fixed size type to the stream in its byte order.
+func PutNumber[T Number](stream *Stream, v T) {
+	if !stream.writable("PutNumber", sizeOf[T]()) {
+		return
+	}
+	WriteNumber(&stream.Buffer, encodeFloat(stream, v), stream.endian)
+}
+
+// GetNumber reads a number of any fixed size type from the stream in its byte order.
+func GetNumber[T Number](stream *Stream) T {
+	size := sizeOf[T]()
+	if !stream.check("GetNumber", size) {
+		return 0
+	}
+	stream.Offset += size
+	return decodeFloat(stream, "GetNumber", stream.Offset-size, number[T](stream.Buffer[stream.Offset-size:], stream.byteOrder()))
+}
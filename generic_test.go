@@ -0,0 +1,54 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type testGenericID uint8
+
+func TestGenericNumbers(t *testing.T) {
+	buffer := []byte{}
+	WriteNumber(&buffer, int16(-2), BigEndian)
+	WriteNumber(&buffer, testGenericID(7), LittleEndian)
+	WriteNumber(&buffer, uint32(0x01020304), LittleEndian)
+	WriteNumber(&buffer, float32(1.5), BigEndian)
+	WriteNumber(&buffer, -2.25, LittleEndian)
+
+	expected := []byte{}
+	WriteShort(&expected, -2)
+	WriteByte(&expected, 7)
+	WriteLittleUnsignedInt(&expected, 0x01020304)
+	WriteFloat(&expected, 1.5)
+	WriteLittleDouble(&expected, -2.25)
+	assert.DeepEqual(t, buffer, expected)
+
+	short, err := ReadNumber[int16](buffer, 0, BigEndian)
+	assert.NilError(t, err)
+	assert.Equal(t, short, int16(-2))
+	id, err := ReadNumber[testGenericID](buffer, 2, BigEndian)
+	assert.NilError(t, err)
+	assert.Equal(t, id, testGenericID(7))
+	double, err := ReadNumber[float64](buffer, 11, LittleEndian)
+	assert.NilError(t, err)
+	assert.Equal(t, double, -2.25)
+	_, err = ReadNumber[float64](buffer, 12, LittleEndian)
+	assert.Equal(t, err, ErrUnexpectedEOF)
+
+	stream := &Stream{Buffer: buffer}
+	assert.Equal(t, GetNumber[int16](stream), int16(-2))
+	stream.SetEndianness(LittleEndian)
+	assert.Equal(t, GetNumber[uint8](stream), uint8(7))
+	assert.Equal(t, GetNumber[uint32](stream), uint32(0x01020304))
+	stream.SetEndianness(BigEndian)
+	assert.Equal(t, GetNumber[float32](stream), float32(1.5))
+	GetNumber[int64](stream)
+	assert.Equal(t, GetNumber[int64](stream), int64(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream = NewStream()
+	stream.SetEndianness(LittleEndian)
+	PutNumber(stream, int32(-3))
+	assert.DeepEqual(t, stream.Buffer, b(0xfd, 0xff, 0xff, 0xff))
+}
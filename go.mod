@@ -1,9 +1,9 @@
 module github.com/irmine/binutils
 
-go 1.12
+go 1.23
 
 require (
-	github.com/google/go-cmp v0.3.0 // indirect
+	github.com/google/go-cmp v0.3.0
 	github.com/jawm/BinUtils v0.0.0-20180901114828-1d45b6f16318 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
 	gotest.tools v2.2.0+incompatible
@@ -0,0 +1,97 @@
+package binutils
+
+import "errors"
+
+// errBadReference is set on the stream when a back-reference points to an object not read yet.
+var errBadReference = errors.New("reference to unknown object")
+
+// GraphObject is implemented by objects of a graph, which write the objects they refer to through the graph encoder
+// and read them back through the graph decoder. Objects must be pointers, as objects are identified by their address.
+type GraphObject interface {
+	EncodeGraph(encoder *GraphEncoder)
+	DecodeGraph(decoder *GraphDecoder)
+}
+
+// References are written as unsigned varint: 0 for nil, 1 for a new object followed by its encoding,
+// or 2 plus the ID of an object written before. Objects are numbered from 0 in the order they are first written.
+const (
+	refNil = iota
+	refNew
+	refBack
+)
+
+// GraphEncoder writes object graphs to a stream, writing objects that were already written as back-references to
+// their ID instead of duplicating them, so that shared objects and cycles survive a round trip, such as in
+// save states and scene graphs.
+type GraphEncoder struct {
+	stream *Stream
+	ids    map[GraphObject]uint64
+}
+
+// NewGraphEncoder returns a new graph encoder writing to the stream.
+func NewGraphEncoder(stream *Stream) *GraphEncoder {
+	return &GraphEncoder{stream, make(map[GraphObject]uint64)}
+}
+
+// Stream returns the stream the encoder writes to, for writing the fields of objects.
+func (encoder *GraphEncoder) Stream() *Stream {
+	return encoder.stream
+}
+
+// Encode writes a reference to the object, followed by the object itself if it was not written before.
+func (encoder *GraphEncoder) Encode(object GraphObject) {
+	if object == nil {
+		encoder.stream.PutUnsignedVarLong(refNil)
+		return
+	}
+	if id, ok := encoder.ids[object]; ok {
+		encoder.stream.PutUnsignedVarLong(refBack + id)
+		return
+	}
+	// The ID is assigned before the object is written, so that references back to it from within resolve.
+	encoder.ids[object] = uint64(len(encoder.ids))
+	encoder.stream.PutUnsignedVarLong(refNew)
+	object.EncodeGraph(encoder)
+}
+
+// GraphDecoder reads object graphs written by a GraphEncoder from a stream.
+type GraphDecoder struct {
+	stream  *Stream
+	objects []GraphObject
+}
+
+// NewGraphDecoder returns a new graph decoder reading from the stream.
+func NewGraphDecoder(stream *Stream) *GraphDecoder {
+	return &GraphDecoder{stream: stream}
+}
+
+// Stream returns the stream the decoder reads from, for reading the fields of objects.
+func (decoder *GraphDecoder) Stream() *Stream {
+	return decoder.stream
+}
+
+// Decode reads a reference to an object. New objects are created with newObject and read into,
+// while back-references return the object read before. It returns nil for nil references,
+// or if an error is set on the stream. Back-references to objects not read yet set an error on the stream,
+// as do new objects nested deeper than the depth limit of the stream.
+func (decoder *GraphDecoder) Decode(newObject func() GraphObject) GraphObject {
+	offset := decoder.stream.Offset
+	ref := decoder.stream.GetUnsignedVarLong()
+	switch {
+	case decoder.stream.err != nil || ref == refNil:
+		return nil
+	case ref == refNew:
+		if !decoder.stream.Enter() {
+			return nil
+		}
+		defer decoder.stream.Leave()
+		object := newObject()
+		decoder.objects = append(decoder.objects, object)
+		object.DecodeGraph(decoder)
+		return object
+	case ref-refBack >= uint64(len(decoder.objects)):
+		decoder.stream.SetError(&StreamError{"Decode", offset, errBadReference})
+		return nil
+	}
+	return decoder.objects[ref-refBack]
+}
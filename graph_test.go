@@ -0,0 +1,70 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type testNode struct {
+	Name string
+	Next *testNode
+}
+
+func newTestNode() GraphObject {
+	return &testNode{}
+}
+
+func (node *testNode) EncodeGraph(encoder *GraphEncoder) {
+	encoder.Stream().PutString(node.Name)
+	if node.Next == nil {
+		encoder.Encode(nil)
+		return
+	}
+	encoder.Encode(node.Next)
+}
+
+func (node *testNode) DecodeGraph(decoder *GraphDecoder) {
+	node.Name = decoder.Stream().GetString()
+	node.Next, _ = decoder.Decode(newTestNode).(*testNode)
+}
+
+func TestGraph(t *testing.T) {
+	a := &testNode{Name: "a"}
+	b := &testNode{Name: "b", Next: a}
+	a.Next = b
+	tail := &testNode{Name: "c"}
+
+	stream := NewStream()
+	encoder := NewGraphEncoder(stream)
+	encoder.Encode(a)
+	encoder.Encode(b)
+	encoder.Encode(tail)
+	assert.DeepEqual(t, stream.Buffer, []byte{1, 1, 'a', 1, 1, 'b', 2, 3, 1, 1, 'c', 0})
+
+	decoder := NewGraphDecoder(stream)
+	decodedA := decoder.Decode(newTestNode).(*testNode)
+	decodedB := decoder.Decode(newTestNode).(*testNode)
+	decodedTail := decoder.Decode(newTestNode).(*testNode)
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, decodedA.Name, "a")
+	assert.Equal(t, decodedA.Next, decodedB)
+	assert.Equal(t, decodedB.Next, decodedA)
+	assert.Equal(t, decodedTail.Name, "c")
+	assert.Assert(t, decodedTail.Next == nil)
+
+	decoder = NewGraphDecoder(&Stream{Buffer: []byte{5}})
+	assert.Assert(t, decoder.Decode(newTestNode) == nil)
+	assert.Assert(t, errors.Is(decoder.Stream().Err(), errBadReference))
+}
+
+func TestGraphDepth(t *testing.T) {
+	// Every three bytes start another object nested in the previous one.
+	stream := &Stream{Buffer: bytes.Repeat([]byte{1}, 20<<20)}
+	decoder := NewGraphDecoder(stream)
+	decoder.Decode(newTestNode)
+	assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+	assert.Equal(t, stream.Depth(), 0)
+}
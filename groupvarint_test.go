@@ -0,0 +1,35 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestGroupVarInt(t *testing.T) {
+	buffer := []byte{}
+	WriteGroupVarInt(&buffer, [4]uint32{1, 0x0203, 0x040506, 0x0708090a})
+	assert.DeepEqual(t, buffer, b(0x1b, 0x01, 0x03, 0x02, 0x06, 0x05, 0x04, 0x0a, 0x09, 0x08, 0x07))
+
+	stream := NewStream()
+	stream.PutGroupVarInt([4]uint32{0, 1 << 31, 255, 256})
+	assert.Equal(t, len(stream.Buffer), 9)
+	assert.Equal(t, stream.GetGroupVarInt(), [4]uint32{0, 1 << 31, 255, 256})
+
+	stream.Buffer = stream.Buffer[:8]
+	stream.Offset = 0
+	assert.Equal(t, stream.GetGroupVarInt(), [4]uint32{})
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
+
+func TestGroupVarInts(t *testing.T) {
+	values := []uint32{1, 2, 3, 4, 1000, 70000, 1 << 30}
+	stream := NewStream()
+	stream.PutGroupVarInts(values)
+	assert.Equal(t, len(stream.Buffer), 1+5+1+2+3+4+1)
+	assert.DeepEqual(t, stream.GetGroupVarInts(), values)
+
+	stream = &Stream{Buffer: b(0xff, 0xff, 0x03, 0x00, 0, 0, 0, 0)}
+	assert.Assert(t, stream.GetGroupVarInts() == nil)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
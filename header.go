@@ -0,0 +1,52 @@
+package binutils
+
+// HeaderEncoder encodes a packet body before its header, for headers that depend on the body, such as its length.
+// Head room reserved in front of the body allows prepending the header without copying the body.
+type HeaderEncoder struct {
+	body     *Stream
+	headroom int
+}
+
+// NewHeaderEncoder returns a new header encoder reserving headroom bytes for the header.
+// A negative headroom sets ErrNegativeLength on the body, which is returned by the stream of FinishHeader.
+func NewHeaderEncoder(headroom int) *HeaderEncoder {
+	if headroom < 0 {
+		body := NewStream()
+		body.SetError(&StreamError{"NewHeaderEncoder", 0, ErrNegativeLength})
+		return &HeaderEncoder{body, 0}
+	}
+	body := NewStreamWithCapacity(headroom + readFromChunk)
+	body.Buffer = body.Buffer[:headroom]
+	body.Offset = headroom
+	return &HeaderEncoder{body, headroom}
+}
+
+// Body returns the stream the body is written to. Its offset is set to the start of the body.
+func (encoder *HeaderEncoder) Body() *Stream {
+	return encoder.body
+}
+
+// FinishHeader writes the header by calling write with the length of the body, and returns a stream
+// holding the header followed by the body. The body is only copied if the header is larger than the head room.
+// The encoder must not be used after finishing the header.
+func (encoder *HeaderEncoder) FinishHeader(write func(header *Stream, bodyLength int)) *Stream {
+	body := encoder.body
+	header := AcquireStream()
+	defer ReleaseStream(header)
+	header.endian = body.endian
+	write(header, len(body.Buffer)-encoder.headroom)
+
+	stream := &Stream{endian: body.endian, err: body.err}
+	if stream.err == nil {
+		stream.err = header.err
+	}
+	if start := encoder.headroom - len(header.Buffer); start >= 0 {
+		copy(body.Buffer[start:], header.Buffer)
+		stream.Buffer = body.Buffer[start:]
+		return stream
+	}
+	stream.Buffer = make([]byte, 0, len(header.Buffer)+len(body.Buffer)-encoder.headroom)
+	stream.Buffer = append(stream.Buffer, header.Buffer...)
+	stream.Buffer = append(stream.Buffer, body.Buffer[encoder.headroom:]...)
+	return stream
+}
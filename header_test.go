@@ -0,0 +1,39 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestHeaderEncoder(t *testing.T) {
+	encoder := NewHeaderEncoder(4)
+	encoder.Body().PutString("body")
+	body := &encoder.Body().Buffer[4]
+
+	stream := encoder.FinishHeader(func(header *Stream, bodyLength int) {
+		header.PutByte(0xfe)
+		header.PutUnsignedShort(uint16(bodyLength))
+	})
+	assert.DeepEqual(t, stream.Buffer, b(0xfe, 0, 5, 4, 'b', 'o', 'd', 'y'))
+	assert.Equal(t, &stream.Buffer[3], body)
+	assert.NilError(t, stream.Err())
+}
+
+func TestHeaderEncoderWithoutHeadroom(t *testing.T) {
+	encoder := NewHeaderEncoder(0)
+	encoder.Body().PutByte(1)
+	stream := encoder.FinishHeader(func(header *Stream, bodyLength int) {
+		header.PutUnsignedVarInt(uint32(bodyLength))
+	})
+	assert.DeepEqual(t, stream.Buffer, b(1, 1))
+}
+
+func TestHeaderEncoderNegativeHeadroom(t *testing.T) {
+	encoder := NewHeaderEncoder(-1)
+	encoder.Body().PutByte(1)
+	stream := encoder.FinishHeader(func(header *Stream, bodyLength int) {
+		header.PutUnsignedVarInt(uint32(bodyLength))
+	})
+	assert.Assert(t, errors.Is(stream.Err(), ErrNegativeLength))
+}
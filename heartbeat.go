@@ -0,0 +1,98 @@
+package binutils
+
+import (
+	"time"
+)
+
+// Ping is a keep-alive request.
+// The timestamp is the time the ping was sent in Unix nanoseconds, and the payload is echoed by the pong.
+type Ping struct {
+	ID        uint64
+	Timestamp int64
+	Payload   []byte
+}
+
+// NewPing returns a new ping with the given ID and payload, timestamped now.
+func NewPing(id uint64, payload []byte) *Ping {
+	return &Ping{id, time.Now().UnixNano(), payload}
+}
+
+func (ping *Ping) Encode(stream *Stream) {
+	stream.PutUnsignedVarLong(ping.ID)
+	stream.PutLong(ping.Timestamp)
+	stream.PutLengthPrefixedBytes(ping.Payload)
+}
+
+func (ping *Ping) Decode(stream *Stream) {
+	ping.ID = stream.GetUnsignedVarLong()
+	ping.Timestamp = stream.GetLong()
+	ping.Payload = stream.GetLengthPrefixedBytes()
+}
+
+// Pong returns the pong answering the ping, echoing its ID, timestamp and payload.
+func (ping *Ping) Pong() *Pong {
+	return &Pong{ping.ID, ping.Timestamp, ping.Payload}
+}
+
+// Pong is a keep-alive response, echoing the ping it answers.
+type Pong struct {
+	ID        uint64
+	Timestamp int64
+	Payload   []byte
+}
+
+func (pong *Pong) Encode(stream *Stream) {
+	stream.PutUnsignedVarLong(pong.ID)
+	stream.PutLong(pong.Timestamp)
+	stream.PutLengthPrefixedBytes(pong.Payload)
+}
+
+func (pong *Pong) Decode(stream *Stream) {
+	pong.ID = stream.GetUnsignedVarLong()
+	pong.Timestamp = stream.GetLong()
+	pong.Payload = stream.GetLengthPrefixedBytes()
+}
+
+// RTT returns the round trip time of the pong if it was received at the given time.
+func (pong *Pong) RTT(received time.Time) time.Duration {
+	return received.Sub(time.Unix(0, pong.Timestamp))
+}
+
+// RTTEstimator computes a smoothed round trip time and its variation from samples,
+// as described in RFC 6298.
+type RTTEstimator struct {
+	smoothed  time.Duration
+	variation time.Duration
+	samples   int
+}
+
+// Add adds a round trip time sample to the estimator.
+func (estimator *RTTEstimator) Add(rtt time.Duration) {
+	if estimator.samples == 0 {
+		estimator.smoothed = rtt
+		estimator.variation = rtt / 2
+	} else {
+		delta := estimator.smoothed - rtt
+		if delta < 0 {
+			delta = -delta
+		}
+		estimator.variation = (3*estimator.variation + delta) / 4
+		estimator.smoothed = (7*estimator.smoothed + rtt) / 8
+	}
+	estimator.samples++
+}
+
+// Smoothed returns the smoothed round trip time.
+func (estimator *RTTEstimator) Smoothed() time.Duration {
+	return estimator.smoothed
+}
+
+// Variation returns the round trip time variation.
+func (estimator *RTTEstimator) Variation() time.Duration {
+	return estimator.variation
+}
+
+// Timeout returns the time after which a pong should be considered lost.
+func (estimator *RTTEstimator) Timeout() time.Duration {
+	return estimator.smoothed + 4*estimator.variation
+}
@@ -0,0 +1,29 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+	"time"
+)
+
+func TestPingPong(t *testing.T) {
+	ping := NewPing(7, []byte("echo"))
+	stream := NewStream()
+	ping.Pong().Encode(stream)
+
+	pong := &Pong{}
+	pong.Decode(stream)
+	assert.DeepEqual(t, pong, &Pong{7, ping.Timestamp, []byte("echo")})
+	assert.Equal(t, pong.RTT(time.Unix(0, ping.Timestamp+int64(time.Millisecond))), time.Millisecond)
+}
+
+func TestRTTEstimator(t *testing.T) {
+	estimator := &RTTEstimator{}
+	estimator.Add(100 * time.Millisecond)
+	assert.Equal(t, estimator.Smoothed(), 100*time.Millisecond)
+	assert.Equal(t, estimator.Timeout(), 300*time.Millisecond)
+
+	estimator.Add(20 * time.Millisecond)
+	assert.Equal(t, estimator.Smoothed(), 90*time.Millisecond)
+	assert.Equal(t, estimator.Variation(), 57500*time.Microsecond)
+}
@@ -0,0 +1,199 @@
+package binutils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// readFromChunk is the amount of bytes the buffer grows by at least while reading from a reader.
+const readFromChunk = 512
+
+// WriteTo writes the bytes left in the buffer to the writer, advancing the offset past the written bytes.
+// It implements io.WriterTo.
+func (stream *Stream) WriteTo(writer io.Writer) (int64, error) {
+	if stream.Offset >= len(stream.Buffer) {
+		return 0, nil
+	}
+	n, err := writer.Write(stream.Buffer[stream.Offset:])
+	stream.Offset += n
+	if err == nil && stream.Offset < len(stream.Buffer) {
+		err = io.ErrShortWrite
+	}
+	return int64(n), err
+}
+
+// ReadFrom appends the bytes read from the reader to the buffer, until the reader returns io.EOF.
+// It implements io.ReaderFrom. Once the buffer reaches the write limit of the stream, a single byte is read to check
+// that the reader ended at the limit. If it did not, ErrLimitExceeded is returned, with the byte read past the limit
+// discarded and the bytes after it left unread in the reader.
+func (stream *Stream) ReadFrom(reader io.Reader) (int64, error) {
+	if !stream.writable("ReadFrom", 0) {
+		return 0, stream.err
+	}
+	original := reader
+	if stream.writeLimit > 0 {
+		reader = io.LimitReader(reader, int64(max(stream.writeLimit-len(stream.Buffer), 0)))
+	}
+	var total int64
+	for {
+		stream.growFree(readFromChunk)
+		n, err := reader.Read(stream.Buffer[len(stream.Buffer):cap(stream.Buffer)])
+		stream.Buffer = stream.Buffer[:len(stream.Buffer)+n]
+		total += int64(n)
+		if stream.writeLimit > 0 && len(stream.Buffer) >= stream.writeLimit && (err == nil || err == io.EOF && n == 0) {
+			var probe [1]byte
+			if _, err = io.ReadFull(original, probe[:]); err == nil {
+				stream.fits("ReadFrom", len(stream.Buffer)+1)
+				return total, stream.err
+			}
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// growFree grows the buffer if it has less than n bytes of free capacity.
+func (stream *Stream) growFree(n int) {
+	if cap(stream.Buffer)-len(stream.Buffer) < n {
+		grown := make([]byte, len(stream.Buffer), 2*cap(stream.Buffer)+n)
+		copy(grown, stream.Buffer)
+		stream.Buffer = grown
+	}
+}
+
+// ReadWriter wraps a stream to implement io.Reader, io.Writer, io.ByteScanner and io.ByteWriter.
+// Reads consume the bytes left in the buffer of the stream, writes append to it.
+type ReadWriter struct {
+	stream *Stream
+}
+
+// NewReadWriter returns a new ReadWriter for the stream.
+func NewReadWriter(stream *Stream) *ReadWriter {
+	return &ReadWriter{stream}
+}
+
+// Stream returns the wrapped stream.
+func (rw *ReadWriter) Stream() *Stream {
+	return rw.stream
+}
+
+// Read reads up to len(p) bytes from the stream, returning io.EOF if no bytes are left.
+func (rw *ReadWriter) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if rw.stream.Offset >= len(rw.stream.Buffer) {
+		return 0, io.EOF
+	}
+	n := copy(p, rw.stream.Buffer[rw.stream.Offset:])
+	rw.stream.Offset += n
+	return n, nil
+}
+
+// Write appends p to the stream. Writing to a frozen stream returns ErrFrozen.
+func (rw *ReadWriter) Write(p []byte) (int, error) {
+	if !rw.stream.writable("Write", len(p)) {
+		return 0, rw.stream.Err()
+	}
+	rw.stream.PutBytes(p)
+	return len(p), nil
+}
+
+// ReadByte reads a byte from the stream, returning io.EOF if no bytes are left.
+func (rw *ReadWriter) ReadByte() (byte, error) {
+	if rw.stream.Offset >= len(rw.stream.Buffer) {
+		return 0, io.EOF
+	}
+	rw.stream.Offset++
+	return rw.stream.Buffer[rw.stream.Offset-1], nil
+}
+
+// UnreadByte moves the offset of the stream back by one byte.
+func (rw *ReadWriter) UnreadByte() error {
+	if rw.stream.Offset <= 0 {
+		return errors.New("binutils: cannot unread byte at offset 0")
+	}
+	rw.stream.Offset--
+	return nil
+}
+
+// WriteByte appends a byte to the stream. Writing to a frozen stream returns ErrFrozen.
+func (rw *ReadWriter) WriteByte(c byte) error {
+	if !rw.stream.writable("WriteByte", 1) {
+		return rw.stream.Err()
+	}
+	rw.stream.PutByte(c)
+	return nil
+}
+
+// Seek sets the offset of the stream relative to the start of the buffer, the current offset or the end of the buffer,
+// depending on whence, and returns the new offset. It implements io.Seeker.
+// Seeking before the start or past the end of the buffer returns an error and leaves the offset unchanged.
+func (stream *Stream) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		base = int64(stream.Offset)
+	case io.SeekEnd:
+		base = int64(len(stream.Buffer))
+	default:
+		return int64(stream.Offset), fmt.Errorf("binutils: invalid whence %d", whence)
+	}
+	target := base + offset
+	if target < 0 || target > int64(len(stream.Buffer)) {
+		return int64(stream.Offset), fmt.Errorf("binutils: seek to %d outside buffer of %d bytes", target, len(stream.Buffer))
+	}
+	stream.Offset = int(target)
+	return target, nil
+}
+
+// AvailableWriter is a writer exposing the free capacity of its buffer, such as *bytes.Buffer and *bufio.Writer.
+type AvailableWriter interface {
+	io.Writer
+	AvailableBuffer() []byte
+}
+
+// NewStreamForWriter returns a new stream appending into the free capacity of the writer, such as a *bytes.Buffer
+// or *bufio.Writer, so that encoding and writing the stream with WriteTo needs no buffer of its own as long as
+// the bytes fit. The stream must be written before anything else is written to the writer.
+func NewStreamForWriter(writer AvailableWriter) *Stream {
+	return &Stream{Buffer: writer.AvailableBuffer()}
+}
+
+// NewBufferedStream returns a new stream which reads more bytes from the buffered reader when a read needs more bytes
+// than are left, taking the bytes the reader has buffered instead of reading through an intermediate buffer.
+func NewBufferedStream(reader *bufio.Reader) *Stream {
+	return NewRefillStream(func() ([]byte, error) {
+		if reader.Buffered() == 0 {
+			if _, err := reader.Peek(1); err != nil {
+				return nil, err
+			}
+		}
+		return drain(reader), nil
+	})
+}
+
+// ReadBuffered appends the bytes the reader has buffered to the stream without blocking, for draining a reader
+// incrementally as data arrives. It returns the amount of bytes appended.
+func (stream *Stream) ReadBuffered(reader *bufio.Reader) int {
+	if !stream.writable("ReadBuffered", reader.Buffered()) {
+		return 0
+	}
+	b := drain(reader)
+	stream.Buffer = append(stream.Buffer, b...)
+	return len(b)
+}
+
+// drain returns the bytes buffered by the reader and discards them. They are valid until the next read.
+func drain(reader *bufio.Reader) []byte {
+	b, _ := reader.Peek(reader.Buffered())
+	_, _ = reader.Discard(len(b))
+	return b
+}
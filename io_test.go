@@ -0,0 +1,117 @@
+package binutils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"io"
+	"testing"
+)
+
+func TestStreamWriteToReadFrom(t *testing.T) {
+	stream := NewStream()
+	stream.PutString("hello")
+	stream.PutInt(1)
+
+	var buffer bytes.Buffer
+	n, err := stream.WriteTo(&buffer)
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(10))
+	assert.Equal(t, stream.Offset, 10)
+
+	decoded := NewStream()
+	n, err = decoded.ReadFrom(bytes.NewReader(bytes.Repeat(buffer.Bytes(), 100)))
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(1000))
+	assert.Equal(t, decoded.GetString(), "hello")
+	assert.Equal(t, decoded.GetInt(), int32(1))
+}
+
+func TestReadWriter(t *testing.T) {
+	rw := NewReadWriter(NewStream())
+	_, err := io.WriteString(rw, "ab")
+	assert.NilError(t, err)
+	assert.NilError(t, rw.WriteByte('c'))
+
+	c, err := rw.ReadByte()
+	assert.NilError(t, err)
+	assert.Equal(t, c, byte('a'))
+	assert.NilError(t, rw.UnreadByte())
+
+	p := make([]byte, 8)
+	n, err := rw.Read(p)
+	assert.NilError(t, err)
+	assert.Equal(t, string(p[:n]), "abc")
+
+	_, err = rw.Read(p)
+	assert.Equal(t, err, io.EOF)
+	assert.Equal(t, rw.Stream().Offset, 3)
+}
+
+func TestSeek(t *testing.T) {
+	stream := &Stream{Buffer: b(1, 2, 3, 4)}
+	var _ io.Seeker = stream
+
+	offset, err := stream.Seek(3, io.SeekStart)
+	assert.NilError(t, err)
+	assert.Equal(t, offset, int64(3))
+	offset, err = stream.Seek(-2, io.SeekCurrent)
+	assert.NilError(t, err)
+	assert.Equal(t, offset, int64(1))
+	offset, err = stream.Seek(-1, io.SeekEnd)
+	assert.NilError(t, err)
+	assert.Equal(t, stream.GetByte(), byte(4))
+
+	offset, err = stream.Seek(1, io.SeekEnd)
+	assert.ErrorContains(t, err, "outside buffer")
+	assert.Equal(t, offset, int64(4))
+	_, err = stream.Seek(-5, io.SeekCurrent)
+	assert.ErrorContains(t, err, "outside buffer")
+	_, err = stream.Seek(0, 7)
+	assert.ErrorContains(t, err, "whence")
+}
+
+func TestStreamForWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	buffer.Grow(64)
+	buffer.WriteString("a")
+	stream := NewStreamForWriter(&buffer)
+	stream.PutInt(1)
+	assert.Equal(t, &stream.Buffer[:1][0], &buffer.AvailableBuffer()[:1][0])
+	_, err := stream.WriteTo(&buffer)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer.Bytes(), []byte{'a', 0, 0, 0, 1})
+
+	var output bytes.Buffer
+	writer := bufio.NewWriter(&output)
+	stream = NewStreamForWriter(writer)
+	stream.PutString("hello")
+	_, err = stream.WriteTo(writer)
+	assert.NilError(t, err)
+	assert.NilError(t, writer.Flush())
+	assert.DeepEqual(t, output.Bytes(), []byte{5, 'h', 'e', 'l', 'l', 'o'})
+}
+
+func TestBufferedStream(t *testing.T) {
+	source := NewStream()
+	for i := int32(0); i < 100; i++ {
+		source.PutInt(i)
+	}
+	reader := bufio.NewReaderSize(bytes.NewReader(source.Buffer), 16)
+	stream := NewBufferedStream(reader)
+	for i := int32(0); i < 100; i++ {
+		assert.Equal(t, stream.GetInt(), i)
+	}
+	assert.NilError(t, stream.Err())
+	stream.GetByte()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	reader = bufio.NewReaderSize(bytes.NewReader(source.Buffer), 16)
+	stream = NewStream()
+	assert.Equal(t, stream.ReadBuffered(reader), 0)
+	_, err := reader.Peek(1)
+	assert.NilError(t, err)
+	assert.Equal(t, stream.ReadBuffered(reader), 16)
+	assert.Equal(t, stream.GetInt(), int32(0))
+}
@@ -0,0 +1,67 @@
+package binutils
+
+import (
+	"iter"
+)
+
+// Elements returns an iterator lazily decoding an array prefixed with its element count.
+// The count is read when iteration starts, and each element is read with the read function
+// as it is yielded together with its index.
+// Iteration stops early once an error is set on the stream.
+func Elements[T any](stream *Stream, prefix PrefixType, read func(*Stream) T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		offset := stream.Offset
+		count := stream.GetPrefix(prefix)
+		if !stream.checkCount("Elements", offset, count) {
+			return
+		}
+		for i := 0; i < count && stream.err == nil; i++ {
+			element := read(stream)
+			if stream.err != nil || !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
+// TLVFormat describes the encoding of type-length-value entries.
+type TLVFormat struct {
+	Type   PrefixType
+	Length PrefixType
+}
+
+// PutTLV writes a type-length-value entry in the given format.
+func (stream *Stream) PutTLV(format TLVFormat, typ uint32, value []byte) {
+	if !stream.writable("PutTLV", prefixSize(format.Type, int(typ))+prefixSize(format.Length, len(value))+len(value)) {
+		return
+	}
+	stream.PutPrefix(format.Type, int(typ))
+	stream.PutPrefix(format.Length, len(value))
+	stream.PutBytes(value)
+}
+
+// TLVs returns an iterator over the type-length-value entries left in the buffer, consuming them.
+// The value of each entry is yielded as a sub-stream bounded to the value.
+// Iteration stops at the end of the buffer, or once an error is set on the stream.
+func (stream *Stream) TLVs(format TLVFormat) iter.Seq2[uint32, *Stream] {
+	return func(yield func(uint32, *Stream) bool) {
+		for stream.err == nil && (stream.Offset < len(stream.Buffer) || stream.fill("TLVs", 1)) {
+			offset := stream.Offset
+			typ := uint32(stream.GetPrefix(format.Type))
+			length := stream.GetPrefix(format.Length)
+			if stream.err != nil {
+				return
+			}
+			if !stream.available("TLVs", length, 1) {
+				stream.Offset = offset
+				stream.SetError(&StreamError{"TLVs", offset, ErrUnexpectedEOF})
+				return
+			}
+			value := stream.child(stream.Buffer[stream.Offset : stream.Offset+length])
+			stream.Offset += length
+			if !yield(typ, value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestElements(t *testing.T) {
+	stream := NewStream()
+	stream.PutPrefix(PrefixUnsignedShort, 3)
+	for _, v := range []int32{-1, 300, 5} {
+		stream.PutVarInt(v)
+	}
+
+	var values []int32
+	for i, v := range Elements(stream, PrefixUnsignedShort, (*Stream).GetVarInt) {
+		assert.Equal(t, i, len(values))
+		values = append(values, v)
+	}
+	assert.DeepEqual(t, values, []int32{-1, 300, 5})
+}
+
+func TestElementsStopsOnError(t *testing.T) {
+	stream := NewStream()
+	stream.PutPrefix(PrefixUnsignedVarInt, 1000000)
+	stream.PutInt(1)
+
+	count := 0
+	for range Elements(stream, PrefixUnsignedVarInt, (*Stream).GetInt) {
+		count++
+	}
+	assert.Equal(t, count, 1)
+	assert.ErrorContains(t, stream.Error(), "GetInt")
+}
+
+func TestTLVs(t *testing.T) {
+	format := TLVFormat{Type: PrefixByte, Length: PrefixUnsignedShort}
+	stream := NewStream()
+	stream.PutTLV(format, 1, []byte("ab"))
+	stream.PutTLV(format, 7, nil)
+	stream.PutTLV(format, 2, []byte{0, 0, 0, 9})
+
+	var types []uint32
+	for typ, value := range stream.TLVs(format) {
+		types = append(types, typ)
+		if typ == 2 {
+			assert.Equal(t, value.GetInt(), int32(9))
+		}
+	}
+	assert.DeepEqual(t, types, []uint32{1, 7, 2})
+	assert.NilError(t, stream.Error())
+}
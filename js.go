@@ -0,0 +1,43 @@
+//go:build js && wasm
+
+package binutils
+
+import "syscall/js"
+
+// NewStreamFromUint8Array returns a new stream reading the bytes of a JavaScript Uint8Array or Uint8ClampedArray.
+// The bytes are copied once, straight into the buffer of the stream.
+func NewStreamFromUint8Array(array js.Value) *Stream {
+	buffer := make([]byte, array.Get("length").Int())
+	js.CopyBytesToGo(buffer, array)
+	return &Stream{Buffer: buffer}
+}
+
+// PutUint8Array appends the bytes of a JavaScript Uint8Array or Uint8ClampedArray to the stream,
+// copying them straight into the buffer.
+func (stream *Stream) PutUint8Array(array js.Value) {
+	n := array.Get("length").Int()
+	if !stream.writable("PutUint8Array", n) {
+		return
+	}
+	stream.growFree(n)
+	stream.Buffer = stream.Buffer[:len(stream.Buffer)+n]
+	js.CopyBytesToGo(stream.Buffer[len(stream.Buffer)-n:], array)
+}
+
+// Uint8Array returns a new JavaScript Uint8Array holding the bytes left in the stream.
+func (stream *Stream) Uint8Array() js.Value {
+	array := js.Global().Get("Uint8Array").New(stream.Remaining())
+	if stream.Remaining() > 0 {
+		js.CopyBytesToJS(array, stream.Buffer[stream.Offset:])
+	}
+	return array
+}
+
+// CopyToUint8Array copies the bytes left in the stream into a JavaScript Uint8Array, such as a view on a
+// preallocated ArrayBuffer, without advancing the offset, and returns the number of bytes copied.
+func (stream *Stream) CopyToUint8Array(array js.Value) int {
+	if stream.Remaining() == 0 {
+		return 0
+	}
+	return js.CopyBytesToJS(array, stream.Buffer[stream.Offset:])
+}
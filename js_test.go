@@ -0,0 +1,30 @@
+//go:build js && wasm
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"syscall/js"
+	"testing"
+)
+
+func TestUint8Array(t *testing.T) {
+	array := js.Global().Get("Uint8Array").New(3)
+	for i := 0; i < 3; i++ {
+		array.SetIndex(i, i+1)
+	}
+	stream := NewStreamFromUint8Array(array)
+	assert.DeepEqual(t, stream.Buffer, b(1, 2, 3))
+	stream.PutUint8Array(array)
+	assert.DeepEqual(t, stream.Buffer, b(1, 2, 3, 1, 2, 3))
+
+	stream.GetByte()
+	out := stream.Uint8Array()
+	assert.Equal(t, out.Get("length").Int(), 5)
+	assert.Equal(t, out.Index(0).Int(), 2)
+
+	view := js.Global().Get("Uint8Array").New(2)
+	assert.Equal(t, stream.CopyToUint8Array(view), 2)
+	assert.Equal(t, view.Index(1).Int(), 3)
+	assert.Equal(t, stream.Offset, 1)
+}
@@ -0,0 +1,148 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// errTrailingBytes is returned when a record of a binary log holds more bytes than its schema reads.
+var errTrailingBytes = errors.New("trailing bytes in record")
+
+// EncodeJSONLines reads JSON objects, such as one per line, from the reader, and writes each encoded with the schema
+// and prefixed with its length as unsigned varint to the writer, converting text logs to a compact binary log.
+// Bytes fields are read from base64 strings, like encoding/json writes them. It returns the amount of records written.
+func (schema *Schema) EncodeJSONLines(writer io.Writer, reader io.Reader) (int, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	buffered := bufio.NewWriter(writer)
+	stream := NewStream()
+	record := NewStream()
+	n := 0
+	for {
+		var values map[string]interface{}
+		if err := decoder.Decode(&values); err == io.EOF {
+			break
+		} else if err != nil {
+			return n, fmt.Errorf("binutils: record %d: %w", n, err)
+		}
+		values, err := schema.jsonValues(values)
+		if err == nil {
+			record.Reset()
+			err = schema.Put(record, values)
+		}
+		if err != nil {
+			return n, fmt.Errorf("binutils: record %d: %w", n, err)
+		}
+		stream.Reset()
+		stream.PutUnsignedVarInt(uint32(len(record.Buffer)))
+		stream.PutBytes(record.Buffer)
+		if _, err := buffered.Write(stream.Buffer); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, buffered.Flush()
+}
+
+// DecodeJSONLines reads a binary log written by EncodeJSONLines from the reader, and writes each record decoded
+// with the schema to the writer as JSON object on its own line. It returns the amount of records written.
+func (schema *Schema) DecodeJSONLines(writer io.Writer, reader io.Reader) (int, error) {
+	buffered := bufio.NewReader(reader)
+	output := bufio.NewWriter(writer)
+	encoder := json.NewEncoder(output)
+	n := 0
+	for {
+		length, _, err := ReadUnsignedVarIntFrom(buffered)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, fmt.Errorf("binutils: record %d: %w", n, err)
+		}
+		record, err := io.ReadAll(io.LimitReader(buffered, int64(length)))
+		if err == nil && len(record) < int(length) {
+			err = ErrUnexpectedEOF
+		}
+		if err != nil {
+			return n, fmt.Errorf("binutils: record %d: %w", n, err)
+		}
+		stream := &Stream{Buffer: record}
+		values, err := schema.Get(stream)
+		if err == nil && stream.Remaining() > 0 {
+			err = errTrailingBytes
+		}
+		if err == nil {
+			err = encoder.Encode(values)
+		}
+		if err != nil {
+			return n, fmt.Errorf("binutils: record %d: %w", n, err)
+		}
+		n++
+	}
+	return n, output.Flush()
+}
+
+// jsonValues converts values decoded from JSON with numbers kept as json.Number to the values Put accepts.
+func (schema *Schema) jsonValues(values map[string]interface{}) (map[string]interface{}, error) {
+	converted := make(map[string]interface{}, len(values))
+	for _, field := range schema.fields {
+		v, ok := values[field.name]
+		if !ok {
+			continue
+		}
+		v, err := field.jsonValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.name, err)
+		}
+		converted[field.name] = v
+	}
+	return converted, nil
+}
+
+// jsonValue converts the value of the field decoded from JSON: numbers are parsed by the field type,
+// and bytes decoded from base64 strings.
+func (field schemaField) jsonValue(v interface{}) (interface{}, error) {
+	if elements, ok := v.([]interface{}); ok && field.array {
+		converted := make([]interface{}, len(elements))
+		for i, element := range elements {
+			element, err := field.jsonElement(element)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			converted[i] = element
+		}
+		return converted, nil
+	}
+	return field.jsonElement(v)
+}
+
+// jsonElement converts a single value of the field type decoded from JSON.
+func (field schemaField) jsonElement(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if field.t == TypeStruct {
+			return field.schema.jsonValues(v)
+		}
+	case string:
+		if field.t == TypeBytes {
+			return base64.StdEncoding.DecodeString(v)
+		}
+	case json.Number:
+		switch {
+		case field.t == TypeFloat32 || field.t == TypeFloat64:
+			return v.Float64()
+		case field.t.signed():
+			return strconv.ParseInt(string(v), 10, 64)
+		default:
+			return strconv.ParseUint(string(v), 10, 64)
+		}
+	}
+	return v, nil
+}
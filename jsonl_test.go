@@ -0,0 +1,45 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestJSONLines(t *testing.T) {
+	point := NewSchema().Field("x", TypeInt16).Field("y", TypeInt16)
+	schema := NewSchema().
+		Field("id", TypeUint64).
+		Field("level", TypeVarInt).
+		Field("ratio", TypeFloat64).
+		Field("msg", TypeString).
+		Field("raw", TypeBytes).
+		Array("tags", TypeUint8).
+		Struct("at", point)
+	lines := `{"at":{"x":-1,"y":2},"id":18446744073709551615,"level":-3,"msg":"started","ratio":0.5,"raw":"3q0=","tags":[1,2]}
+{"at":{"x":0,"y":0},"id":1,"level":0,"msg":"","ratio":0,"raw":"","tags":[]}
+`
+	var binary bytes.Buffer
+	n, err := schema.EncodeJSONLines(&binary, strings.NewReader(lines))
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+	assert.Equal(t, binary.Bytes()[0], byte(35))
+
+	var text strings.Builder
+	n, err = schema.DecodeJSONLines(&text, bytes.NewReader(binary.Bytes()))
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+	assert.Equal(t, text.String(), lines)
+
+	_, err = schema.DecodeJSONLines(&text, bytes.NewReader(binary.Bytes()[:20]))
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	_, err = point.DecodeJSONLines(&text, bytes.NewReader(binary.Bytes()))
+	assert.ErrorContains(t, err, "record 0: trailing bytes in record")
+	_, err = schema.EncodeJSONLines(&binary, strings.NewReader(`{"id":-1}`))
+	assert.ErrorContains(t, err, "record 0: field id")
+}
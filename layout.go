@@ -0,0 +1,170 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MarshalOptions are options of the struct marshaler. The zero value encodes as described for Marshal.
+type MarshalOptions struct {
+	// CLayout pads fields to their natural alignment like C compilers do, so that structs dumped by C programs
+	// can be read without manual padding fields. Structs are padded to a multiple of their largest alignment.
+	// Only fixed width wire types, arrays and structs can be used in C layout.
+	CLayout bool
+	// Pack limits the alignment of fields in C layout, like #pragma pack(n). 0 uses natural alignment.
+	Pack int
+	// ByteOrder is the byte order of fields without le or be option in their tag.
+	ByteOrder EndianType
+	// Registry encodes and decodes interface fields, which hold packets registered in it.
+	Registry *Registry
+	// Profiler records the encoded size of the fields of every struct encoded or decoded, if set.
+	Profiler *Profiler
+}
+
+// Marshal returns the encoding of v as described for Marshal, with the options applied.
+func (options MarshalOptions) Marshal(v interface{}) ([]byte, error) {
+	stream := NewStream()
+	if err := options.PutStruct(stream, v); err != nil {
+		return nil, err
+	}
+	return stream.Buffer, nil
+}
+
+// Unmarshal decodes the buffer into v as described for Unmarshal, with the options applied.
+func (options MarshalOptions) Unmarshal(buffer []byte, v interface{}) error {
+	return options.GetStruct(&Stream{Buffer: buffer}, v)
+}
+
+// PutStruct writes v to the stream as described for Stream.PutStruct, with the options applied.
+func (options MarshalOptions) PutStruct(stream *Stream, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("binutils: cannot marshal %T, expected a struct", v)
+	}
+	if !stream.writable("PutStruct", 0) {
+		return stream.err
+	}
+	start := len(stream.Buffer)
+	m := &marshaler{stream: stream, options: options, base: start, track: options.Profiler != nil}
+	if err := m.encode(value, fieldTag{}, ""); err != nil {
+		return err
+	}
+	if m.dependents && stream.err == nil {
+		buffer, _, err := updateDependents(stream.Buffer[start:], reflect.New(value.Type()).Elem(), options)
+		if err != nil {
+			return err
+		}
+		stream.Buffer = append(stream.Buffer[:start], buffer...)
+	}
+	if options.Profiler != nil && stream.err == nil {
+		options.Profiler.record(m.fields, len(stream.Buffer)-start)
+	}
+	return stream.err
+}
+
+// GetStruct reads into v from the stream as described for Stream.GetStruct, with the options applied.
+func (options MarshalOptions) GetStruct(stream *Stream, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("binutils: cannot unmarshal into %T, expected a pointer to a struct", v)
+	}
+	m := &marshaler{stream: stream, options: options, base: stream.Offset, track: options.Profiler != nil}
+	if err := m.decode(value.Elem(), fieldTag{}, ""); err != nil {
+		return err
+	}
+	if options.Profiler != nil && stream.err == nil {
+		options.Profiler.record(m.fields, stream.Offset-m.base)
+	}
+	return stream.err
+}
+
+// endian returns the byte order of values with the given tag.
+func (m *marshaler) endian(tag fieldTag) EndianType {
+	if tag.endianSet {
+		return tag.endian
+	}
+	return m.options.ByteOrder
+}
+
+// size returns the encoded size of fixed width wire types, or 0 for variable width wire types.
+func (wire wireType) size() int {
+	switch wire {
+	case wireBool, wireInt8, wireUint8:
+		return 1
+	case wireInt16, wireUint16:
+		return 2
+	case wireTriad, wireInt24:
+		return 3
+	case wireInt32, wireUint32, wireFloat32:
+		return 4
+	case wireInt64, wireUint64, wireFloat64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// alignment returns the alignment of values of type t with the given tag in C layout.
+func (options MarshalOptions) alignment(t reflect.Type, tag fieldTag) (int, error) {
+	align := 1
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, err := structFields(t)
+		if err != nil {
+			return 0, err
+		}
+		for _, field := range fields {
+			fieldAlign, err := options.alignment(t.FieldByIndex(field.index).Type, field.tag)
+			if err != nil {
+				return 0, err
+			}
+			align = max(align, fieldAlign)
+		}
+	case reflect.Array:
+		return options.alignment(t.Elem(), tag)
+	default:
+		wire, err := resolveWire(t, tag.wire)
+		if err != nil {
+			return 0, err
+		}
+		switch size := wire.size(); size {
+		case 0:
+			return 0, fmt.Errorf("binutils: %s has no fixed size for C layout", t)
+		case 3:
+			// 24 bit integers do not exist in C, and are treated as byte arrays.
+		default:
+			align = size
+		}
+	}
+	if options.Pack > 0 && align > options.Pack {
+		align = options.Pack
+	}
+	return align, nil
+}
+
+// alignField pads the stream to the alignment of values of type t with the given tag if C layout is used.
+// Encoding appends zero bytes, decoding skips the padding.
+func (m *marshaler) alignField(t reflect.Type, tag fieldTag, encoding bool) error {
+	if !m.options.CLayout {
+		return nil
+	}
+	align, err := m.options.alignment(t, tag)
+	if err != nil {
+		return err
+	}
+	if encoding {
+		padding := (align - (len(m.stream.Buffer)-m.base)%align) % align
+		m.stream.PutBytes(make([]byte, padding))
+		return nil
+	}
+	padding := (align - (m.stream.Offset-m.base)%align) % align
+	if m.stream.check("GetStruct", padding) {
+		m.stream.Offset += padding
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+type testCStruct struct {
+	A byte
+	B int32
+	C int16
+}
+
+type testCNested struct {
+	A     byte
+	Inner struct {
+		A byte
+		B float64
+	}
+	Values [2]testCStruct
+}
+
+func TestCLayout(t *testing.T) {
+	value := testCStruct{1, 2, 3}
+	for pack, expected := range map[int][]byte{
+		0: b(1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0),
+		1: b(1, 2, 0, 0, 0, 3, 0),
+		2: b(1, 0, 2, 0, 0, 0, 3, 0),
+	} {
+		options := MarshalOptions{CLayout: true, Pack: pack, ByteOrder: LittleEndian}
+		buffer, err := options.Marshal(value)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, buffer, expected)
+
+		var decoded testCStruct
+		assert.NilError(t, options.Unmarshal(buffer, &decoded))
+		assert.Equal(t, decoded, value)
+	}
+}
+
+func TestCLayoutNested(t *testing.T) {
+	var value testCNested
+	value.A, value.Inner.A, value.Inner.B = 1, 2, 1.5
+	value.Values[1].C = 4
+	options := MarshalOptions{CLayout: true, ByteOrder: LittleEndian}
+	buffer, err := options.Marshal(value)
+	assert.NilError(t, err)
+	assert.Equal(t, len(buffer), 48)
+	assert.Equal(t, buffer[8], byte(2))
+	assert.Equal(t, buffer[44], byte(4))
+
+	var decoded testCNested
+	assert.NilError(t, options.Unmarshal(buffer, &decoded))
+	assert.Equal(t, decoded, value)
+
+	_, err = options.Marshal(testHeader{})
+	assert.ErrorContains(t, err, "int has no fixed size for C layout")
+}
+
+func TestMarshalIgnoresStreamEndianness(t *testing.T) {
+	stream := NewStream()
+	stream.SetEndianness(LittleEndian)
+	assert.NilError(t, stream.PutStruct(testHeader{ID: 1}))
+	assert.DeepEqual(t, stream.Buffer, b(0x01, 0x00, 0x00, 0x00))
+	assert.Equal(t, stream.GetEndianness(), LittleEndian)
+}
@@ -0,0 +1,156 @@
+package binutils
+
+import (
+	"math"
+)
+
+// maxVarLongBytes is the maximum encoded size of 64 bit variable length integers.
+const maxVarLongBytes = 10
+
+// WriteUnsignedLEB128 writes an unsigned LEB128 integer, as used by DWARF and WebAssembly, to the buffer.
+// Unsigned LEB128 is the encoding of unsigned varints.
+func WriteUnsignedLEB128(buffer *[]byte, v uint64) {
+	WriteUnsignedVarLong(buffer, v)
+}
+
+// ReadUnsignedLEB128 reads an unsigned LEB128 integer from the buffer.
+func ReadUnsignedLEB128(buffer *[]byte, offset *int) uint64 {
+	return ReadUnsignedVarLong(buffer, offset)
+}
+
+// WriteLEB128 writes a signed LEB128 integer, which is sign extended rather than zigzag encoded, to the buffer.
+func WriteLEB128(buffer *[]byte, v int64) {
+	var b [maxVarLongBytes]byte
+	n := 0
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 && c&0x40 == 0 || v == -1 && c&0x40 != 0 {
+			b[n] = c
+			*buffer = append(*buffer, b[:n+1]...)
+			return
+		}
+		b[n] = c | 0x80
+		n++
+	}
+}
+
+// leb128Size returns the encoded size of a signed LEB128 integer.
+func leb128Size(v int64) int {
+	n := 1
+	for ; v < -0x40 || v >= 0x40; v >>= 7 {
+		n++
+	}
+	return n
+}
+
+// ReadLEB128 reads a signed LEB128 integer from the buffer.
+// It panics with ErrMalformedVarInt if the integer is longer than 10 bytes.
+func ReadLEB128(buffer *[]byte, offset *int) int64 {
+	if *offset < 0 || *offset > len(*buffer) {
+		panic(ErrUnexpectedEOF)
+	}
+	v, n, err := decodeLEB128((*buffer)[*offset:])
+	if err != nil {
+		panic(err)
+	}
+	*offset += n
+	return int64(v)
+}
+
+// decodeLEB128 decodes a signed LEB128 integer, returning it with the number of bytes read.
+func decodeLEB128(buffer []byte) (uint64, int, error) {
+	var result int64
+	for i := 0; i < maxVarLongBytes; i++ {
+		if i >= len(buffer) {
+			return 0, 0, ErrUnexpectedEOF
+		}
+		shift := 7 * uint(i)
+		result |= int64(buffer[i]&0x7f) << shift
+		if buffer[i]&0x80 == 0 {
+			if shift+7 < 64 && buffer[i]&0x40 != 0 {
+				result |= -1 << (shift + 7)
+			}
+			return uint64(result), i + 1, nil
+		}
+	}
+	return 0, 0, ErrMalformedVarInt
+}
+
+// WriteVLQ writes a variable length quantity, as used by MIDI files, to the buffer.
+// VLQs are encoded in groups of 7 bits with the most significant group first.
+func WriteVLQ(buffer *[]byte, v uint64) {
+	var b [maxVarLongBytes]byte
+	i := len(b) - 1
+	b[i] = byte(v & 0x7f)
+	for v >>= 7; v != 0; v >>= 7 {
+		i--
+		b[i] = byte(v&0x7f) | 0x80
+	}
+	*buffer = append(*buffer, b[i:]...)
+}
+
+// ReadVLQ reads a variable length quantity from the buffer.
+// It panics with ErrMalformedVarInt if the quantity does not fit in 64 bits.
+func ReadVLQ(buffer *[]byte, offset *int) uint64 {
+	if *offset < 0 || *offset > len(*buffer) {
+		panic(ErrUnexpectedEOF)
+	}
+	v, n, err := decodeVLQ((*buffer)[*offset:])
+	if err != nil {
+		panic(err)
+	}
+	*offset += n
+	return v
+}
+
+// decodeVLQ decodes a variable length quantity, returning it with the number of bytes read.
+func decodeVLQ(buffer []byte) (uint64, int, error) {
+	var result uint64
+	for i := 0; i < maxVarLongBytes; i++ {
+		if i >= len(buffer) {
+			return 0, 0, ErrUnexpectedEOF
+		}
+		if result > math.MaxUint64>>7 {
+			return 0, 0, ErrMalformedVarInt
+		}
+		result = result<<7 | uint64(buffer[i]&0x7f)
+		if buffer[i]&0x80 == 0 {
+			return result, i + 1, nil
+		}
+	}
+	return 0, 0, ErrMalformedVarInt
+}
+
+func (stream *Stream) PutUnsignedLEB128(v uint64) {
+	if !stream.writable("PutUnsignedLEB128", uvarintSize(v)) {
+		return
+	}
+	WriteUnsignedLEB128(&stream.Buffer, v)
+}
+
+func (stream *Stream) GetUnsignedLEB128() uint64 {
+	return stream.getUnsignedVarLong("GetUnsignedLEB128", maxVarLongBytes)
+}
+
+func (stream *Stream) PutLEB128(v int64) {
+	if !stream.writable("PutLEB128", leb128Size(v)) {
+		return
+	}
+	WriteLEB128(&stream.Buffer, v)
+}
+
+func (stream *Stream) GetLEB128() int64 {
+	return int64(stream.getEncoded("GetLEB128", decodeLEB128))
+}
+
+func (stream *Stream) PutVLQ(v uint64) {
+	if !stream.writable("PutVLQ", uvarintSize(v)) {
+		return
+	}
+	WriteVLQ(&stream.Buffer, v)
+}
+
+func (stream *Stream) GetVLQ() uint64 {
+	return stream.getEncoded("GetVLQ", decodeVLQ)
+}
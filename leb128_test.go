@@ -0,0 +1,62 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestLEB128(t *testing.T) {
+	for v, expected := range map[int64][]byte{
+		0:       b(0x00),
+		2:       b(0x02),
+		-2:      b(0x7e),
+		127:     b(0xff, 0x00),
+		-128:    b(0x80, 0x7f),
+		-123456: b(0xc0, 0xbb, 0x78),
+	} {
+		buffer := []byte{}
+		WriteLEB128(&buffer, v)
+		assert.DeepEqual(t, buffer, expected)
+		offset := 0
+		assert.Equal(t, ReadLEB128(&buffer, &offset), v)
+	}
+
+	stream := NewStream()
+	for _, v := range []int64{math.MinInt64, math.MaxInt64, -1, 64, -65} {
+		stream.PutLEB128(v)
+		assert.Equal(t, stream.GetLEB128(), v)
+	}
+	stream.PutUnsignedLEB128(624485)
+	assert.DeepEqual(t, stream.Buffer[len(stream.Buffer)-3:], b(0xe5, 0x8e, 0x26))
+	assert.Equal(t, stream.GetUnsignedLEB128(), uint64(624485))
+	assert.NilError(t, stream.Err())
+}
+
+func TestVLQ(t *testing.T) {
+	for v, expected := range map[uint64][]byte{
+		0:          b(0x00),
+		0x7f:       b(0x7f),
+		0x80:       b(0x81, 0x00),
+		0x2000:     b(0xc0, 0x00),
+		0x0fffffff: b(0xff, 0xff, 0xff, 0x7f),
+	} {
+		buffer := []byte{}
+		WriteVLQ(&buffer, v)
+		assert.DeepEqual(t, buffer, expected)
+		offset := 0
+		assert.Equal(t, ReadVLQ(&buffer, &offset), v)
+	}
+
+	stream := NewStream()
+	stream.PutVLQ(math.MaxUint64)
+	assert.Equal(t, stream.GetVLQ(), uint64(math.MaxUint64))
+
+	stream = &Stream{Buffer: b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f)}
+	stream.GetVLQ()
+	assert.Assert(t, errors.Is(stream.Err(), ErrMalformedVarInt))
+	stream = &Stream{Buffer: b(0x81)}
+	stream.GetLEB128()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
@@ -0,0 +1,42 @@
+package binutils
+
+import (
+	"errors"
+)
+
+// NewStreamWithLimit returns a new stream whose buffer cannot grow past maxSize bytes, as described for SetWriteLimit.
+func NewStreamWithLimit(maxSize int) *Stream {
+	return &Stream{Buffer: []byte{}, writeLimit: maxSize}
+}
+
+// SetWriteLimit sets the maximum size of the buffer of the stream, such as the MTU of packets assembled in it.
+// Functions that would grow the buffer past the limit write nothing and set ErrLimitExceeded on the stream instead.
+// Once the limit has been exceeded, later writes are discarded as well, so that a packet is never assembled with
+// fields missing. A limit of 0 or less disables the limit, which is the default.
+func (stream *Stream) SetWriteLimit(limit int) {
+	stream.writeLimit = limit
+}
+
+// GetWriteLimit returns the maximum size of the buffer of the stream, or 0 if there is no limit.
+func (stream *Stream) GetWriteLimit() int {
+	if stream.writeLimit <= 0 {
+		return 0
+	}
+	return stream.writeLimit
+}
+
+// fits checks if the buffer of the stream may grow to size bytes for the operation op,
+// setting ErrLimitExceeded on the stream if it exceeds the write limit.
+func (stream *Stream) fits(op string, size int) bool {
+	if stream.writeLimit <= 0 {
+		return true
+	}
+	if stream.err != nil && errors.Is(stream.err, ErrLimitExceeded) {
+		return false
+	}
+	if size > stream.writeLimit {
+		stream.SetError(&StreamError{op, len(stream.Buffer), ErrLimitExceeded})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriteLimit(t *testing.T) {
+	stream := NewStreamWithLimit(8)
+	assert.Equal(t, stream.GetWriteLimit(), 8)
+	stream.PutInt(1)
+	stream.PutString("abc")
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, len(stream.Buffer), 8)
+
+	stream.PutByte(1)
+	var err *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &err))
+	assert.Equal(t, err.Op, "PutByte")
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, len(stream.Buffer), 8)
+
+	stream.Reset()
+	stream.PutIntSlice([]int32{1, 2})
+	stream.PutLong(1)
+	stream.PutByte(1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+	assert.Equal(t, len(stream.Buffer), 0)
+}
+
+func TestWriteLimitReadFrom(t *testing.T) {
+	stream := NewStreamWithLimit(600)
+	n, err := stream.ReadFrom(bytes.NewReader(make([]byte, 600)))
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(600))
+
+	stream = NewStreamWithLimit(600)
+	n, err = stream.ReadFrom(iotest.DataErrReader(bytes.NewReader(make([]byte, 600))))
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(600))
+	n, err = stream.ReadFrom(bytes.NewReader(nil))
+	assert.NilError(t, err)
+	assert.Equal(t, n, int64(0))
+	_, err = stream.ReadFrom(bytes.NewReader(b(1)))
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+
+	stream = NewStreamWithLimit(600)
+	reader := bytes.NewReader(make([]byte, 601))
+	n, err = stream.ReadFrom(reader)
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, n, int64(600))
+	assert.Equal(t, len(stream.Buffer), 600)
+	// The byte past the limit is discarded, the bytes after it are left unread.
+	assert.Equal(t, reader.Len(), 0)
+
+	stream = NewStreamWithLimit(600)
+	reader = bytes.NewReader(make([]byte, 700))
+	_, err = stream.ReadFrom(reader)
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, reader.Len(), 99)
+}
+
+func TestEncodedSizes(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 63, 64, -64, -65, 127, 128, 1 << 20, -1 << 20, math.MaxInt64, math.MinInt64} {
+		buffer := []byte{}
+		WriteUnsignedVarLong(&buffer, uint64(v))
+		assert.Equal(t, uvarintSize(uint64(v)), len(buffer))
+		buffer = buffer[:0]
+		WriteLEB128(&buffer, v)
+		assert.Equal(t, leb128Size(v), len(buffer))
+		for prefix := PrefixUnsignedVarInt; prefix <= PrefixLittleUnsignedInt; prefix++ {
+			buffer = buffer[:0]
+			WritePrefix(&buffer, prefix, int(int32(v)))
+			assert.Equal(t, prefixSize(prefix, int(int32(v))), len(buffer))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package binutils
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// logDumpWindow is the maximum amount of bytes around the error offset included in a log entry.
+const logDumpWindow = 32
+
+// Logger is used by a Stream to report errors.
+// It is satisfied by *slog.Logger.
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// SetLogger sets the logger the stream reports errors to.
+// Passing nil disables logging.
+func (stream *Stream) SetLogger(logger Logger) {
+	stream.logger = logger
+}
+
+// logError reports err to the logger of the stream, including the operation,
+// the offset and a bounded hexdump of the buffer around the offset.
+func (stream *Stream) logError(err error) {
+	op, offset := "", stream.Offset
+	var streamErr *StreamError
+	if errors.As(err, &streamErr) {
+		op, offset = streamErr.Op, streamErr.Offset
+	}
+
+	start := offset - logDumpWindow/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + logDumpWindow
+	if end > len(stream.Buffer) {
+		end = len(stream.Buffer)
+	}
+	if start > end {
+		start = end
+	}
+
+	stream.logger.Error("binutils: stream error",
+		"op", op,
+		"offset", offset,
+		"error", err,
+		"dump_start", start,
+		"dump", hex.EncodeToString(stream.Buffer[start:end]))
+}
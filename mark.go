@@ -0,0 +1,44 @@
+package binutils
+
+import "errors"
+
+// errNoMark is returned by ResetToMark and DiscardMark if no mark is set.
+var errNoMark = errors.New("binutils: no mark set")
+
+// mark is a position remembered by Mark.
+type mark struct {
+	offset int
+	err    error
+}
+
+// Mark remembers the current offset and error of the stream, so that a parser can backtrack to it with ResetToMark
+// after a speculative read. Marks are nested: each ResetToMark or DiscardMark removes the most recent mark.
+func (stream *Stream) Mark() {
+	stream.marks = append(stream.marks, mark{stream.Offset, stream.err})
+}
+
+// ResetToMark removes the most recent mark and restores the offset and error of the stream to the ones when it was set,
+// discarding errors set by the speculative read.
+func (stream *Stream) ResetToMark() error {
+	if len(stream.marks) == 0 {
+		return errNoMark
+	}
+	m := stream.marks[len(stream.marks)-1]
+	stream.marks = stream.marks[:len(stream.marks)-1]
+	stream.Offset, stream.err = m.offset, m.err
+	return nil
+}
+
+// DiscardMark removes the most recent mark without changing the offset, once a speculative read succeeded.
+func (stream *Stream) DiscardMark() error {
+	if len(stream.marks) == 0 {
+		return errNoMark
+	}
+	stream.marks = stream.marks[:len(stream.marks)-1]
+	return nil
+}
+
+// Marks returns the number of marks set.
+func (stream *Stream) Marks() int {
+	return len(stream.marks)
+}
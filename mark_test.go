@@ -0,0 +1,57 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestMark(t *testing.T) {
+	stream := &Stream{Buffer: b(1, 2, 3)}
+	stream.Mark()
+	stream.GetByte()
+	stream.Mark()
+	stream.GetInt()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+	assert.Equal(t, stream.Marks(), 2)
+
+	assert.NilError(t, stream.ResetToMark())
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, stream.GetByte(), byte(2))
+	assert.NilError(t, stream.ResetToMark())
+	assert.Equal(t, stream.Offset, 0)
+	assert.Error(t, stream.ResetToMark(), "binutils: no mark set")
+
+	stream.Mark()
+	stream.GetByte()
+	assert.NilError(t, stream.DiscardMark())
+	assert.Equal(t, stream.Offset, 1)
+	assert.Equal(t, stream.Marks(), 0)
+}
+
+func TestCompactKeepsMarks(t *testing.T) {
+	stream := &Stream{Buffer: b(1, 2, 3, 4)}
+	stream.GetByte()
+	stream.Mark()
+	stream.GetShort()
+	stream.Compact()
+	assert.DeepEqual(t, stream.Buffer, b(2, 3, 4))
+	assert.Equal(t, stream.Offset, 2)
+	assert.NilError(t, stream.ResetToMark())
+	assert.Equal(t, stream.GetByte(), byte(2))
+
+	stream.BeginSection(PrefixByte)
+	stream.PutByte(5)
+	stream.GetShort()
+	stream.Compact()
+	assert.NilError(t, stream.EndSection())
+	assert.DeepEqual(t, stream.Buffer, b(1, 5))
+
+	stream.Begin()
+	stream.PutByte(6)
+	stream.Offset = len(stream.Buffer)
+	stream.Compact()
+	assert.DeepEqual(t, stream.Buffer, b(6))
+	assert.NilError(t, stream.Rollback())
+	assert.Equal(t, len(stream.Buffer), 0)
+}
@@ -0,0 +1,806 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// wireType is the type a value has when encoded.
+type wireType int
+
+const (
+	wireDefault wireType = iota
+	wireBool
+	wireInt8
+	wireUint8
+	wireInt16
+	wireUint16
+	wireInt32
+	wireUint32
+	wireInt64
+	wireUint64
+	wireFloat32
+	wireFloat64
+	wireVarInt
+	wireVarLong
+	wireUnsignedVarInt
+	wireUnsignedVarLong
+	wireTriad
+	wireInt24
+)
+
+// wireTypes maps the names used in struct tags to wire types.
+var wireTypes = map[string]wireType{
+	"bool":     wireBool,
+	"int8":     wireInt8,
+	"uint8":    wireUint8,
+	"byte":     wireUint8,
+	"int16":    wireInt16,
+	"uint16":   wireUint16,
+	"int32":    wireInt32,
+	"uint32":   wireUint32,
+	"int64":    wireInt64,
+	"uint64":   wireUint64,
+	"float32":  wireFloat32,
+	"float64":  wireFloat64,
+	"varint":   wireVarInt,
+	"varlong":  wireVarLong,
+	"uvarint":  wireUnsignedVarInt,
+	"uvarlong": wireUnsignedVarLong,
+	"triad":    wireTriad,
+	"int24":    wireInt24,
+}
+
+// defaultWireTypes holds the wire types used for fields without a wire type in their tag.
+var defaultWireTypes = map[reflect.Kind]wireType{
+	reflect.Bool:    wireBool,
+	reflect.Int8:    wireInt8,
+	reflect.Uint8:   wireUint8,
+	reflect.Int16:   wireInt16,
+	reflect.Uint16:  wireUint16,
+	reflect.Int32:   wireInt32,
+	reflect.Uint32:  wireUint32,
+	reflect.Int64:   wireInt64,
+	reflect.Uint64:  wireUint64,
+	reflect.Int:     wireVarLong,
+	reflect.Uint:    wireUnsignedVarLong,
+	reflect.Float32: wireFloat32,
+	reflect.Float64: wireFloat64,
+}
+
+// compatible checks if values of the given kind can be encoded as the wire type.
+func (wire wireType) compatible(kind reflect.Kind) bool {
+	switch wire {
+	case wireBool:
+		return kind == reflect.Bool
+	case wireFloat32, wireFloat64:
+		return kind == reflect.Float32 || kind == reflect.Float64
+	default:
+		return kind >= reflect.Int && kind <= reflect.Uint64
+	}
+}
+
+// wireBits holds the widths in bits of the integer wire types.
+var wireBits = [...]uint{wireInt8: 8, wireUint8: 8, wireInt16: 16, wireUint16: 16, wireInt32: 32, wireUint32: 32,
+	wireInt64: 64, wireUint64: 64, wireVarInt: 32, wireVarLong: 64, wireUnsignedVarInt: 32, wireUnsignedVarLong: 64,
+	wireTriad: 24, wireInt24: 24}
+
+// String returns the name of the wire type used in struct tags.
+func (wire wireType) String() string {
+	for name, w := range wireTypes {
+		if w == wire && name != "byte" {
+			return name
+		}
+	}
+	return "default"
+}
+
+// signed checks if the wire type is a signed integer type.
+func (wire wireType) signed() bool {
+	switch wire {
+	case wireInt8, wireInt16, wireInt32, wireInt64, wireVarInt, wireVarLong, wireInt24:
+		return true
+	}
+	return false
+}
+
+// max returns the largest value of the integer wire type.
+func (wire wireType) max() uint64 {
+	if wire.signed() {
+		return 1<<(wireBits[wire]-1) - 1
+	}
+	return 1<<wireBits[wire] - 1
+}
+
+// fits checks if the integer u, negative if it is a negative signed integer, can be encoded as the wire type
+// and decoded back. Values of 64 bit wire types are reinterpreted rather than checked, like when decoding.
+func (wire wireType) fits(u uint64, negative bool) bool {
+	switch {
+	case wireBits[wire] == 64:
+		return true
+	case negative:
+		return wire.signed() && int64(u) >= -int64(wire.max())-1
+	}
+	return u <= wire.max()
+}
+
+// integer checks if the wire type is an integer type, which can be used as length prefix.
+func (wire wireType) integer() bool {
+	return wire != wireDefault && wire != wireBool && wire != wireFloat32 && wire != wireFloat64
+}
+
+// fieldTag is the parsed `bin` struct tag of a field.
+type fieldTag struct {
+	wire   wireType
+	endian EndianType
+	// endianSet is set if the byte order is given in the tag.
+	endianSet bool
+	prefix    wireType
+	// sizeOf and crcOf are the names of the sibling fields whose size or CRC-32 the field holds.
+	sizeOf string
+	crcOf  string
+	// compute is the name of the method computing the value of the field on encoding.
+	compute string
+}
+
+// structField is an exported field of a struct with its parsed tag.
+// The index is the index sequence of the field for reflect.Value.FieldByIndex, as fields of embedded structs are
+// flattened into the fields of the struct embedding them.
+type structField struct {
+	index []int
+	name  string
+	tag   fieldTag
+}
+
+// structFieldCache caches the fields of struct types, mapping reflect.Type to []structField.
+var structFieldCache sync.Map
+
+// structFields returns the exported fields of the struct type.
+func structFields(t reflect.Type) ([]structField, error) {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]structField), nil
+	}
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("bin") == "" {
+			embedded, err := structFields(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range embedded {
+				fields = append(fields, structField{append([]int{i}, f.index...), f.name, f.tag})
+			}
+			continue
+		}
+		if field.PkgPath != "" || field.Tag.Get("bin") == "-" {
+			continue
+		}
+		tag, err := parseTag(field.Tag.Get("bin"))
+		if err != nil {
+			return nil, fmt.Errorf("binutils: field %s of %s: %v", field.Name, t, err)
+		}
+		if kind := field.Type.Kind(); (tag.sizeOf != "" || tag.crcOf != "") && (kind < reflect.Int || kind > reflect.Uint64) {
+			return nil, fmt.Errorf("binutils: field %s of %s: sizeof and crc32 require an integer field", field.Name, t)
+		}
+		if tag.compute != "" {
+			if err := checkCompute(t, field, tag); err != nil {
+				return nil, fmt.Errorf("binutils: field %s of %s: %v", field.Name, t, err)
+			}
+		}
+		fields = append(fields, structField{[]int{i}, field.Name, tag})
+	}
+	for _, field := range fields {
+		for _, name := range []string{field.tag.sizeOf, field.tag.crcOf} {
+			if name != "" && findField(fields, name) < 0 {
+				return nil, fmt.Errorf("binutils: field %s of %s: unknown field %s", field.name, t, name)
+			}
+		}
+	}
+	structFieldCache.Store(t, fields)
+	return fields, nil
+}
+
+// checkCompute checks if the compute method of the field exists, takes no arguments,
+// and returns a single value convertible to the type of the field.
+func checkCompute(t reflect.Type, field reflect.StructField, tag fieldTag) error {
+	if tag.sizeOf != "" || tag.crcOf != "" {
+		return fmt.Errorf("compute cannot be combined with sizeof or crc32")
+	}
+	method, ok := reflect.PointerTo(t).MethodByName(tag.compute)
+	if !ok {
+		return fmt.Errorf("unknown method %s", tag.compute)
+	}
+	if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || !method.Type.Out(0).ConvertibleTo(field.Type) {
+		return fmt.Errorf("method %s must take no arguments and return a value convertible to %s", tag.compute, field.Type)
+	}
+	return nil
+}
+
+// computed returns the value of the field of the struct value computed by its compute method.
+func computed(value reflect.Value, field structField) reflect.Value {
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+	result := value.Addr().MethodByName(field.tag.compute).Call(nil)[0]
+	return result.Convert(value.Type().FieldByIndex(field.index).Type)
+}
+
+// findField returns the index in fields of the field with the given name, or -1 if there is none.
+func findField(fields []structField, name string) int {
+	for i, field := range fields {
+		if field.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseTag parses a `bin` struct tag.
+func parseTag(tag string) (fieldTag, error) {
+	parsed := fieldTag{endian: BigEndian, prefix: wireUnsignedVarInt}
+	if tag == "" {
+		return parsed, nil
+	}
+	for i, option := range strings.Split(tag, ",") {
+		switch {
+		case option == "le":
+			parsed.endian, parsed.endianSet = LittleEndian, true
+		case option == "be":
+			parsed.endian, parsed.endianSet = BigEndian, true
+		case strings.HasPrefix(option, "prefix="):
+			wire, ok := wireTypes[strings.TrimPrefix(option, "prefix=")]
+			if !ok || !wire.integer() {
+				return parsed, fmt.Errorf("invalid length prefix %q", option)
+			}
+			parsed.prefix = wire
+		case strings.HasPrefix(option, "sizeof=") && len(option) > len("sizeof="):
+			parsed.sizeOf = strings.TrimPrefix(option, "sizeof=")
+		case strings.HasPrefix(option, "crc32=") && len(option) > len("crc32="):
+			parsed.crcOf = strings.TrimPrefix(option, "crc32=")
+		case strings.HasPrefix(option, "compute=") && len(option) > len("compute="):
+			parsed.compute = strings.TrimPrefix(option, "compute=")
+		case i == 0 && option == "":
+		case i == 0:
+			wire, ok := wireTypes[option]
+			if !ok {
+				return parsed, fmt.Errorf("unknown wire type %q", option)
+			}
+			parsed.wire = wire
+		default:
+			return parsed, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return parsed, nil
+}
+
+// Marshal returns the encoding of v, which must be a struct or a pointer to a struct.
+//
+// Exported fields are encoded in order, as described by their `bin` struct tag. Fields tagged `bin:"-"` are skipped.
+// The tag holds the wire type of the field followed by options, for example `bin:"uint16,le"`.
+// The wire types are bool, int8, uint8, int16, uint16, int32, uint32, int64, uint64, float32, float64,
+// varint, varlong, uvarint, uvarlong, triad and int24. Without a wire type, a field is encoded as the
+// fixed width type matching its Go type, with int and uint encoded as varlong and uvarlong.
+// The options are:
+//
+//	le, be            the byte order of the field, big endian by default
+//	prefix=<type>     the wire type of the length prefix of strings and slices, uvarint by default
+//	sizeof=<field>    the field holds the encoded size of the sibling field, and is computed on encoding
+//	crc32=<field>     the field holds the CRC-32 (IEEE) of the encoding of the sibling field, and is computed on encoding
+//	compute=<method>  the field is encoded as the value returned by the exported method of the struct, such as a length
+//	                  or checksum derived from other fields, and is decoded like other fields
+//
+// Strings and slices are prefixed with their length, arrays are not. Integers out of range of their wire type,
+// other than of 64 bit wire types, and lengths out of range of their prefix are errors rather than truncated.
+// The wire type of a slice or array applies to its elements. Nested structs are encoded field by field,
+// and the fields of embedded structs are encoded as if they were fields of the struct embedding them.
+// Embedded pointers to structs are not flattened, but encoded like other pointer fields.
+// Pointer fields are prefixed with a bool, and only followed by the value they point to if they are not nil.
+// Structs nested deeper than the depth limit of the stream, such as cyclic pointers, fail with a *DepthError.
+// Interface fields are prefixed the same way, followed by the packet they hold as encoded by MarshalOptions.Registry.
+// MarshalOptions changes the default byte order, and provides C struct layout.
+func Marshal(v interface{}) ([]byte, error) {
+	stream := NewStream()
+	if err := stream.PutStruct(v); err != nil {
+		return nil, err
+	}
+	return stream.Buffer, nil
+}
+
+// Unmarshal decodes the buffer into v, which must be a pointer to a struct.
+// The buffer is decoded as described for Marshal.
+func Unmarshal(buffer []byte, v interface{}) error {
+	stream := &Stream{Buffer: buffer}
+	return stream.GetStruct(v)
+}
+
+// UnmarshalWithOffsets decodes the buffer into v like Unmarshal, and returns the range of the buffer
+// each field was decoded from. Fields are identified by their path, such as "Header.ID" for the field ID
+// of the nested struct Header, or "Items[2].Name" for the field Name of the third element of the slice Items.
+// Ranges are also recorded for struct elements of slices and arrays, such as "Items[2]".
+// If decoding fails, the ranges of the fields decoded until then are returned with the error.
+func UnmarshalWithOffsets(buffer []byte, v interface{}) (map[string]Range, error) {
+	stream := &Stream{Buffer: buffer}
+	return stream.GetStructWithOffsets(v)
+}
+
+// PutStruct writes v, which must be a struct or a pointer to a struct, as described for Marshal.
+func (stream *Stream) PutStruct(v interface{}) error {
+	return MarshalOptions{}.PutStruct(stream, v)
+}
+
+// GetStruct reads into v, which must be a pointer to a struct, as described for Marshal.
+// Reading errors are set on the stream and returned.
+func (stream *Stream) GetStruct(v interface{}) error {
+	return MarshalOptions{}.GetStruct(stream, v)
+}
+
+// Range is the range of bytes from Start up to End in a buffer.
+type Range struct {
+	Start int
+	End   int
+}
+
+// Len returns the number of bytes in the range.
+func (r Range) Len() int {
+	return r.End - r.Start
+}
+
+// fieldRange is the range of bytes a field was encoded in or decoded from.
+type fieldRange struct {
+	path  string
+	value reflect.Value
+	tag   fieldTag
+	Range
+}
+
+// GetStructWithOffsets reads into v like GetStruct, and returns the range of the buffer each field was read from,
+// as described for UnmarshalWithOffsets.
+func (stream *Stream) GetStructWithOffsets(v interface{}) (map[string]Range, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("binutils: cannot unmarshal into %T, expected a pointer to a struct", v)
+	}
+	m := &marshaler{stream: stream, track: true}
+	err := m.decode(value.Elem(), fieldTag{}, "")
+	if err == nil {
+		err = stream.err
+	}
+	return m.ranges(), err
+}
+
+// ranges returns the tracked ranges by path of the fields.
+func (m *marshaler) ranges() map[string]Range {
+	ranges := make(map[string]Range, len(m.fields))
+	for _, field := range m.fields {
+		ranges[field.path] = field.Range
+	}
+	return ranges
+}
+
+// marshaler encodes and decodes values on a stream.
+type marshaler struct {
+	stream  *Stream
+	options MarshalOptions
+	// base is the position in the buffer at which the outermost struct starts, which alignment is relative to.
+	base int
+	// track enables recording the ranges of fields, struct elements of slices and arrays, in fields.
+	track  bool
+	fields []fieldRange
+	// dependents is set if a field with sizeof or crc32 was encoded.
+	dependents bool
+}
+
+// begin records the start of the value at the path if ranges are tracked, and returns the index of its range.
+func (m *marshaler) begin(path string, value reflect.Value, tag fieldTag, start int) int {
+	if !m.track {
+		return -1
+	}
+	m.fields = append(m.fields, fieldRange{path, value, tag, Range{start, start}})
+	return len(m.fields) - 1
+}
+
+// end records the end of the range with the given index.
+func (m *marshaler) end(index int, end int) {
+	if index >= 0 {
+		m.fields[index].End = end
+	}
+}
+
+// fieldPath returns the path of the field with the given name in the struct at the parent path.
+// Paths are only built if ranges are tracked.
+func (m *marshaler) fieldPath(parent string, name string) string {
+	if !m.track || parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// elementPath returns the path of the element at index i of the slice or array at the parent path.
+// Paths are only built if ranges are tracked.
+func (m *marshaler) elementPath(parent string, i int) string {
+	if !m.track {
+		return ""
+	}
+	return fmt.Sprintf("%s[%d]", parent, i)
+}
+
+// encode writes the value at the path with the given tag.
+func (m *marshaler) encode(value reflect.Value, tag fieldTag, path string) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		fields, err := structFields(value.Type())
+		if err != nil {
+			return err
+		}
+		// Pointers back to a struct being encoded would recurse forever, which the depth limit stops.
+		if !m.stream.Enter() {
+			return m.stream.err
+		}
+		defer m.stream.Leave()
+		for _, field := range fields {
+			fieldValue := value.FieldByIndex(field.index)
+			if field.tag.compute != "" {
+				fieldValue = computed(value, field)
+			}
+			fieldPath := m.fieldPath(path, field.name)
+			if err := m.alignField(fieldValue.Type(), field.tag, true); err != nil {
+				return err
+			}
+			index := m.begin(fieldPath, fieldValue, field.tag, len(m.stream.Buffer))
+			if field.tag.sizeOf != "" || field.tag.crcOf != "" {
+				m.dependents = true
+			}
+			if err := m.encode(fieldValue, field.tag, fieldPath); err != nil {
+				return err
+			}
+			m.end(index, len(m.stream.Buffer))
+		}
+		if err := m.alignField(value.Type(), tag, true); err != nil {
+			return err
+		}
+	case reflect.Ptr, reflect.Interface:
+		m.stream.PutBool(!value.IsNil())
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Interface {
+			return m.encodeInterface(value)
+		}
+		return m.encode(value.Elem(), tag, path)
+	case reflect.String:
+		if !m.putLength(tag, value.Len()) {
+			return m.stream.err
+		}
+		m.stream.PutBytes([]byte(value.String()))
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && !m.putLength(tag, value.Len()) {
+			return m.stream.err
+		}
+		if tag.wire == wireDefault && value.Type().Elem().Kind() == reflect.Uint8 && value.Kind() == reflect.Slice {
+			m.stream.PutBytes(value.Bytes())
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			index := m.beginElement(path, value, i, len(m.stream.Buffer))
+			if err := m.encode(value.Index(i), tag, m.elementPath(path, i)); err != nil {
+				return err
+			}
+			m.end(index, len(m.stream.Buffer))
+		}
+	default:
+		wire, err := resolveWire(value.Type(), tag.wire)
+		if err != nil {
+			return err
+		}
+		var u uint64
+		var f float64
+		switch value.Kind() {
+		case reflect.Bool:
+			if value.Bool() {
+				u = 1
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			u = uint64(value.Int())
+			if !wire.fits(u, value.Int() < 0) {
+				m.stream.SetError(&StreamError{"PutStruct", len(m.stream.Buffer), fmt.Errorf("value %d overflows %s", value.Int(), wire)})
+				return m.stream.err
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u = value.Uint()
+			if !wire.fits(u, false) {
+				m.stream.SetError(&StreamError{"PutStruct", len(m.stream.Buffer), fmt.Errorf("value %d overflows %s", u, wire)})
+				return m.stream.err
+			}
+		case reflect.Float32, reflect.Float64:
+			f = value.Float()
+		}
+		m.putNumber(wire, m.endian(tag), u, f)
+	}
+	return nil
+}
+
+// encodeInterface writes the packet held by the interface value with the registry of the options.
+func (m *marshaler) encodeInterface(value reflect.Value) error {
+	if m.options.Registry == nil {
+		return fmt.Errorf("binutils: interface field of type %s requires a registry", value.Type())
+	}
+	packet, ok := value.Interface().(Serializable)
+	if !ok {
+		return fmt.Errorf("binutils: %s does not implement Serializable", value.Elem().Type())
+	}
+	err := m.options.Registry.Encode(m.stream, packet)
+	if m.stream.err != nil {
+		return nil
+	}
+	return err
+}
+
+// decodeInterface reads a packet with the registry of the options into the interface value.
+func (m *marshaler) decodeInterface(value reflect.Value) error {
+	if m.options.Registry == nil {
+		return fmt.Errorf("binutils: interface field of type %s requires a registry", value.Type())
+	}
+	_, packet, _ := m.options.Registry.Decode(m.stream)
+	if m.stream.err != nil {
+		return nil
+	}
+	if !reflect.TypeOf(packet).AssignableTo(value.Type()) {
+		return fmt.Errorf("binutils: %T cannot be assigned to %s", packet, value.Type())
+	}
+	value.Set(reflect.ValueOf(packet))
+	return nil
+}
+
+// beginElement records the start of the element of the slice or array at the path if it is a struct.
+func (m *marshaler) beginElement(path string, value reflect.Value, i int, start int) int {
+	if value.Type().Elem().Kind() != reflect.Struct {
+		return -1
+	}
+	return m.begin(m.elementPath(path, i), value.Index(i), fieldTag{}, start)
+}
+
+// decode reads into the value at the path with the given tag.
+func (m *marshaler) decode(value reflect.Value, tag fieldTag, path string) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		fields, err := structFields(value.Type())
+		if err != nil {
+			return err
+		}
+		if !m.stream.Enter() {
+			return nil
+		}
+		defer m.stream.Leave()
+		offset := m.stream.Offset
+		for _, field := range fields {
+			fieldValue := value.FieldByIndex(field.index)
+			fieldPath := m.fieldPath(path, field.name)
+			if err := m.alignField(fieldValue.Type(), field.tag, false); err != nil {
+				return err
+			}
+			index := m.begin(fieldPath, fieldValue, field.tag, m.stream.Offset)
+			if err := m.decode(fieldValue, field.tag, fieldPath); err != nil {
+				return err
+			}
+			m.end(index, m.stream.Offset)
+			if m.stream.err != nil {
+				return nil
+			}
+		}
+		if err := m.alignField(value.Type(), tag, false); err != nil {
+			return err
+		}
+		m.validate(value, offset)
+	case reflect.Ptr, reflect.Interface:
+		if !m.stream.GetBool() {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		if value.Kind() == reflect.Interface {
+			return m.decodeInterface(value)
+		}
+		elem := reflect.New(value.Type().Elem())
+		if err := m.decode(elem.Elem(), tag, path); err != nil {
+			return err
+		}
+		value.Set(elem)
+	case reflect.String:
+		length, ok := m.getLength(tag, m.stream.maxStringLength)
+		if !ok {
+			return nil
+		}
+		value.SetString(string(m.stream.Get(length)))
+	case reflect.Slice:
+		bytes := tag.wire == wireDefault && value.Type().Elem().Kind() == reflect.Uint8
+		max := m.stream.maxSliceLength
+		if bytes {
+			max = m.stream.maxStringLength
+		}
+		length, ok := m.getLength(tag, max)
+		if !ok {
+			return nil
+		}
+		if bytes {
+			value.SetBytes(append([]byte{}, m.stream.Get(length)...))
+			return nil
+		}
+		slice := reflect.MakeSlice(value.Type(), length, length)
+		for i := 0; i < length && m.stream.err == nil; i++ {
+			index := m.beginElement(path, slice, i, m.stream.Offset)
+			if err := m.decode(slice.Index(i), tag, m.elementPath(path, i)); err != nil {
+				return err
+			}
+			m.end(index, m.stream.Offset)
+		}
+		value.Set(slice)
+	case reflect.Array:
+		for i := 0; i < value.Len() && m.stream.err == nil; i++ {
+			index := m.beginElement(path, value, i, m.stream.Offset)
+			if err := m.decode(value.Index(i), tag, m.elementPath(path, i)); err != nil {
+				return err
+			}
+			m.end(index, m.stream.Offset)
+		}
+	default:
+		wire, err := resolveWire(value.Type(), tag.wire)
+		if err != nil {
+			return err
+		}
+		offset := m.stream.Offset
+		u, f := m.getNumber(wire, m.endian(tag))
+		switch value.Kind() {
+		case reflect.Bool:
+			value.SetBool(u != 0)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.OverflowInt(int64(u)) {
+				m.stream.SetError(&StreamError{"GetStruct", offset, fmt.Errorf("value %d overflows %s", int64(u), value.Type())})
+				return nil
+			}
+			value.SetInt(int64(u))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if value.OverflowUint(u) {
+				m.stream.SetError(&StreamError{"GetStruct", offset, fmt.Errorf("value %d overflows %s", u, value.Type())})
+				return nil
+			}
+			value.SetUint(u)
+		case reflect.Float32, reflect.Float64:
+			value.SetFloat(f)
+		}
+	}
+	return nil
+}
+
+// resolveWire returns the wire type used for values of type t with the wire type of their tag.
+func resolveWire(t reflect.Type, wire wireType) (wireType, error) {
+	if wire == wireDefault {
+		if wire, ok := defaultWireTypes[t.Kind()]; ok {
+			return wire, nil
+		}
+		return wireDefault, fmt.Errorf("binutils: cannot marshal values of type %s", t)
+	}
+	if !wire.compatible(t.Kind()) {
+		return wireDefault, fmt.Errorf("binutils: wire type does not match type %s", t)
+	}
+	return wire, nil
+}
+
+// putLength writes a length prefix of the given tag. A *LengthError is set on the stream if the length
+// overflows the prefix, and false returned.
+func (m *marshaler) putLength(tag fieldTag, length int) bool {
+	if !tag.prefix.fits(uint64(length), false) {
+		m.stream.SetError(&StreamError{"PutStruct", len(m.stream.Buffer), &LengthError{length, int(min(tag.prefix.max(), math.MaxInt))}})
+		return false
+	}
+	m.putNumber(tag.prefix, m.endian(tag), uint64(length), 0)
+	return true
+}
+
+// getLength reads a length prefix of the given tag.
+// An error is set on the stream if the length exceeds max, unless max is 0 or less, or the bytes left in the buffer.
+func (m *marshaler) getLength(tag fieldTag, max int) (int, bool) {
+	offset := m.stream.Offset
+	u, _ := m.getNumber(tag.prefix, m.endian(tag))
+	if m.stream.err != nil {
+		return 0, false
+	}
+	if max > 0 && u > uint64(max) {
+		m.stream.SetError(&StreamError{"GetStruct", offset, &LengthError{int(min(u, math.MaxInt)), max}})
+		return 0, false
+	}
+	if u > math.MaxInt || !m.stream.available("GetStruct", int(u), 1) {
+		m.stream.SetError(&StreamError{"GetStruct", offset, ErrUnexpectedEOF})
+		return 0, false
+	}
+	return int(u), true
+}
+
+// putNumber writes a number as the wire type in the given byte order.
+// Integers are passed as u, floats as f.
+func (m *marshaler) putNumber(wire wireType, endian EndianType, u uint64, f float64) {
+	stream := m.stream
+	defer stream.SetEndianness(stream.endian)
+	stream.endian = endian
+
+	switch wire {
+	case wireBool:
+		stream.PutBool(u != 0)
+	case wireInt8, wireUint8:
+		stream.PutByte(byte(u))
+	case wireInt16, wireUint16:
+		stream.PutUnsignedShort(uint16(u))
+	case wireInt32, wireUint32:
+		stream.PutUnsignedInt(uint32(u))
+	case wireInt64, wireUint64:
+		stream.PutUnsignedLong(u)
+	case wireFloat32:
+		stream.PutFloat(float32(f))
+	case wireFloat64:
+		stream.PutDouble(f)
+	case wireVarInt:
+		stream.PutVarInt(int32(u))
+	case wireVarLong:
+		stream.PutVarLong(int64(u))
+	case wireUnsignedVarInt:
+		stream.PutUnsignedVarInt(uint32(u))
+	case wireUnsignedVarLong:
+		stream.PutUnsignedVarLong(u)
+	case wireTriad:
+		stream.PutTriad(uint32(u))
+	case wireInt24:
+		stream.PutInt24(int32(u))
+	}
+}
+
+// getNumber reads a number of the wire type in the given byte order.
+// Integers are returned as u, sign extended for signed wire types, and floats as f.
+func (m *marshaler) getNumber(wire wireType, endian EndianType) (u uint64, f float64) {
+	stream := m.stream
+	defer stream.SetEndianness(stream.endian)
+	stream.endian = endian
+
+	switch wire {
+	case wireBool:
+		if stream.GetBool() {
+			u = 1
+		}
+	case wireInt8:
+		u = uint64(int8(stream.GetByte()))
+	case wireUint8:
+		u = uint64(stream.GetByte())
+	case wireInt16:
+		u = uint64(stream.GetShort())
+	case wireUint16:
+		u = uint64(stream.GetUnsignedShort())
+	case wireInt32:
+		u = uint64(stream.GetInt())
+	case wireUint32:
+		u = uint64(stream.GetUnsignedInt())
+	case wireInt64, wireUint64:
+		u = stream.GetUnsignedLong()
+	case wireFloat32:
+		f = float64(stream.GetFloat())
+	case wireFloat64:
+		f = stream.GetDouble()
+	case wireVarInt:
+		u = uint64(stream.GetVarInt())
+	case wireVarLong:
+		u = uint64(stream.GetVarLong())
+	case wireUnsignedVarInt:
+		u = uint64(stream.GetUnsignedVarInt())
+	case wireUnsignedVarLong:
+		u = stream.GetUnsignedVarLong()
+	case wireTriad:
+		u = uint64(stream.GetTriad())
+	case wireInt24:
+		u = uint64(stream.GetInt24())
+	}
+	return u, f
+}
@@ -0,0 +1,301 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	"testing"
+	"testing/iotest"
+)
+
+type testHeader struct {
+	ID      uint16 `bin:"uint16,le"`
+	Flags   byte
+	Version int `bin:"varint"`
+}
+
+type testMarshalPacket struct {
+	Header   testHeader
+	Name     string `bin:",prefix=uint16"`
+	Position [3]float32
+	Scores   []int64 `bin:"varlong"`
+	Payload  []byte
+	Enabled  bool
+	Sequence uint32 `bin:"triad,le"`
+	hidden   int
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	packet := testMarshalPacket{
+		Header:   testHeader{ID: 0x0102, Flags: 0x80, Version: -3},
+		Name:     "steve",
+		Position: [3]float32{1.5, -2, 64},
+		Scores:   []int64{1, -1, 300},
+		Payload:  []byte{0xde, 0xad},
+		Enabled:  true,
+		Sequence: 0x030201,
+	}
+	buffer, err := Marshal(&packet)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer[:11], b(0x02, 0x01, 0x80, 0x05, 0x00, 0x05, 's', 't', 'e', 'v', 'e'))
+
+	var decoded testMarshalPacket
+	assert.NilError(t, Unmarshal(buffer, &decoded))
+	assert.DeepEqual(t, decoded, packet, cmp.AllowUnexported(testMarshalPacket{}))
+}
+
+func TestGetStructReaderStream(t *testing.T) {
+	packet := testMarshalPacket{Name: "steve", Scores: []int64{1, 2}, Payload: []byte{3, 4}}
+	buffer, err := Marshal(packet)
+	assert.NilError(t, err)
+
+	stream := NewReaderStream(iotest.OneByteReader(bytes.NewReader(buffer)))
+	var decoded testMarshalPacket
+	assert.NilError(t, stream.GetStruct(&decoded))
+	assert.DeepEqual(t, decoded, packet, cmp.AllowUnexported(testMarshalPacket{}))
+}
+
+func TestUnmarshalTruncated(t *testing.T) {
+	buffer, err := Marshal(testMarshalPacket{Name: "steve"})
+	assert.NilError(t, err)
+
+	var decoded testMarshalPacket
+	err = Unmarshal(buffer[:len(buffer)-2], &decoded)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+}
+
+func TestUnmarshalHostileLength(t *testing.T) {
+	var decoded struct {
+		Scores []int64
+	}
+	err := Unmarshal(b(0xff, 0xff, 0xff, 0xff, 0x0f), &decoded)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+}
+
+func TestMarshalInvalidTag(t *testing.T) {
+	_, err := Marshal(struct {
+		Value float32 `bin:"varint"`
+	}{})
+	assert.ErrorContains(t, err, "wire type")
+
+	_, err = Marshal(struct {
+		Value int32 `bin:"int128"`
+	}{})
+	assert.ErrorContains(t, err, "unknown wire type")
+}
+
+func TestMarshalOverflow(t *testing.T) {
+	_, err := Marshal(&struct {
+		A int `bin:"uint8"`
+	}{300})
+	assert.ErrorContains(t, err, "value 300 overflows uint8")
+	_, err = Marshal(struct {
+		A int16 `bin:"uint16"`
+	}{-1})
+	assert.ErrorContains(t, err, "value -1 overflows uint16")
+	_, err = Marshal(struct {
+		A uint32 `bin:"int24"`
+	}{1 << 23})
+	assert.ErrorContains(t, err, "overflows int24")
+
+	buffer, err := Marshal(struct {
+		A int   `bin:"int8"`
+		B int64 `bin:"uint64"`
+	}{-128, -1})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0x80, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff))
+
+	_, err = Marshal(struct {
+		Name string `bin:",prefix=uint8"`
+	}{string(make([]byte, 300))})
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(err, &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{300, 255})
+	_, err = Marshal(struct {
+		Scores []int64 `bin:",prefix=int8"`
+	}{make([]int64, 128)})
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+}
+
+func TestUnmarshalWithOffsets(t *testing.T) {
+	type item struct {
+		Count uint16
+	}
+	type packet struct {
+		Header testHeader
+		Name   string
+		Items  []item
+	}
+	buffer, err := Marshal(packet{Name: "steve", Items: []item{{1}, {2}}})
+	assert.NilError(t, err)
+
+	var decoded packet
+	ranges, err := UnmarshalWithOffsets(buffer, &decoded)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ranges, map[string]Range{
+		"Header":         {0, 4},
+		"Header.ID":      {0, 2},
+		"Header.Flags":   {2, 3},
+		"Header.Version": {3, 4},
+		"Name":           {4, 10},
+		"Items":          {10, 15},
+		"Items[0]":       {11, 13},
+		"Items[0].Count": {11, 13},
+		"Items[1]":       {13, 15},
+		"Items[1].Count": {13, 15},
+	})
+	assert.Equal(t, ranges["Name"].Len(), 6)
+
+	ranges, err = UnmarshalWithOffsets(buffer[:12], &decoded)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	assert.Equal(t, ranges["Items"], Range{10, 11})
+}
+
+func TestMarshalInt24(t *testing.T) {
+	type packet struct {
+		Delta int32 `bin:"int24,le"`
+	}
+	buffer, err := Marshal(packet{-3})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0xfd, 0xff, 0xff))
+	var decoded packet
+	assert.NilError(t, Unmarshal(buffer, &decoded))
+	assert.Equal(t, decoded.Delta, int32(-3))
+}
+
+func TestDepthLimitStruct(t *testing.T) {
+	buffer, err := Marshal(testMarshalPacket{})
+	assert.NilError(t, err)
+
+	stream := &Stream{Buffer: buffer}
+	stream.SetMaxDepth(1)
+	err = stream.GetStruct(&testMarshalPacket{})
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, stream.Depth(), 0)
+}
+
+type testCycleNode struct {
+	Value byte
+	Next  *testCycleNode
+}
+
+// TestEmbeddedHeader is exported, so that the pointer to it embedded by testEmbeddedPointer is encoded.
+type TestEmbeddedHeader struct {
+	ID byte
+}
+
+type testEmbeddedPointer struct {
+	*TestEmbeddedHeader
+	Value byte
+}
+
+func TestMarshalCycle(t *testing.T) {
+	node := &testCycleNode{Value: 1}
+	node.Next = node
+	stream := NewStream()
+	err := stream.PutStruct(node)
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, stream.Depth(), 0)
+
+	node.Next = &testCycleNode{Value: 2}
+	buffer, err := Marshal(node)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0x01, 0x01, 0x02, 0x00))
+
+	// Embedded pointers are encoded like pointer fields, rather than flattened.
+	buffer, err = Marshal(testEmbeddedPointer{&TestEmbeddedHeader{7}, 3})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0x01, 0x07, 0x03))
+}
+
+func TestGetStructSafeLimits(t *testing.T) {
+	buffer, err := Marshal(testMarshalPacket{Name: "steve", Scores: []int64{1, 2, 3}})
+	assert.NilError(t, err)
+
+	stream := NewSafeStream(buffer)
+	stream.SetMaxSliceLength(2)
+	err = stream.GetStruct(&testMarshalPacket{})
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(err, &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{3, 2})
+
+	stream = NewSafeStream(buffer)
+	stream.SetMaxStringLength(4)
+	err = stream.GetStruct(&testMarshalPacket{})
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+}
+
+type testComputedPacket struct {
+	Count  uint8 `bin:",compute=ItemCount"`
+	Items  []uint16
+	Cache  map[string]int `bin:"-"`
+	Parity bool           `bin:",compute=Odd"`
+}
+
+func (packet *testComputedPacket) ItemCount() int {
+	return len(packet.Items)
+}
+
+func (packet testComputedPacket) Odd() bool {
+	return len(packet.Items)%2 == 1
+}
+
+func TestMarshalComputedAndSkipped(t *testing.T) {
+	packet := testComputedPacket{Items: []uint16{1, 2, 3}, Cache: map[string]int{"a": 1}}
+	buffer, err := Marshal(packet)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(3, 3, 0, 1, 0, 2, 0, 3, 1))
+	assert.Equal(t, packet.Count, uint8(0))
+
+	var decoded testComputedPacket
+	assert.NilError(t, Unmarshal(buffer, &decoded))
+	assert.DeepEqual(t, decoded, testComputedPacket{Count: 3, Items: []uint16{1, 2, 3}, Parity: true})
+
+	_, err = Marshal(struct {
+		Count int `bin:",compute=Missing"`
+	}{})
+	assert.ErrorContains(t, err, "unknown method Missing")
+}
+
+type testEmbeddedBase struct {
+	ID uint16
+}
+
+type testNestedPacket struct {
+	testEmbeddedBase
+	Extra   *uint16 `bin:"uint16,le"`
+	Header  *testHeader
+	Payload Serializable
+}
+
+func TestMarshalEmbeddedPointerInterface(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(0x01, func() Serializable { return &testPacket{} })
+	options := MarshalOptions{Registry: registry}
+
+	extra := uint16(0x0102)
+	packet := testNestedPacket{testEmbeddedBase{7}, &extra, nil, &testPacket{-1}}
+	buffer, err := options.Marshal(&packet)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0x00, 0x07, 0x01, 0x02, 0x01, 0x00, 0x01, 0x01, 0x01))
+
+	var decoded testNestedPacket
+	assert.NilError(t, options.Unmarshal(buffer, &decoded))
+	assert.DeepEqual(t, decoded, packet, cmp.AllowUnexported(testNestedPacket{}, testPacket{}))
+
+	ranges, err := UnmarshalWithOffsets(buffer[:4], &decoded)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	assert.Equal(t, ranges["ID"], Range{0, 2})
+
+	_, err = Marshal(&packet)
+	assert.ErrorContains(t, err, "requires a registry")
+
+	registry = NewRegistry()
+	registry.Register(0x01, func() Serializable { return &testOtherPacket{} })
+	err = MarshalOptions{Registry: registry}.Unmarshal(buffer, &decoded)
+	assert.NilError(t, err)
+	assert.Equal(t, decoded.Payload.(*testOtherPacket).Value, int32(-1))
+}
@@ -0,0 +1,134 @@
+package binutils
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// AddressError is an error of an operation on a memory image at an address.
+type AddressError struct {
+	Op      string
+	Address uint64
+	Err     error
+}
+
+func (err *AddressError) Error() string {
+	return fmt.Sprintf("binutils: %s at address 0x%x: %v", err.Op, err.Address, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *AddressError) Unwrap() error {
+	return err.Err
+}
+
+// region is a block of memory mapped at a base address.
+type region struct {
+	base uint64
+	data []byte
+}
+
+// MemoryImage interprets raw memory dumps, such as the segments of a core file, by their virtual addresses.
+// Pointers are read with the pointer size and byte order of the dumped process.
+type MemoryImage struct {
+	pointerSize int
+	endian      EndianType
+	regions     []region
+}
+
+// NewMemoryImage returns a new empty memory image of a process with pointers of pointerSize bytes, which is 4 or 8,
+// in the given byte order.
+func NewMemoryImage(pointerSize int, endian EndianType) (*MemoryImage, error) {
+	if pointerSize != 4 && pointerSize != 8 {
+		return nil, fmt.Errorf("binutils: invalid pointer size %d", pointerSize)
+	}
+	return &MemoryImage{pointerSize: pointerSize, endian: endian}, nil
+}
+
+// Map maps the dumped bytes at the base address. Regions mapped later take precedence where regions overlap.
+func (image *MemoryImage) Map(base uint64, data []byte) {
+	image.regions = append(image.regions, region{base, data})
+}
+
+// PointerSize returns the size of pointers in bytes.
+func (image *MemoryImage) PointerSize() int {
+	return image.pointerSize
+}
+
+// Bytes returns the n bytes at the address, sharing the memory of the dump.
+// The bytes must lie within a single mapped region; ErrUnexpectedEOF is returned otherwise.
+func (image *MemoryImage) Bytes(address uint64, n int) ([]byte, error) {
+	data, ok := image.find(address)
+	if !ok || n < 0 || n > len(data) {
+		return nil, &AddressError{"Bytes", address, ErrUnexpectedEOF}
+	}
+	return data[:n:n], nil
+}
+
+// find returns the mapped bytes from the address up to the end of its region.
+func (image *MemoryImage) find(address uint64) ([]byte, bool) {
+	for i := len(image.regions) - 1; i >= 0; i-- {
+		r := image.regions[i]
+		if address >= r.base && address-r.base < uint64(len(r.data)) {
+			return r.data[address-r.base:], true
+		}
+	}
+	return nil, false
+}
+
+// Stream returns a stream reading from the address up to the end of its region in the byte order and with the
+// pointer size of the image, for decoding structures with the Get functions.
+func (image *MemoryImage) Stream(address uint64) (*Stream, error) {
+	data, ok := image.find(address)
+	if !ok {
+		return nil, &AddressError{"Stream", address, ErrUnexpectedEOF}
+	}
+	stream := NewStreamEndian(image.endian)
+	stream.SetPointerSize(image.pointerSize)
+	stream.Buffer = data[:len(data):len(data)]
+	return stream, nil
+}
+
+// Pointer reads the pointer at the address.
+func (image *MemoryImage) Pointer(address uint64) (uint64, error) {
+	b, err := image.Bytes(address, image.pointerSize)
+	if err != nil {
+		return 0, &AddressError{"Pointer", address, ErrUnexpectedEOF}
+	}
+	if image.pointerSize == 4 {
+		return uint64(number[uint32](b, byteOrder(image.endian))), nil
+	}
+	return number[uint64](b, byteOrder(image.endian)), nil
+}
+
+// Follow resolves a pointer chain starting at the address: for each offset, the pointer at the current address
+// is read and the offset is added to it. It returns the final address, which is not dereferenced.
+// With no offsets, the address is returned as is.
+func (image *MemoryImage) Follow(address uint64, offsets ...int64) (uint64, error) {
+	for _, offset := range offsets {
+		pointer, err := image.Pointer(address)
+		if err != nil {
+			return 0, err
+		}
+		address = pointer + uint64(offset)
+	}
+	return address, nil
+}
+
+// CString reads the zero terminated string at the address. The terminator must lie within the region of the address.
+func (image *MemoryImage) CString(address uint64) (string, error) {
+	data, ok := image.find(address)
+	i := bytes.IndexByte(data, 0)
+	if !ok || i < 0 {
+		return "", &AddressError{"CString", address, ErrUnexpectedEOF}
+	}
+	return string(data[:i]), nil
+}
+
+// ReadMemory reads a number of any fixed size type at the address of the memory image, in its byte order.
+func ReadMemory[T Number](image *MemoryImage, address uint64) (T, error) {
+	b, err := image.Bytes(address, sizeOf[T]())
+	if err != nil {
+		return 0, &AddressError{"ReadMemory", address, ErrUnexpectedEOF}
+	}
+	return number[T](b, byteOrder(image.endian)), nil
+}
@@ -0,0 +1,56 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestMemoryImage(t *testing.T) {
+	_, err := NewMemoryImage(2, LittleEndian)
+	assert.ErrorContains(t, err, "invalid pointer size")
+
+	image, err := NewMemoryImage(8, LittleEndian)
+	assert.NilError(t, err)
+	heap := make([]byte, 32)
+	copy(heap, b(0x10, 0x10, 0, 0, 0, 0, 0, 0))
+	copy(heap[8:], "name\x00")
+	copy(heap[16:], b(0x2a, 0, 0, 0))
+	image.Map(0x1000, heap)
+	image.Map(0x2000, b(0x00, 0x10, 0, 0, 0, 0, 0, 0))
+
+	address, err := image.Follow(0x2000, 8)
+	assert.NilError(t, err)
+	assert.Equal(t, address, uint64(0x1008))
+	name, err := image.CString(address)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "name")
+
+	address, err = image.Follow(0x2000, 0, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, address, uint64(0x1010))
+	v, err := ReadMemory[int32](image, address)
+	assert.NilError(t, err)
+	assert.Equal(t, v, int32(42))
+	stream, err := image.Stream(0x1010)
+	assert.NilError(t, err)
+	assert.Equal(t, stream.GetInt(), int32(42))
+	assert.Equal(t, stream.Remaining(), 12)
+
+	_, err = image.Pointer(0x101c)
+	var addressErr *AddressError
+	assert.Assert(t, errors.As(err, &addressErr))
+	assert.Equal(t, addressErr.Address, uint64(0x101c))
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	_, err = image.Follow(0x1000, 0, 0, 0)
+	assert.ErrorContains(t, err, "Pointer at address 0x2a")
+	_, err = image.CString(0x3000)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+
+	big, _ := NewMemoryImage(4, BigEndian)
+	big.Map(0x10, b(0, 0, 0, 0x20))
+	pointer, err := big.Pointer(0x10)
+	assert.NilError(t, err)
+	assert.Equal(t, pointer, uint64(0x20))
+}
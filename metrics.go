@@ -0,0 +1,84 @@
+package binutils
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrorCategory is a category of errors counted by streams.
+type ErrorCategory int
+
+const (
+	CategoryOther ErrorCategory = iota
+	CategoryEOF
+	CategoryLimitExceeded
+	CategoryMalformedVarInt
+
+	categoryCount
+)
+
+// globalErrorCounts holds the error counts of all streams combined.
+var globalErrorCounts [categoryCount]uint64
+
+// String returns the name of the category.
+func (category ErrorCategory) String() string {
+	switch category {
+	case CategoryEOF:
+		return "eof"
+	case CategoryLimitExceeded:
+		return "limit_exceeded"
+	case CategoryMalformedVarInt:
+		return "malformed_varint"
+	default:
+		return "other"
+	}
+}
+
+// CategoryOf returns the category of the given error.
+func CategoryOf(err error) ErrorCategory {
+	switch {
+	case errors.Is(err, ErrUnexpectedEOF):
+		return CategoryEOF
+	case errors.Is(err, ErrLimitExceeded):
+		return CategoryLimitExceeded
+	case errors.Is(err, ErrMalformedVarInt):
+		return CategoryMalformedVarInt
+	default:
+		return CategoryOther
+	}
+}
+
+// ErrorCount returns the amount of errors of the given category set on all streams.
+func ErrorCount(category ErrorCategory) uint64 {
+	if category < 0 || category >= categoryCount {
+		return 0
+	}
+	return atomic.LoadUint64(&globalErrorCounts[category])
+}
+
+// ResetErrorCounts resets the error counts of all streams combined.
+func ResetErrorCounts() {
+	for i := range globalErrorCounts {
+		atomic.StoreUint64(&globalErrorCounts[i], 0)
+	}
+}
+
+// ErrorCount returns the amount of errors of the given category set on the stream.
+func (stream *Stream) ErrorCount(category ErrorCategory) uint64 {
+	if category < 0 || category >= categoryCount {
+		return 0
+	}
+	return stream.errorCounts[category]
+}
+
+// ResetErrorCounts resets the error counts of the stream.
+func (stream *Stream) ResetErrorCounts() {
+	stream.errorCounts = [categoryCount]uint64{}
+}
+
+// countError increments the stream and global counters for the category of err.
+func (stream *Stream) countError(err error) {
+	category := CategoryOf(err)
+	stream.errorCounts[category]++
+	atomic.AddUint64(&globalErrorCounts[category], 1)
+}
@@ -0,0 +1,33 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestErrorCounts(t *testing.T) {
+	ResetErrorCounts()
+
+	stream := NewStream()
+	stream.SetError(nil)
+	assert.NilError(t, stream.Err())
+	stream.GetInt()
+	stream.SetError(ErrLimitExceeded)
+	stream.SetError(errors.New("something else"))
+
+	assert.Equal(t, stream.ErrorCount(CategoryEOF), uint64(1))
+	assert.Equal(t, stream.ErrorCount(CategoryLimitExceeded), uint64(1))
+	assert.Equal(t, stream.ErrorCount(CategoryMalformedVarInt), uint64(0))
+	assert.Equal(t, stream.ErrorCount(CategoryOther), uint64(1))
+
+	other := NewStream()
+	other.SetBuffer(b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff))
+	other.GetUnsignedVarInt()
+
+	assert.Equal(t, ErrorCount(CategoryEOF), uint64(1))
+	assert.Equal(t, ErrorCount(CategoryMalformedVarInt), uint64(1))
+
+	stream.ResetErrorCounts()
+	assert.Equal(t, stream.ErrorCount(CategoryEOF), uint64(0))
+}
@@ -0,0 +1,53 @@
+package binutils
+
+import (
+	"fmt"
+	"os"
+)
+
+// MappedStream is a stream reading a file mapped into memory, so that large files are parsed with the Get functions
+// without reading them into memory first. Pages are loaded by the operating system as they are read.
+// On platforms without memory mapping, the file is read into memory instead.
+//
+// The buffer of a mapped stream is read-only: like a forked stream, the stream, as well as its sub-streams, copies it
+// into a new buffer before it is first modified, by Put functions as well as by Compact, SetBitsAt or views,
+// which is slow for large files.
+// Reset replaces it with a new buffer. The buffer must not be used after Close.
+type MappedStream struct {
+	*Stream
+	mapped []byte
+}
+
+// OpenMapped opens the file at the path and returns a stream reading its contents.
+func OpenMapped(path string) (*MappedStream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size != int64(int(size)) {
+		return nil, fmt.Errorf("binutils: %s is too large to map", path)
+	}
+	mapped, err := mapFile(file, int(size))
+	if err != nil {
+		return nil, fmt.Errorf("binutils: mapping %s: %w", path, err)
+	}
+	// The stream is marked shared, so that writes never reach the read-only mapping.
+	return &MappedStream{&Stream{Buffer: mapped[:len(mapped):len(mapped)], shared: true}, mapped}, nil
+}
+
+// Close unmaps the file. The stream is reset to an empty buffer.
+func (stream *MappedStream) Close() error {
+	mapped := stream.mapped
+	stream.mapped = nil
+	stream.ResetStream()
+	if mapped == nil {
+		return nil
+	}
+	return unmapFile(mapped)
+}
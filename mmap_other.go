@@ -0,0 +1,20 @@
+//go:build !unix || tinygo
+
+package binutils
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the size bytes of the file into memory, as memory mapping is not supported on this platform.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	buffer := make([]byte, size)
+	_, err := io.ReadFull(file, buffer)
+	return buffer, err
+}
+
+// unmapFile releases memory returned by mapFile.
+func unmapFile(mapped []byte) error {
+	return nil
+}
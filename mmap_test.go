@@ -0,0 +1,91 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMappedStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "region.bin")
+	expected := NewStream()
+	expected.PutLittleInt(7)
+	expected.PutString("steve")
+	assert.NilError(t, os.WriteFile(path, expected.Buffer, 0o644))
+
+	stream, err := OpenMapped(path)
+	assert.NilError(t, err)
+	stream.SetEndianness(LittleEndian)
+	assert.Equal(t, stream.GetInt(), int32(7))
+	assert.Equal(t, stream.GetString(), "steve")
+	assert.NilError(t, stream.Err())
+
+	stream.PutByte(1)
+	assert.Equal(t, len(stream.Buffer), len(expected.Buffer)+1)
+	assert.NilError(t, stream.Close())
+
+	// Writing in place after Reset would fault on the read-only mapping.
+	stream, err = OpenMapped(path)
+	assert.NilError(t, err)
+	stream.Reset()
+	stream.PutByte(1)
+	assert.DeepEqual(t, stream.Buffer, []byte{1})
+	assert.NilError(t, stream.Close())
+
+	stream, err = OpenMapped(path)
+	assert.NilError(t, err)
+	stream.Offset = 2
+	stream.Compact()
+	stream.SetBitsAt(0, 0, 8, 0xff)
+	stream.View().PutUint32At(4, 0)
+	assert.NilError(t, stream.Err())
+	assert.DeepEqual(t, stream.Buffer, []byte{0xff, 0, 5, 's', 0, 0, 0, 0})
+	assert.NilError(t, stream.Close())
+	assert.Equal(t, len(stream.Buffer), 0)
+	assert.NilError(t, stream.Close())
+
+	// Children of the stream copy the mapping before writing too.
+	stream, err = OpenMapped(path)
+	assert.NilError(t, err)
+	sub, err := stream.Sub(4)
+	assert.NilError(t, err)
+	sub.SetBitsAt(0, 0, 8, 0xff)
+	assert.NilError(t, sub.Err())
+	assert.Equal(t, sub.Buffer[0], byte(0xff))
+	for _, piece := range stream.SplitBy([]byte("t")) {
+		piece.PutByte(1)
+		assert.NilError(t, piece.Err())
+	}
+	stream.Offset = 4
+	for record := range stream.Records(PrefixUnsignedVarInt) {
+		record.SetBitsAt(0, 0, 8, 0xff)
+		assert.NilError(t, record.Err())
+	}
+	assert.NilError(t, stream.Err())
+	assert.DeepEqual(t, stream.Buffer, expected.Buffer)
+	assert.NilError(t, stream.Close())
+
+	tagged := NewStream()
+	tagged.PutTagged(1, func(stream *Stream) { stream.PutInt(7) })
+	assert.NilError(t, os.WriteFile(path, tagged.Buffer, 0o644))
+	stream, err = OpenMapped(path)
+	assert.NilError(t, err)
+	_, err = stream.GetTagged(map[uint32]func(*Stream) error{1: func(body *Stream) error {
+		body.SetBitsAt(0, 0, 8, 0xff)
+		return nil
+	}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stream.Buffer, tagged.Buffer)
+	assert.NilError(t, stream.Close())
+
+	empty := filepath.Join(t.TempDir(), "empty.bin")
+	assert.NilError(t, os.WriteFile(empty, nil, 0o644))
+	stream, err = OpenMapped(empty)
+	assert.NilError(t, err)
+	assert.Equal(t, stream.Remaining(), 0)
+	assert.NilError(t, stream.Close())
+
+	_, err = OpenMapped(filepath.Join(t.TempDir(), "missing.bin"))
+	assert.Assert(t, os.IsNotExist(err))
+}
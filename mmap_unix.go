@@ -0,0 +1,21 @@
+//go:build unix && !tinygo
+
+package binutils
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the size bytes of the file into memory, read-only.
+func mapFile(file *os.File, size int) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapFile unmaps memory returned by mapFile.
+func unmapFile(mapped []byte) error {
+	return syscall.Munmap(mapped)
+}
@@ -0,0 +1,215 @@
+package nbt
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/irmine/binutils"
+)
+
+// decoder reads tags from a stream.
+type decoder struct {
+	stream   *binutils.Stream
+	encoding Encoding
+}
+
+// length reads the length of a list or array with elements of at least size bytes.
+// Lengths exceeding the bytes left in the buffer are set as error on the stream.
+func (d *decoder) length(size int) int {
+	stream := d.stream
+	offset := stream.Offset
+	length := stream.GetPrefix(d.encoding.lengthPrefix())
+	if stream.Err() == nil && length > (len(stream.Buffer)-stream.Offset)/size {
+		stream.SetError(&binutils.StreamError{Op: "nbt.Read", Offset: offset, Err: binutils.ErrUnexpectedEOF})
+		return 0
+	}
+	return length
+}
+
+// numberSize returns the minimum encoded size of ints or longs of the given fixed size,
+// which is a single byte if they are encoded as varints.
+func (d *decoder) numberSize(size int) int {
+	if d.encoding.varints {
+		return 1
+	}
+	return size
+}
+
+// payload reads the payload of a tag of the given type.
+func (d *decoder) payload(tagType byte) interface{} {
+	stream := d.stream
+	switch tagType {
+	case TagByte:
+		return int8(stream.GetByte())
+	case TagShort:
+		return stream.GetShort()
+	case TagInt:
+		if d.encoding.varints {
+			return stream.GetVarInt()
+		}
+		return stream.GetInt()
+	case TagLong:
+		if d.encoding.varints {
+			return stream.GetVarLong()
+		}
+		return stream.GetLong()
+	case TagFloat:
+		return stream.GetFloat()
+	case TagDouble:
+		return stream.GetDouble()
+	case TagByteArray:
+		return append([]byte{}, stream.Get(d.length(1))...)
+	case TagString:
+		return stream.GetStringWithPrefix(d.encoding.stringPrefix())
+	case TagList:
+		offset := stream.Offset
+		elemType := stream.GetByte()
+		length := d.length(1)
+		if elemType == TagEnd && length > 0 {
+			stream.SetError(&binutils.StreamError{Op: "nbt.Read", Offset: offset, Err: ErrInvalidTag})
+		}
+		if stream.Err() != nil || !stream.Enter() {
+			return []interface{}{}
+		}
+		defer stream.Leave()
+		elements := make([]interface{}, length)
+		for i := 0; i < length && stream.Err() == nil; i++ {
+			elements[i] = d.payload(elemType)
+		}
+		return elements
+	case TagCompound:
+		tags := make(map[string]interface{})
+		if !stream.Enter() {
+			return tags
+		}
+		defer stream.Leave()
+		for {
+			tagType := stream.GetByte()
+			if tagType == TagEnd || stream.Err() != nil {
+				return tags
+			}
+			name := stream.GetStringWithPrefix(d.encoding.stringPrefix())
+			tags[name] = d.payload(tagType)
+		}
+	case TagIntArray:
+		ints := make([]int32, d.length(d.numberSize(4)))
+		for i := 0; i < len(ints) && stream.Err() == nil; i++ {
+			ints[i] = d.payload(TagInt).(int32)
+		}
+		return ints
+	case TagLongArray:
+		longs := make([]int64, d.length(d.numberSize(8)))
+		for i := 0; i < len(longs) && stream.Err() == nil; i++ {
+			longs[i] = d.payload(TagLong).(int64)
+		}
+		return longs
+	default:
+		stream.SetError(&binutils.StreamError{Op: "nbt.Read", Offset: stream.Offset - 1, Err: ErrInvalidTag})
+		return nil
+	}
+}
+
+// assign assigns a decoded tag to v, which must be a pointer.
+func assign(v interface{}, tag interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("nbt: cannot read into %T, expected a pointer", v)
+	}
+	return assignValue(value.Elem(), tag)
+}
+
+// assignValue assigns a decoded tag to the value, converting it to the type of the value.
+func assignValue(value reflect.Value, tag interface{}) error {
+	if b, ok := tag.(byte); ok {
+		// Elements of byte arrays are bytes of TAG_Byte.
+		tag = int8(b)
+	}
+	tagValue := reflect.ValueOf(tag)
+	switch value.Kind() {
+	case reflect.Interface:
+		if tagValue.Type().AssignableTo(value.Type()) {
+			value.Set(tagValue)
+			return nil
+		}
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return assignValue(value.Elem(), tag)
+	case reflect.Bool:
+		if tagValue.Kind() == reflect.Int8 {
+			value.SetBool(tagValue.Int() != 0)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tagValue.Kind() >= reflect.Int8 && tagValue.Kind() <= reflect.Int64 && !value.OverflowInt(tagValue.Int()) {
+			value.SetInt(tagValue.Int())
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if tagValue.Kind() >= reflect.Int8 && tagValue.Kind() <= reflect.Int64 {
+			// Unsigned values are written as the signed tag of the same size.
+			u := uint64(tagValue.Int()) & (1<<(8*tagValue.Type().Size()) - 1)
+			if !value.OverflowUint(u) {
+				value.SetUint(u)
+				return nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if tagValue.Kind() == reflect.Float32 || tagValue.Kind() == reflect.Float64 {
+			value.SetFloat(tagValue.Float())
+			return nil
+		}
+	case reflect.String:
+		if tagValue.Kind() == reflect.String {
+			value.SetString(tagValue.String())
+			return nil
+		}
+	case reflect.Slice:
+		if tagValue.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(value.Type(), tagValue.Len(), tagValue.Len())
+			for i := 0; i < tagValue.Len(); i++ {
+				if err := assignValue(slice.Index(i), tagValue.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			value.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		if tagValue.Kind() == reflect.Slice && tagValue.Len() == value.Len() {
+			for i := 0; i < tagValue.Len(); i++ {
+				if err := assignValue(value.Index(i), tagValue.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if tags, ok := tag.(map[string]interface{}); ok && value.Type().Key().Kind() == reflect.String {
+			m := reflect.MakeMapWithSize(value.Type(), len(tags))
+			for name, tag := range tags {
+				element := reflect.New(value.Type().Elem()).Elem()
+				if err := assignValue(element, tag); err != nil {
+					return err
+				}
+				m.SetMapIndex(reflect.ValueOf(name).Convert(value.Type().Key()), element)
+			}
+			value.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if tags, ok := tag.(map[string]interface{}); ok {
+			for i := 0; i < value.NumField(); i++ {
+				name, _, ok := fieldName(value.Type().Field(i))
+				if tag, present := tags[name]; ok && present {
+					if err := assignValue(value.Field(i), tag); err != nil {
+						return fmt.Errorf("nbt: field %s: %v", value.Type().Field(i).Name, err)
+					}
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("nbt: cannot read %T into %s", tag, value.Type())
+}
@@ -0,0 +1,253 @@
+package nbt
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/irmine/binutils"
+)
+
+// list is a list tag with the type of its elements.
+type list struct {
+	elemType byte
+	elements []interface{}
+}
+
+// compound is a compound tag with its tags in order.
+type compound []namedTag
+
+// namedTag is a tag of a compound.
+type namedTag struct {
+	name  string
+	value interface{}
+}
+
+// tagType returns the type of a tag converted by toTag.
+func tagType(tag interface{}) byte {
+	switch tag.(type) {
+	case int8:
+		return TagByte
+	case int16:
+		return TagShort
+	case int32:
+		return TagInt
+	case int64:
+		return TagLong
+	case float32:
+		return TagFloat
+	case float64:
+		return TagDouble
+	case []byte:
+		return TagByteArray
+	case string:
+		return TagString
+	case list:
+		return TagList
+	case compound:
+		return TagCompound
+	case []int32:
+		return TagIntArray
+	default:
+		return TagLongArray
+	}
+}
+
+// toTag converts v to the tag it is written as.
+func toTag(v interface{}) (interface{}, error) {
+	return valueToTag(reflect.ValueOf(v))
+}
+
+// valueToTag converts the value to the tag it is written as.
+func valueToTag(value reflect.Value) (interface{}, error) {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			return nil, fmt.Errorf("nbt: cannot write nil %s", value.Type())
+		}
+		return valueToTag(value.Elem())
+	case reflect.Bool:
+		if value.Bool() {
+			return int8(1), nil
+		}
+		return int8(0), nil
+	case reflect.Int8:
+		return int8(value.Int()), nil
+	case reflect.Uint8:
+		return int8(value.Uint()), nil
+	case reflect.Int16:
+		return int16(value.Int()), nil
+	case reflect.Uint16:
+		return int16(value.Uint()), nil
+	case reflect.Int32:
+		return int32(value.Int()), nil
+	case reflect.Uint32:
+		return int32(value.Uint()), nil
+	case reflect.Int, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint64:
+		return int64(value.Uint()), nil
+	case reflect.Float32:
+		return float32(value.Float()), nil
+	case reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Slice, reflect.Array:
+		return sliceToTag(value)
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("nbt: cannot write map with %s keys", value.Type().Key())
+		}
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		tags := make(compound, 0, len(keys))
+		for _, key := range keys {
+			tag, err := valueToTag(value.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, namedTag{key.String(), tag})
+		}
+		return tags, nil
+	case reflect.Struct:
+		return structToTag(value)
+	default:
+		return nil, fmt.Errorf("nbt: cannot write values of type %s", value.Type())
+	}
+}
+
+// sliceToTag converts a slice or array to an array tag, or a list tag.
+func sliceToTag(value reflect.Value) (interface{}, error) {
+	switch value.Type().Elem().Kind() {
+	case reflect.Int8, reflect.Uint8:
+		bytes := make([]byte, value.Len())
+		for i := range bytes {
+			tag, _ := valueToTag(value.Index(i))
+			bytes[i] = byte(tag.(int8))
+		}
+		return bytes, nil
+	case reflect.Int32:
+		ints := make([]int32, value.Len())
+		for i := range ints {
+			ints[i] = int32(value.Index(i).Int())
+		}
+		return ints, nil
+	case reflect.Int64:
+		longs := make([]int64, value.Len())
+		for i := range longs {
+			longs[i] = value.Index(i).Int()
+		}
+		return longs, nil
+	}
+	tags := list{TagEnd, make([]interface{}, value.Len())}
+	for i := range tags.elements {
+		tag, err := valueToTag(value.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			tags.elemType = tagType(tag)
+		} else if tagType(tag) != tags.elemType {
+			return nil, fmt.Errorf("nbt: list elements of %s have different tag types", value.Type())
+		}
+		tags.elements[i] = tag
+	}
+	return tags, nil
+}
+
+// structToTag converts a struct to a compound tag.
+func structToTag(value reflect.Value) (interface{}, error) {
+	tags := make(compound, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		name, omitEmpty, ok := fieldName(value.Type().Field(i))
+		if !ok || omitEmpty && value.Field(i).IsZero() {
+			continue
+		}
+		tag, err := valueToTag(value.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, namedTag{name, tag})
+	}
+	return tags, nil
+}
+
+// fieldName returns the tag name of the struct field, whether it is omitted if empty,
+// and whether the field is written at all.
+func fieldName(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+	tag := field.Tag.Get("nbt")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, options == "omitempty", true
+}
+
+// encoder writes tags on a stream.
+type encoder struct {
+	stream   *binutils.Stream
+	encoding Encoding
+}
+
+// payload writes the payload of a tag converted by toTag.
+func (e *encoder) payload(tag interface{}) {
+	stream := e.stream
+	switch tag := tag.(type) {
+	case int8:
+		stream.PutByte(byte(tag))
+	case int16:
+		stream.PutShort(tag)
+	case int32:
+		if e.encoding.varints {
+			stream.PutVarInt(tag)
+		} else {
+			stream.PutInt(tag)
+		}
+	case int64:
+		if e.encoding.varints {
+			stream.PutVarLong(tag)
+		} else {
+			stream.PutLong(tag)
+		}
+	case float32:
+		stream.PutFloat(tag)
+	case float64:
+		stream.PutDouble(tag)
+	case []byte:
+		stream.PutPrefix(e.encoding.lengthPrefix(), len(tag))
+		stream.PutBytes(tag)
+	case string:
+		stream.PutStringWithPrefix(tag, e.encoding.stringPrefix())
+	case list:
+		stream.PutByte(tag.elemType)
+		stream.PutPrefix(e.encoding.lengthPrefix(), len(tag.elements))
+		for _, element := range tag.elements {
+			e.payload(element)
+		}
+	case compound:
+		for _, named := range tag {
+			stream.PutByte(tagType(named.value))
+			stream.PutStringWithPrefix(named.name, e.encoding.stringPrefix())
+			e.payload(named.value)
+		}
+		stream.PutByte(TagEnd)
+	case []int32:
+		stream.PutPrefix(e.encoding.lengthPrefix(), len(tag))
+		for _, v := range tag {
+			e.payload(v)
+		}
+	case []int64:
+		stream.PutPrefix(e.encoding.lengthPrefix(), len(tag))
+		for _, v := range tag {
+			e.payload(v)
+		}
+	}
+}
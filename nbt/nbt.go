@@ -0,0 +1,126 @@
+// Package nbt reads and writes NBT (Named Binary Tag) compounds on binutils streams.
+//
+// Compounds are read into map[string]interface{} or into structs, and written from maps or structs.
+// Tags are mapped to Go values as follows:
+//
+//	TAG_Byte        int8
+//	TAG_Short       int16
+//	TAG_Int         int32
+//	TAG_Long        int64
+//	TAG_Float       float32
+//	TAG_Double      float64
+//	TAG_Byte_Array  []byte
+//	TAG_String      string
+//	TAG_List        []interface{}
+//	TAG_Compound    map[string]interface{}
+//	TAG_Int_Array   []int32
+//	TAG_Long_Array  []int64
+//
+// When writing, bool is written as TAG_Byte, int and uint as TAG_Long, and other slices and arrays as TAG_List.
+// Struct fields are named by their `nbt` struct tag, or by their field name. Fields tagged `nbt:"-"` are skipped,
+// and fields tagged with the omitempty option, such as `nbt:"name,omitempty"`, are skipped if they are zero.
+package nbt
+
+import (
+	"errors"
+
+	"github.com/irmine/binutils"
+)
+
+// Tag types.
+const (
+	TagEnd byte = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+// ErrInvalidTag is used when a tag type is unknown, or a root tag is not a compound.
+var ErrInvalidTag = errors.New("invalid tag type")
+
+// Encoding is a flavor of NBT.
+type Encoding struct {
+	endian  binutils.EndianType
+	varints bool
+}
+
+var (
+	// BigEndian is the encoding used by Java Edition.
+	BigEndian = Encoding{binutils.BigEndian, false}
+	// LittleEndian is the encoding used by Bedrock Edition for files.
+	LittleEndian = Encoding{binutils.LittleEndian, false}
+	// NetworkLittleEndian is the encoding used by the Bedrock Edition network protocol.
+	// Ints, longs and lengths are written as varints, and strings are prefixed with an unsigned varint.
+	NetworkLittleEndian = Encoding{binutils.LittleEndian, true}
+)
+
+// stringPrefix returns the prefix type of strings.
+func (encoding Encoding) stringPrefix() binutils.PrefixType {
+	switch {
+	case encoding.varints:
+		return binutils.PrefixUnsignedVarInt
+	case encoding.endian == binutils.LittleEndian:
+		return binutils.PrefixLittleUnsignedShort
+	default:
+		return binutils.PrefixUnsignedShort
+	}
+}
+
+// lengthPrefix returns the prefix type of lists and arrays.
+func (encoding Encoding) lengthPrefix() binutils.PrefixType {
+	switch {
+	case encoding.varints:
+		return binutils.PrefixVarInt
+	case encoding.endian == binutils.LittleEndian:
+		return binutils.PrefixLittleInt
+	default:
+		return binutils.PrefixInt
+	}
+}
+
+// Write writes v, which must be a map with string keys or a struct, as root compound with the given name.
+func Write(stream *binutils.Stream, encoding Encoding, name string, v interface{}) error {
+	root, err := toTag(v)
+	if err != nil {
+		return err
+	}
+	if _, ok := root.(compound); !ok {
+		return errors.New("nbt: root tag must be a compound")
+	}
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(encoding.endian)
+
+	e := &encoder{stream, encoding}
+	stream.PutByte(TagCompound)
+	stream.PutStringWithPrefix(name, encoding.stringPrefix())
+	e.payload(root)
+	return stream.Err()
+}
+
+// Read reads a root compound into v, which must be a pointer to a map with string keys, a struct or an interface{},
+// and returns the name of the compound. Reading errors are set on the stream and returned.
+func Read(stream *binutils.Stream, encoding Encoding, v interface{}) (string, error) {
+	defer stream.SetEndianness(stream.GetEndianness())
+	stream.SetEndianness(encoding.endian)
+
+	d := &decoder{stream, encoding}
+	offset := stream.Offset
+	if tagType := stream.GetByte(); tagType != TagCompound && stream.Err() == nil {
+		stream.SetError(&binutils.StreamError{Op: "nbt.Read", Offset: offset, Err: ErrInvalidTag})
+	}
+	name := stream.GetStringWithPrefix(encoding.stringPrefix())
+	root := d.payload(TagCompound)
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+	return name, assign(v, root)
+}
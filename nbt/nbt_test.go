@@ -0,0 +1,132 @@
+package nbt
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+
+	"github.com/irmine/binutils"
+)
+
+type testLevel struct {
+	Name      string `nbt:"LevelName"`
+	Seed      int64  `nbt:"RandomSeed"`
+	Hardcore  bool   `nbt:"hardcore"`
+	Spawn     [3]int32
+	Heights   []int64
+	Biomes    []byte
+	Players   []testPlayer
+	Health    float32
+	Weather   float64 `nbt:",omitempty"`
+	Difficult uint8
+	Skipped   string `nbt:"-"`
+}
+
+type testPlayer struct {
+	Name  string
+	Level int16
+}
+
+func TestHelloWorld(t *testing.T) {
+	stream := binutils.NewStream()
+	assert.NilError(t, Write(stream, BigEndian, "hello world", map[string]string{"name": "Bananrama"}))
+	expected := append(append([]byte{0x0a, 0x00, 0x0b}, "hello world"...), 0x08, 0x00, 0x04)
+	expected = append(append(append(expected, "name"...), 0x00, 0x09), "Bananrama"...)
+	assert.DeepEqual(t, stream.Buffer, append(expected, 0x00))
+
+	var compound map[string]interface{}
+	name, err := Read(stream, BigEndian, &compound)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "hello world")
+	assert.DeepEqual(t, compound, map[string]interface{}{"name": "Bananrama"})
+}
+
+func TestRoundTrip(t *testing.T) {
+	level := testLevel{
+		Name:      "world",
+		Seed:      -42,
+		Hardcore:  true,
+		Spawn:     [3]int32{1, 64, -1},
+		Heights:   []int64{1, 2},
+		Biomes:    []byte{0xff, 1},
+		Players:   []testPlayer{{"steve", 30}, {"alex", 5}},
+		Health:    19.5,
+		Difficult: 200,
+	}
+	for _, encoding := range []Encoding{BigEndian, LittleEndian, NetworkLittleEndian} {
+		stream := binutils.NewStream()
+		assert.NilError(t, Write(stream, encoding, "", level))
+
+		var decoded testLevel
+		name, err := Read(stream, encoding, &decoded)
+		assert.NilError(t, err)
+		assert.Equal(t, name, "")
+		assert.DeepEqual(t, decoded, level)
+		assert.Equal(t, stream.Offset, len(stream.Buffer))
+		assert.Equal(t, stream.GetEndianness(), binutils.BigEndian)
+	}
+}
+
+func TestRoundTripArrays(t *testing.T) {
+	// Varint arrays at the end of the buffer are shorter than their elements would be as fixed size integers.
+	arrays := map[string]interface{}{"a": []int32{1, 2, 3}, "b": []int64{-1, 0, 1}}
+	for _, encoding := range []Encoding{BigEndian, LittleEndian, NetworkLittleEndian} {
+		for name, array := range arrays {
+			stream := binutils.NewStream()
+			assert.NilError(t, Write(stream, encoding, "", map[string]interface{}{name: array}))
+
+			var decoded map[string]interface{}
+			_, err := Read(stream, encoding, &decoded)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, decoded, map[string]interface{}{name: array})
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	stream := binutils.NewStream()
+	assert.NilError(t, Write(stream, NetworkLittleEndian, "root", map[string]interface{}{
+		"count": int32(300),
+		"list":  []interface{}{"a", "b"},
+		"empty": []interface{}{},
+		"ints":  []int32{-1},
+	}))
+	assert.DeepEqual(t, stream.Buffer[:8], []byte{0x0a, 0x04, 'r', 'o', 'o', 't', 0x03, 0x05})
+
+	var compound interface{}
+	_, err := Read(stream, NetworkLittleEndian, &compound)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, compound, map[string]interface{}{
+		"count": int32(300),
+		"list":  []interface{}{"a", "b"},
+		"empty": []interface{}{},
+		"ints":  []int32{-1},
+	})
+}
+
+func TestWriteErrors(t *testing.T) {
+	stream := binutils.NewStream()
+	assert.ErrorContains(t, Write(stream, BigEndian, "", 1), "root tag must be a compound")
+	assert.ErrorContains(t, Write(stream, BigEndian, "", map[string]interface{}{"list": []interface{}{1, "a"}}), "different tag types")
+	assert.ErrorContains(t, Write(stream, BigEndian, "", map[int]int{}), "cannot write map with int keys")
+}
+
+func TestReadErrors(t *testing.T) {
+	var compound map[string]interface{}
+	_, err := Read(&binutils.Stream{Buffer: []byte{0x08, 0x00, 0x00}}, BigEndian, &compound)
+	assert.Assert(t, errors.Is(err, ErrInvalidTag))
+
+	_, err = Read(&binutils.Stream{Buffer: []byte{0x0a, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x7f, 0xff, 0xff, 0xff}}, BigEndian, &compound)
+	assert.Assert(t, errors.Is(err, binutils.ErrUnexpectedEOF))
+
+	nested := &binutils.Stream{Buffer: []byte{0x0a, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00}}
+	nested.SetMaxDepth(1)
+	_, err = Read(nested, BigEndian, &compound)
+	assert.Assert(t, errors.Is(err, binutils.ErrLimitExceeded))
+
+	var level testLevel
+	stream := binutils.NewStream()
+	assert.NilError(t, Write(stream, BigEndian, "", map[string]interface{}{"LevelName": int32(1)}))
+	_, err = Read(stream, BigEndian, &level)
+	assert.ErrorContains(t, err, "field Name: nbt: cannot read int32 into string")
+}
@@ -0,0 +1,29 @@
+package binutils
+
+import "unsafe"
+
+// GetBytesNoCopy reads n bytes, returning a slice aliasing the buffer of the stream instead of a copy.
+// Its capacity is limited to n, so that appending to it cannot overwrite the bytes after it.
+// The bytes change if the buffer is written to in place, such as after Reset, Compact or returning the stream to a pool.
+func (stream *Stream) GetBytesNoCopy(n int) []byte {
+	if !stream.check("GetBytesNoCopy", n) {
+		return nil
+	}
+	b := Read(&stream.Buffer, &stream.Offset, n)
+	return b[:n:n]
+}
+
+// GetStringNoCopy reads a string like GetString, but returns a string aliasing the buffer of the stream
+// instead of copying its bytes, so that reading it does not allocate.
+//
+// Go strings are assumed to never change, so the string may only be used as long as the bytes it aliases are not
+// modified: the stream must not be reset, compacted, rolled back and written to, or returned to a pool while the string
+// is in use, and the caller must not modify the buffer it passed to the stream. Strings kept beyond that, such as map keys,
+// must be copied with strings.Clone first.
+func (stream *Stream) GetStringNoCopy() string {
+	b := stream.getPrefixed("GetStringNoCopy", stream.stringPrefix, stream.maxStringLength)
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(&b[0], len(b))
+}
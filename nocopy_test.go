@@ -0,0 +1,36 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestGetNoCopy(t *testing.T) {
+	stream := NewStream()
+	stream.PutString("hello")
+	stream.PutString("")
+	stream.PutBytes(b(1, 2, 3))
+
+	s := stream.GetStringNoCopy()
+	assert.Equal(t, s, "hello")
+	assert.Equal(t, stream.GetStringNoCopy(), "")
+	bytes := stream.GetBytesNoCopy(2)
+	assert.DeepEqual(t, bytes, b(1, 2))
+	assert.Equal(t, cap(bytes), 2)
+
+	stream.Buffer[1] = 'j'
+	assert.Equal(t, s, "jello")
+
+	stream.GetBytesNoCopy(2)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream = NewStream()
+	stream.PutString("hello")
+	allocs := testing.AllocsPerRun(100, func() {
+		stream.Offset = 0
+		stream.GetStringNoCopy()
+	})
+	assert.Equal(t, allocs, float64(0))
+}
@@ -0,0 +1,41 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+// Overlay decodes the next size bytes of the stream into each of the views, like the members of a C union,
+// and advances the offset past them. Views are pointers to structs, decoded as described for Marshal,
+// or Serializable types. Every view is decoded from the start of the same bytes, and reading past them is an error.
+// The first error is set on the stream and returned.
+func (stream *Stream) Overlay(size int, views ...interface{}) error {
+	offset := stream.Offset
+	if !stream.check("Overlay", size) {
+		return stream.err
+	}
+	bytes := stream.Buffer[offset : offset+size]
+	for _, view := range views {
+		child := stream.child(bytes)
+		var err error
+		if serializable, ok := view.(Serializable); ok {
+			child.decode("Overlay", serializable)
+		} else {
+			err = child.GetStruct(view)
+		}
+		if err == nil {
+			err = child.err
+		}
+		if err != nil {
+			if streamErr, ok := err.(*StreamError); ok {
+				err = &StreamError{streamErr.Op, offset + streamErr.Offset, streamErr.Err}
+			}
+			stream.SetError(err)
+			return err
+		}
+	}
+	stream.Offset += size
+	return nil
+}
+
+// Overlay decodes the buffer into each of the views as described for Stream.Overlay.
+func Overlay(buffer []byte, views ...interface{}) error {
+	return (&Stream{Buffer: buffer}).Overlay(len(buffer), views...)
+}
@@ -0,0 +1,38 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	type asInts struct {
+		A, B uint16
+	}
+	type asFloat struct {
+		F float32
+	}
+	stream := &Stream{Buffer: b(0x3f, 0xc0, 0x00, 0x00, 0xff)}
+	var ints asInts
+	var float asFloat
+	var packet testPacket
+	assert.NilError(t, stream.Overlay(4, &ints, &float, &packet))
+	assert.Equal(t, ints, asInts{0x3fc0, 0})
+	assert.Equal(t, float.F, float32(1.5))
+	assert.Equal(t, packet.Value, int32(-32))
+	assert.Equal(t, stream.Offset, 4)
+
+	type tooLarge struct {
+		A, B uint16
+	}
+	err := stream.Overlay(1, &tooLarge{})
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	assert.Equal(t, err.(*StreamError).Offset, 4)
+	assert.Equal(t, stream.Offset, 4)
+
+	assert.NilError(t, Overlay(b(1, 2, 3, 4), &ints))
+	assert.Equal(t, ints, asInts{0x0102, 0x0304})
+}
@@ -0,0 +1,168 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchField sets the field at the path of v to value, and replaces the encoding of the field in the buffer,
+// without encoding the other fields again. v must be a pointer to the struct decoded from the buffer by
+// UnmarshalWithOffsets, and offsets the ranges it returned. Fields declaring sizeof or crc32 are recomputed,
+// as described for Marshal.
+//
+// The buffer is modified in place if the encodings keep their size. PatchField returns the patched buffer
+// and the new ranges of the fields, and v holds the values of the patched buffer.
+func PatchField(buffer []byte, v interface{}, offsets map[string]Range, path string, value interface{}) ([]byte, map[string]Range, error) {
+	root := reflect.ValueOf(v)
+	if root.Kind() != reflect.Ptr || root.IsNil() || root.Elem().Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("binutils: cannot patch %T, expected a pointer to a struct", v)
+	}
+	r, ok := offsets[path]
+	if !ok || r.Start < 0 || r.End > len(buffer) || r.Start > r.End {
+		return nil, nil, fmt.Errorf("binutils: no range for field %s", path)
+	}
+	field, tag, err := resolvePath(root.Elem(), path)
+	if err != nil {
+		return nil, nil, err
+	}
+	newValue, ok := convertValue(value, field.Type())
+	if !ok {
+		return nil, nil, fmt.Errorf("binutils: cannot set field %s of type %s to %T", path, field.Type(), value)
+	}
+	field.Set(newValue)
+
+	m := &marshaler{stream: NewStream()}
+	if err := m.encode(field, tag, path); err != nil {
+		return nil, nil, err
+	}
+	buffer, fields, err := updateDependents(splice(buffer, r, m.stream.Buffer), root.Elem(), MarshalOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return buffer, (&marshaler{fields: fields}).ranges(), nil
+}
+
+// convertValue converts v to the type t, and returns whether it could be converted.
+// Numbers are not converted to strings.
+func convertValue(v interface{}, t reflect.Type) (reflect.Value, bool) {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() || !value.Type().ConvertibleTo(t) || (t.Kind() == reflect.String) != (value.Kind() == reflect.String) {
+		return value, false
+	}
+	return value.Convert(t), true
+}
+
+// resolvePath returns the field at the path in the struct, and the tag it is encoded with.
+func resolvePath(value reflect.Value, path string) (reflect.Value, fieldTag, error) {
+	var tag fieldTag
+	for _, part := range strings.Split(path, ".") {
+		name, indices, _ := strings.Cut(part, "[")
+		if value.Kind() != reflect.Struct {
+			return value, tag, fmt.Errorf("binutils: invalid field path %s", path)
+		}
+		fields, err := structFields(value.Type())
+		if err != nil {
+			return value, tag, err
+		}
+		i := findField(fields, name)
+		if i < 0 {
+			return value, tag, fmt.Errorf("binutils: unknown field %s in path %s", name, path)
+		}
+		value, tag = value.FieldByIndex(fields[i].index), fields[i].tag
+		for indices != "" {
+			index, rest, _ := strings.Cut(indices, "]")
+			n, err := strconv.Atoi(index)
+			if err != nil || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || n < 0 || n >= value.Len() {
+				return value, tag, fmt.Errorf("binutils: invalid index %s in path %s", index, path)
+			}
+			value, indices = value.Index(n), strings.TrimPrefix(rest, "[")
+		}
+	}
+	return value, tag, nil
+}
+
+// splice returns the buffer with the bytes in the range replaced with the encoded bytes.
+// The buffer is modified in place if the encoded bytes have the length of the range.
+func splice(buffer []byte, r Range, encoded []byte) []byte {
+	if len(encoded) == r.Len() {
+		copy(buffer[r.Start:], encoded)
+		return buffer
+	}
+	spliced := make([]byte, 0, len(buffer)-r.Len()+len(encoded))
+	spliced = append(spliced, buffer[:r.Start]...)
+	spliced = append(spliced, encoded...)
+	return append(spliced, buffer[r.End:]...)
+}
+
+// trackedDecode decodes the buffer into the value with the options, and returns the ranges of its fields.
+func trackedDecode(buffer []byte, value reflect.Value, options MarshalOptions) ([]fieldRange, error) {
+	stream := &Stream{Buffer: buffer}
+	m := &marshaler{stream: stream, options: options, track: true}
+	if err := m.decode(value, fieldTag{}, ""); err != nil {
+		return nil, err
+	}
+	return m.fields, stream.err
+}
+
+// updateDependents recomputes the fields declaring sizeof or crc32 in the encoding of the value in the buffer
+// encoded with the options.
+// The buffer is decoded into the value, which must be addressable, and the patched buffer is returned with
+// the ranges of the fields. Sizes are computed before CRCs, and nested fields before the fields containing them.
+func updateDependents(buffer []byte, value reflect.Value, options MarshalOptions) ([]byte, []fieldRange, error) {
+	fields, err := trackedDecode(buffer, value, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, crc := range []bool{false, true} {
+		for i := len(fields) - 1; i >= 0; i-- {
+			field := fields[i]
+			target := field.tag.sizeOf
+			if crc {
+				target = field.tag.crcOf
+			}
+			if target == "" {
+				continue
+			}
+			parent := ""
+			if dot := strings.LastIndex(field.path, "."); dot >= 0 {
+				parent = field.path[:dot]
+			}
+			targetPath := (&marshaler{track: true}).fieldPath(parent, target)
+			var targetRange Range
+			for _, f := range fields {
+				if f.path == targetPath {
+					targetRange = f.Range
+				}
+			}
+			n := uint64(targetRange.Len())
+			if crc {
+				n = uint64(crc32.ChecksumIEEE(buffer[targetRange.Start:targetRange.End]))
+			}
+			if field.value.Kind() >= reflect.Uint && !field.value.OverflowUint(n) {
+				field.value.SetUint(n)
+			} else if field.value.Kind() < reflect.Uint && !field.value.OverflowInt(int64(n)) {
+				field.value.SetInt(int64(n))
+			} else {
+				return nil, nil, fmt.Errorf("binutils: value %d of field %s overflows %s", n, field.path, field.value.Type())
+			}
+			m := &marshaler{stream: NewStream(), options: options}
+			if err := m.encode(field.value, field.tag, field.path); err != nil {
+				return nil, nil, err
+			}
+			if bytes.Equal(m.stream.Buffer, buffer[field.Start:field.End]) {
+				continue
+			}
+			buffer = splice(buffer, field.Range, m.stream.Buffer)
+			if fields, err = trackedDecode(buffer, value, options); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return buffer, fields, nil
+}
@@ -0,0 +1,77 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"hash/crc32"
+	"testing"
+)
+
+type testSave struct {
+	Length uint16 `bin:",sizeof=Body"`
+	Body   testSaveBody
+	CRC    uint32 `bin:",crc32=Body"`
+}
+
+type testSaveBody struct {
+	Name  string
+	Level int32
+	Items []string
+}
+
+func TestMarshalDependents(t *testing.T) {
+	buffer, err := Marshal(testSave{Body: testSaveBody{Name: "steve", Level: 3}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer[:2], b(0, 11))
+	offset := 13
+	assert.Equal(t, ReadUnsignedInt(&buffer, &offset), crc32.ChecksumIEEE(buffer[2:13]))
+}
+
+func TestPatchField(t *testing.T) {
+	buffer, err := Marshal(testSave{Body: testSaveBody{Name: "steve", Level: 3}})
+	assert.NilError(t, err)
+	var save testSave
+	offsets, err := UnmarshalWithOffsets(buffer, &save)
+	assert.NilError(t, err)
+
+	patched, offsets, err := PatchField(buffer, &save, offsets, "Body.Level", 4)
+	assert.NilError(t, err)
+	assert.Equal(t, &patched[0], &buffer[0])
+	assert.Equal(t, save.Body.Level, int32(4))
+
+	patched, offsets, err = PatchField(patched, &save, offsets, "Body.Name", "alexander")
+	assert.NilError(t, err)
+	assert.Equal(t, save.Length, uint16(15))
+	assert.Equal(t, offsets["CRC"], Range{17, 21})
+
+	patched, _, err = PatchField(patched, &save, offsets, "Body.Items", []string{"sword"})
+	assert.NilError(t, err)
+	expected, err := Marshal(testSave{Body: testSaveBody{Name: "alexander", Level: 4, Items: []string{"sword"}}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, patched, expected)
+	assert.Equal(t, save.CRC, crc32.ChecksumIEEE(expected[2:23]))
+}
+
+func TestPatchFieldErrors(t *testing.T) {
+	buffer, err := Marshal(testSave{Body: testSaveBody{Items: []string{"a"}}})
+	assert.NilError(t, err)
+	var save testSave
+	offsets, err := UnmarshalWithOffsets(buffer, &save)
+	assert.NilError(t, err)
+
+	_, _, err = PatchField(buffer, &save, offsets, "Body.Missing", 1)
+	assert.ErrorContains(t, err, "no range for field Body.Missing")
+	_, _, err = PatchField(buffer, &save, offsets, "Body.Name", 1)
+	assert.ErrorContains(t, err, "cannot set field Body.Name of type string to int")
+
+	offsets["Body.Items[1]"] = Range{}
+	_, _, err = PatchField(buffer, &save, offsets, "Body.Items[1]", "b")
+	assert.ErrorContains(t, err, "invalid index 1 in path Body.Items[1]")
+
+	type invalid struct {
+		Length uint8 `bin:",sizeof=Body"`
+	}
+	_, err = Marshal(invalid{})
+	assert.ErrorContains(t, err, "unknown field Body")
+}
@@ -0,0 +1,105 @@
+package binutils
+
+// Peek returns the next n bytes without advancing the offset.
+// The returned slice shares the buffer of the stream.
+func (stream *Stream) Peek(n int) []byte {
+	if !stream.check("Peek", n) {
+		return nil
+	}
+	return stream.Buffer[stream.Offset : stream.Offset+n]
+}
+
+// Skip advances the offset past the next n bytes.
+func (stream *Stream) Skip(n int) {
+	if stream.check("Skip", n) {
+		stream.Offset += n
+	}
+}
+
+// PeekByte reads a byte like GetByte, without advancing the offset.
+func (stream *Stream) PeekByte() byte {
+	offset := stream.Offset
+	v := stream.GetByte()
+	stream.Offset = offset
+	return v
+}
+
+// PeekShort reads a int16 like GetShort, without advancing the offset.
+func (stream *Stream) PeekShort() int16 {
+	offset := stream.Offset
+	v := stream.GetShort()
+	stream.Offset = offset
+	return v
+}
+
+// PeekUnsignedShort reads a uint16 like GetUnsignedShort, without advancing the offset.
+func (stream *Stream) PeekUnsignedShort() uint16 {
+	offset := stream.Offset
+	v := stream.GetUnsignedShort()
+	stream.Offset = offset
+	return v
+}
+
+// PeekInt reads a int32 like GetInt, without advancing the offset.
+func (stream *Stream) PeekInt() int32 {
+	offset := stream.Offset
+	v := stream.GetInt()
+	stream.Offset = offset
+	return v
+}
+
+// PeekUnsignedInt reads a uint32 like GetUnsignedInt, without advancing the offset.
+func (stream *Stream) PeekUnsignedInt() uint32 {
+	offset := stream.Offset
+	v := stream.GetUnsignedInt()
+	stream.Offset = offset
+	return v
+}
+
+// PeekLong reads a int64 like GetLong, without advancing the offset.
+func (stream *Stream) PeekLong() int64 {
+	offset := stream.Offset
+	v := stream.GetLong()
+	stream.Offset = offset
+	return v
+}
+
+// PeekUnsignedLong reads a uint64 like GetUnsignedLong, without advancing the offset.
+func (stream *Stream) PeekUnsignedLong() uint64 {
+	offset := stream.Offset
+	v := stream.GetUnsignedLong()
+	stream.Offset = offset
+	return v
+}
+
+// PeekVarInt reads a int32 like GetVarInt, without advancing the offset.
+func (stream *Stream) PeekVarInt() int32 {
+	offset := stream.Offset
+	v := stream.GetVarInt()
+	stream.Offset = offset
+	return v
+}
+
+// PeekUnsignedVarInt reads a uint32 like GetUnsignedVarInt, without advancing the offset.
+func (stream *Stream) PeekUnsignedVarInt() uint32 {
+	offset := stream.Offset
+	v := stream.GetUnsignedVarInt()
+	stream.Offset = offset
+	return v
+}
+
+// PeekVarLong reads a int64 like GetVarLong, without advancing the offset.
+func (stream *Stream) PeekVarLong() int64 {
+	offset := stream.Offset
+	v := stream.GetVarLong()
+	stream.Offset = offset
+	return v
+}
+
+// PeekUnsignedVarLong reads a uint64 like GetUnsignedVarLong, without advancing the offset.
+func (stream *Stream) PeekUnsignedVarLong() uint64 {
+	offset := stream.Offset
+	v := stream.GetUnsignedVarLong()
+	stream.Offset = offset
+	return v
+}
@@ -0,0 +1,28 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestPeekAndSkip(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedVarInt(300)
+	stream.PutInt(-5)
+	stream.PutByte(7)
+
+	assert.Equal(t, stream.PeekUnsignedVarInt(), uint32(300))
+	assert.Equal(t, stream.PeekUnsignedVarInt(), uint32(300))
+	assert.DeepEqual(t, stream.Peek(2), b(0xac, 0x02))
+	stream.Skip(2)
+
+	assert.Equal(t, stream.PeekInt(), int32(-5))
+	assert.Equal(t, stream.GetInt(), int32(-5))
+	assert.Equal(t, stream.PeekByte(), byte(7))
+	assert.Equal(t, stream.Offset, 6)
+
+	stream.Skip(2)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+	assert.Equal(t, stream.Offset, 6)
+}
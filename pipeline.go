@@ -0,0 +1,200 @@
+package binutils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// DefaultMaxDecompressedSize is the maximum amount of bytes decompressed by the compression transformers,
+// unless a stream sets its own maximum with SetMaxDecompressedSize. Larger output is rejected with an error matching ErrLimitExceeded, so that small compressed inputs
+// cannot expand into huge allocations.
+const DefaultMaxDecompressedSize = 64 << 20
+
+// Transformer transforms encoded data, for example by compressing, encrypting or checksumming it.
+// Decode reverses Encode.
+type Transformer interface {
+	Encode(data []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+// Pipeline is a chain of transformers.
+// Encoding applies the transformers in order, decoding applies them in reverse order.
+type Pipeline []Transformer
+
+// NewPipeline returns a new pipeline of the given transformers.
+func NewPipeline(transformers ...Transformer) Pipeline {
+	return Pipeline(transformers)
+}
+
+// Encode passes data through all transformers of the pipeline in order.
+func (pipeline Pipeline) Encode(data []byte) ([]byte, error) {
+	var err error
+	for _, transformer := range pipeline {
+		if data, err = transformer.Encode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Decode passes data through all transformers of the pipeline in reverse order.
+func (pipeline Pipeline) Decode(data []byte) ([]byte, error) {
+	var err error
+	for i := len(pipeline) - 1; i >= 0; i-- {
+		if data, err = pipeline[i].Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// SetPipeline sets the pipeline used by Encoded and SetEncoded.
+func (stream *Stream) SetPipeline(pipeline Pipeline) {
+	stream.pipeline = pipeline
+}
+
+// GetPipeline returns the pipeline of the stream.
+func (stream *Stream) GetPipeline() Pipeline {
+	return stream.pipeline
+}
+
+// Encoded returns the buffer of the stream passed through its pipeline.
+func (stream *Stream) Encoded() ([]byte, error) {
+	return stream.pipeline.Encode(stream.Buffer)
+}
+
+// SetEncoded passes data through the pipeline of the stream in reverse,
+// and sets the result as buffer of the stream with the offset reset.
+func (stream *Stream) SetEncoded(data []byte) error {
+	if !stream.writable("SetEncoded", 0) {
+		return stream.err
+	}
+	data, err := stream.pipeline.Decode(data)
+	if err != nil {
+		return err
+	}
+	if !stream.fits("SetEncoded", len(data)) {
+		return stream.err
+	}
+	stream.Buffer = data
+	stream.Offset = 0
+	return nil
+}
+
+// transformerFuncs is a Transformer implemented by two functions.
+type transformerFuncs struct {
+	encode func([]byte) ([]byte, error)
+	decode func([]byte) ([]byte, error)
+}
+
+func (transformer transformerFuncs) Encode(data []byte) ([]byte, error) {
+	return transformer.encode(data)
+}
+
+func (transformer transformerFuncs) Decode(data []byte) ([]byte, error) {
+	return transformer.decode(data)
+}
+
+// NewTransformer returns a transformer using the given encode and decode functions.
+func NewTransformer(encode, decode func([]byte) ([]byte, error)) Transformer {
+	return transformerFuncs{encode, decode}
+}
+
+// compressor is a Transformer compressing data, which decoding is bounded by a maximum decompressed size.
+type compressor struct {
+	encode    func([]byte) ([]byte, error)
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+func (transformer compressor) Encode(data []byte) ([]byte, error) {
+	return transformer.encode(data)
+}
+
+// Decode decompresses the data, up to DefaultMaxDecompressedSize bytes.
+func (transformer compressor) Decode(data []byte) ([]byte, error) {
+	return transformer.DecodeLimit(data, DefaultMaxDecompressedSize)
+}
+
+// DecodeLimit decompresses the data, returning an error matching ErrLimitExceeded once more than max bytes come out.
+func (transformer compressor) DecodeLimit(data []byte, max int) ([]byte, error) {
+	reader, err := transformer.newReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readLimited(reader, max)
+}
+
+// ZlibTransformer returns a transformer compressing data with zlib at the given level.
+func ZlibTransformer(level int) Transformer {
+	return compressor{func(data []byte) ([]byte, error) {
+		var buffer bytes.Buffer
+		writer, err := zlib.NewWriterLevel(&buffer, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}, zlib.NewReader}
+}
+
+// readLimited reads the reader to its end, returning an error matching ErrLimitExceeded
+// once more than max bytes are read.
+func readLimited(reader io.Reader, max int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, int64(max)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > max {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes: %w", max, ErrLimitExceeded)
+	}
+	return data, nil
+}
+
+// CRC32Transformer returns a transformer appending a big endian IEEE CRC32 checksum to data.
+// Decoding verifies and strips the checksum, returning ErrChecksumMismatch if it does not match.
+func CRC32Transformer() Transformer {
+	return NewTransformer(func(data []byte) ([]byte, error) {
+		out := make([]byte, 0, len(data)+4)
+		out = append(out, data...)
+		WriteUnsignedInt(&out, crc32.ChecksumIEEE(data))
+		return out, nil
+	}, func(data []byte) ([]byte, error) {
+		if len(data) < 4 {
+			return nil, ErrUnexpectedEOF
+		}
+		body, offset := data[:len(data)-4], len(data)-4
+		if ReadUnsignedInt(&data, &offset) != crc32.ChecksumIEEE(body) {
+			return nil, ErrChecksumMismatch
+		}
+		return body, nil
+	})
+}
+
+// AEADTransformer returns a transformer sealing data with the given AEAD.
+// A random nonce is generated for every call to Encode and prepended to the sealed data.
+func AEADTransformer(aead cipher.AEAD) Transformer {
+	return NewTransformer(func(data []byte) ([]byte, error) {
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		return aead.Seal(nonce, nonce, data, nil), nil
+	}, func(data []byte) ([]byte, error) {
+		if len(data) < aead.NonceSize() {
+			return nil, ErrUnexpectedEOF
+		}
+		nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+		return aead.Open(nil, nonce, sealed, nil)
+	})
+}
@@ -0,0 +1,56 @@
+package binutils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestPipelineRoundTrip(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	assert.NilError(t, err)
+	aead, err := cipher.NewGCM(block)
+	assert.NilError(t, err)
+
+	stream := NewStream()
+	stream.SetPipeline(NewPipeline(ZlibTransformer(6), AEADTransformer(aead), CRC32Transformer()))
+	stream.PutString("hello world")
+	stream.PutInt(42)
+
+	encoded, err := stream.Encoded()
+	assert.NilError(t, err)
+
+	decoded := NewStream()
+	decoded.SetPipeline(stream.GetPipeline())
+	assert.NilError(t, decoded.SetEncoded(encoded))
+	assert.Equal(t, decoded.GetString(), "hello world")
+	assert.Equal(t, decoded.GetInt(), int32(42))
+}
+
+func TestCRC32TransformerMismatch(t *testing.T) {
+	transformer := CRC32Transformer()
+	encoded, err := transformer.Encode(b(0x01, 0x02))
+	assert.NilError(t, err)
+
+	encoded[0] ^= 0xff
+	_, err = transformer.Decode(encoded)
+	assert.Equal(t, err, ErrChecksumMismatch)
+}
+
+func TestZlibTransformerLimit(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := zlib.NewWriter(&buffer)
+	chunk := make([]byte, 1<<20)
+	for i := 0; i < DefaultMaxDecompressedSize/len(chunk); i++ {
+		writer.Write(chunk)
+	}
+	writer.Write([]byte{0})
+	writer.Close()
+
+	_, err := ZlibTransformer(6).Decode(buffer.Bytes())
+	assert.Assert(t, errors.Is(err, ErrLimitExceeded))
+}
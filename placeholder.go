@@ -0,0 +1,120 @@
+package binutils
+
+import "fmt"
+
+// Placeholder is space reserved in a stream by Reserve, filled in later once its value is known,
+// such as a length or checksum of the bytes written after it. Compacting the stream moves the reserved bytes,
+// so placeholders must be filled before.
+type Placeholder struct {
+	stream *Stream
+	offset int
+	size   int
+}
+
+// Reserve appends n zero bytes to the stream and returns a placeholder to fill them in later.
+func (stream *Stream) Reserve(n int) Placeholder {
+	offset := len(stream.Buffer)
+	if n < 0 || !stream.writable("Reserve", n) {
+		return Placeholder{stream, offset, 0}
+	}
+	stream.Buffer = append(stream.Buffer, make([]byte, n)...)
+	return Placeholder{stream, offset, n}
+}
+
+// Offset returns the position of the placeholder in the buffer.
+func (placeholder Placeholder) Offset() int {
+	return placeholder.offset
+}
+
+// Size returns the amount of bytes reserved.
+func (placeholder Placeholder) Size() int {
+	return placeholder.size
+}
+
+// Written returns the amount of bytes written to the stream after the placeholder.
+func (placeholder Placeholder) Written() int {
+	return len(placeholder.stream.Buffer) - placeholder.offset - placeholder.size
+}
+
+// fill returns the reserved bytes for the operation op writing n bytes. If n does not match the size of
+// the placeholder, or the reserved bytes were truncated, an error is set on the stream and nil is returned.
+func (placeholder Placeholder) fill(op string, n int) []byte {
+	stream := placeholder.stream
+	if !stream.writable(op, 0) {
+		return nil
+	}
+	if n != placeholder.size {
+		stream.SetError(&StreamError{op, placeholder.offset, fmt.Errorf("cannot fill %d reserved bytes with %d bytes", placeholder.size, n)})
+		return nil
+	}
+	if placeholder.offset+n > len(stream.Buffer) {
+		stream.SetError(&StreamError{op, placeholder.offset, ErrUnexpectedEOF})
+		return nil
+	}
+	return stream.Buffer[placeholder.offset : placeholder.offset+n]
+}
+
+// PutBytes fills the placeholder with the bytes, which must be as many as were reserved.
+func (placeholder Placeholder) PutBytes(bytes []byte) {
+	if b := placeholder.fill("PutBytes", len(bytes)); b != nil {
+		copy(b, bytes)
+	}
+}
+
+// PutByte fills a placeholder of 1 byte.
+func (placeholder Placeholder) PutByte(v byte) {
+	if b := placeholder.fill("PutByte", 1); b != nil {
+		b[0] = v
+	}
+}
+
+// PutShort fills a placeholder of 2 bytes with an int16 in the byte order of the stream.
+func (placeholder Placeholder) PutShort(v int16) {
+	if b := placeholder.fill("PutShort", 2); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
+
+// PutUnsignedShort fills a placeholder of 2 bytes with a uint16 in the byte order of the stream.
+func (placeholder Placeholder) PutUnsignedShort(v uint16) {
+	if b := placeholder.fill("PutUnsignedShort", 2); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
+
+// PutTriad fills a placeholder of 3 bytes with a triad in the byte order of the stream.
+func (placeholder Placeholder) PutTriad(v uint32) {
+	if b := placeholder.fill("PutTriad", 3); b != nil {
+		encoded := make([]byte, 0, 3)
+		WriteTriadEndian(&encoded, v, placeholder.stream.endian)
+		copy(b, encoded)
+	}
+}
+
+// PutInt fills a placeholder of 4 bytes with an int32 in the byte order of the stream.
+func (placeholder Placeholder) PutInt(v int32) {
+	if b := placeholder.fill("PutInt", 4); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
+
+// PutUnsignedInt fills a placeholder of 4 bytes with a uint32 in the byte order of the stream.
+func (placeholder Placeholder) PutUnsignedInt(v uint32) {
+	if b := placeholder.fill("PutUnsignedInt", 4); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
+
+// PutLong fills a placeholder of 8 bytes with an int64 in the byte order of the stream.
+func (placeholder Placeholder) PutLong(v int64) {
+	if b := placeholder.fill("PutLong", 8); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
+
+// PutUnsignedLong fills a placeholder of 8 bytes with a uint64 in the byte order of the stream.
+func (placeholder Placeholder) PutUnsignedLong(v uint64) {
+	if b := placeholder.fill("PutUnsignedLong", 8); b != nil {
+		putNumber(b, placeholder.stream.byteOrder(), v)
+	}
+}
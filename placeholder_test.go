@@ -0,0 +1,45 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestReserve(t *testing.T) {
+	stream := NewStream()
+	length := stream.Reserve(4)
+	checksum := stream.Reserve(2)
+	stream.PutString("body")
+	assert.Equal(t, length.Offset(), 0)
+	assert.Equal(t, length.Size(), 4)
+	assert.Equal(t, checksum.Written(), 5)
+
+	length.PutUnsignedInt(uint32(length.Written()))
+	checksum.PutShort(-1)
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 7, 0xff, 0xff, 4, 'b', 'o', 'd', 'y'))
+	assert.NilError(t, stream.Err())
+
+	stream.SetEndianness(LittleEndian)
+	checksum.PutTriad(1)
+	var err *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &err))
+	assert.Equal(t, err.Op, "PutTriad")
+	assert.Equal(t, err.Offset, 4)
+
+	stream = NewStream()
+	stream.Begin()
+	placeholder := stream.Reserve(8)
+	assert.NilError(t, stream.Rollback())
+	placeholder.PutLong(1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream = NewStream()
+	placeholder = stream.Reserve(3)
+	placeholder.PutTriad(0x010203)
+	stream.Freeze()
+	placeholder.PutBytes(b(0, 0, 0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrFrozen))
+	assert.DeepEqual(t, stream.Buffer, b(0x01, 0x02, 0x03))
+}
@@ -0,0 +1,69 @@
+package binutils
+
+// DefaultPointerSize is the size of pointers and relative offsets of streams in bytes, unless set otherwise.
+const DefaultPointerSize = 8
+
+// SetPointerSize sets the size of pointers and relative offsets in bytes, such as 4 for 32 bit executables.
+// A size other than 4 or 8 uses DefaultPointerSize.
+func (stream *Stream) SetPointerSize(size int) {
+	stream.pointerSize = size
+}
+
+// GetPointerSize returns the size of pointers and relative offsets in bytes.
+func (stream *Stream) GetPointerSize() int {
+	if stream.pointerSize != 4 {
+		return DefaultPointerSize
+	}
+	return 4
+}
+
+// PutPointer writes a pointer of the pointer size of the stream in its byte order.
+// Pointers too large for 4 byte pointers are truncated.
+func (stream *Stream) PutPointer(v uint64) {
+	if !stream.writable("PutPointer", stream.GetPointerSize()) {
+		return
+	}
+	if stream.GetPointerSize() == 4 {
+		WriteUnsignedIntEndian(&stream.Buffer, uint32(v), stream.endian)
+		return
+	}
+	WriteUnsignedLongEndian(&stream.Buffer, v, stream.endian)
+}
+
+// GetPointer reads a pointer of the pointer size of the stream in its byte order.
+func (stream *Stream) GetPointer() uint64 {
+	if !stream.check("GetPointer", stream.GetPointerSize()) {
+		return 0
+	}
+	if stream.GetPointerSize() == 4 {
+		return uint64(ReadUnsignedIntEndian(&stream.Buffer, &stream.Offset, stream.endian))
+	}
+	return ReadUnsignedLongEndian(&stream.Buffer, &stream.Offset, stream.endian)
+}
+
+// PutOffsetFrom writes the position target in the buffer as signed offset from the position base,
+// of the pointer size of the stream.
+func (stream *Stream) PutOffsetFrom(base, target int) {
+	stream.PutPointer(uint64(int64(target - base)))
+}
+
+// GetOffsetFrom reads a signed offset of the pointer size of the stream, and returns the position in the buffer
+// it points to relative to the position base, such as the start of a section or of the enclosing object.
+// If the position is outside the buffer, ErrUnexpectedEOF is set on the stream.
+func (stream *Stream) GetOffsetFrom(base int) int {
+	offset := stream.Offset
+	v := stream.GetPointer()
+	if stream.err != nil {
+		return 0
+	}
+	relative := int64(v)
+	if stream.GetPointerSize() == 4 {
+		relative = int64(int32(v))
+	}
+	position := int64(base) + relative
+	if position < 0 || position > int64(len(stream.Buffer)) {
+		stream.SetError(&StreamError{"GetOffsetFrom", offset, ErrUnexpectedEOF})
+		return 0
+	}
+	return int(position)
+}
@@ -0,0 +1,45 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestPointer(t *testing.T) {
+	stream := NewStream()
+	assert.Equal(t, stream.GetPointerSize(), 8)
+	stream.PutPointer(0x0102)
+	stream.SetPointerSize(4)
+	stream.SetEndianness(LittleEndian)
+	stream.PutPointer(0x0304)
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 0, 0, 0, 0x01, 0x02, 0x04, 0x03, 0, 0))
+
+	stream.SetPointerSize(8)
+	stream.SetEndianness(BigEndian)
+	assert.Equal(t, stream.GetPointer(), uint64(0x0102))
+	stream.SetPointerSize(4)
+	stream.SetEndianness(LittleEndian)
+	assert.Equal(t, stream.GetPointer(), uint64(0x0304))
+
+	stream.SetPointerSize(3)
+	assert.Equal(t, stream.GetPointerSize(), 8)
+}
+
+func TestOffsetFrom(t *testing.T) {
+	stream := NewStream()
+	stream.SetPointerSize(4)
+	stream.PutOffsetFrom(4, 12)
+	stream.PutOffsetFrom(12, 0)
+	stream.PutOffsetFrom(0, 64)
+	assert.DeepEqual(t, stream.Buffer[4:8], b(0xff, 0xff, 0xff, 0xf4))
+
+	assert.Equal(t, stream.GetOffsetFrom(4), 12)
+	assert.Equal(t, stream.GetOffsetFrom(12), 0)
+	assert.Equal(t, stream.GetOffsetFrom(0), 0)
+	var err *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &err))
+	assert.Equal(t, err.Op, "GetOffsetFrom")
+	assert.Equal(t, err.Offset, 8)
+}
@@ -0,0 +1,37 @@
+package binutils
+
+import (
+	"sync"
+)
+
+// maxPooledCapacity is the maximum buffer capacity of streams kept in the pool.
+// Streams with larger buffers are dropped on release, so that a single large packet does not pin its memory.
+const maxPooledCapacity = 64 * 1024
+
+// streamPool holds released streams.
+var streamPool = sync.Pool{
+	New: func() interface{} {
+		return NewStreamWithCapacity(512)
+	},
+}
+
+// NewStreamWithCapacity returns a new stream with an empty buffer of the given capacity.
+func NewStreamWithCapacity(capacity int) *Stream {
+	return &Stream{Buffer: make([]byte, 0, capacity)}
+}
+
+// AcquireStream returns an empty stream from the pool.
+// The stream should be returned to the pool with ReleaseStream once it is no longer used.
+func AcquireStream() *Stream {
+	return streamPool.Get().(*Stream)
+}
+
+// ReleaseStream resets the stream and returns it to the pool.
+// The stream and its buffer must not be used after releasing it.
+func ReleaseStream(stream *Stream) {
+	if cap(stream.Buffer) > maxPooledCapacity {
+		return
+	}
+	*stream = Stream{Buffer: stream.Buffer[:0]}
+	streamPool.Put(stream)
+}
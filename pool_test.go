@@ -0,0 +1,52 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestStreamPool(t *testing.T) {
+	stream := AcquireStream()
+	stream.PutInt(1)
+	stream.SetEndianness(LittleEndian)
+	ReleaseStream(stream)
+
+	stream = AcquireStream()
+	assert.Equal(t, len(stream.Buffer), 0)
+	assert.Equal(t, stream.GetEndianness(), BigEndian)
+	ReleaseStream(stream)
+}
+
+func TestWritesDoNotAllocate(t *testing.T) {
+	stream := NewStreamWithCapacity(1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		stream.Buffer = stream.Buffer[:0]
+		stream.PutShort(1)
+		stream.PutInt(2)
+		stream.PutLong(3)
+		stream.PutLittleDouble(4)
+		stream.PutVarInt(5)
+		stream.PutTriad(6)
+	})
+	assert.Equal(t, allocs, float64(0))
+}
+
+func BenchmarkPutInt(b *testing.B) {
+	stream := NewStreamWithCapacity(4 * 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%1024 == 0 {
+			stream.Buffer = stream.Buffer[:0]
+		}
+		stream.PutInt(int32(i))
+	}
+}
+
+func BenchmarkAcquireStream(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		stream := AcquireStream()
+		stream.PutLong(int64(i))
+		ReleaseStream(stream)
+	}
+}
@@ -0,0 +1,260 @@
+package binutils
+
+import (
+	"fmt"
+)
+
+// PrefixType is the encoding of a length prefix.
+type PrefixType byte
+
+const (
+	PrefixUnsignedVarInt PrefixType = iota
+	PrefixVarInt
+	PrefixByte
+	PrefixUnsignedShort
+	PrefixLittleUnsignedShort
+	PrefixInt
+	PrefixLittleInt
+	PrefixUnsignedInt
+	PrefixLittleUnsignedInt
+)
+
+// WritePrefix writes a length prefix of the given type to the buffer.
+// Lengths that do not fit the prefix type are truncated; PutPrefix checks them instead.
+func WritePrefix(buffer *[]byte, prefix PrefixType, length int) {
+	switch prefix {
+	case PrefixVarInt:
+		WriteVarInt(buffer, int32(length))
+	case PrefixByte:
+		WriteByte(buffer, byte(length))
+	case PrefixUnsignedShort:
+		WriteUnsignedShort(buffer, uint16(length))
+	case PrefixLittleUnsignedShort:
+		WriteLittleUnsignedShort(buffer, uint16(length))
+	case PrefixInt:
+		WriteInt(buffer, int32(length))
+	case PrefixLittleInt:
+		WriteLittleInt(buffer, int32(length))
+	case PrefixUnsignedInt:
+		WriteUnsignedInt(buffer, uint32(length))
+	case PrefixLittleUnsignedInt:
+		WriteLittleUnsignedInt(buffer, uint32(length))
+	default:
+		WriteUnsignedVarInt(buffer, uint32(length))
+	}
+}
+
+// ReadPrefix reads a length prefix of the given type from the buffer.
+// It panics with ErrNegativeLength if the length is negative.
+func ReadPrefix(buffer *[]byte, offset *int, prefix PrefixType) int {
+	var length int64
+	switch prefix {
+	case PrefixVarInt:
+		length = int64(ReadVarInt(buffer, offset))
+	case PrefixUnsignedVarInt:
+		length = int64(ReadUnsignedVarInt(buffer, offset))
+	default:
+		length = readFixedPrefix(buffer, offset, prefix)
+	}
+	if length < 0 {
+		panic(ErrNegativeLength)
+	}
+	return int(length)
+}
+
+// size returns the encoded size of fixed width prefix types, or 0 for varint prefix types.
+func (prefix PrefixType) size() int {
+	switch prefix {
+	case PrefixByte:
+		return 1
+	case PrefixUnsignedShort, PrefixLittleUnsignedShort:
+		return 2
+	case PrefixInt, PrefixLittleInt, PrefixUnsignedInt, PrefixLittleUnsignedInt:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// prefixSize returns the encoded size of the length prefix of the given type.
+func prefixSize(prefix PrefixType, length int) int {
+	if size := prefix.size(); size > 0 {
+		return size
+	}
+	if prefix == PrefixVarInt {
+		return uvarintSize(uint64(toZigZag32(int32(length))))
+	}
+	return uvarintSize(uint64(uint32(length)))
+}
+
+// readFixedPrefix reads a length prefix of a fixed width prefix type.
+func readFixedPrefix(buffer *[]byte, offset *int, prefix PrefixType) int64 {
+	switch prefix {
+	case PrefixByte:
+		return int64(ReadByte(buffer, offset))
+	case PrefixUnsignedShort:
+		return int64(ReadUnsignedShort(buffer, offset))
+	case PrefixLittleUnsignedShort:
+		return int64(ReadLittleUnsignedShort(buffer, offset))
+	case PrefixInt:
+		return int64(ReadInt(buffer, offset))
+	case PrefixLittleInt:
+		return int64(ReadLittleInt(buffer, offset))
+	case PrefixUnsignedInt:
+		return int64(ReadUnsignedInt(buffer, offset))
+	default:
+		return int64(ReadLittleUnsignedInt(buffer, offset))
+	}
+}
+
+// WriteStringWithPrefix writes a string prefixed with its length as the given prefix type.
+func WriteStringWithPrefix(buffer *[]byte, str string, prefix PrefixType) {
+	WritePrefix(buffer, prefix, len(str))
+	*buffer = append(*buffer, str...)
+}
+
+// ReadStringWithPrefix reads a string prefixed with its length as the given prefix type.
+func ReadStringWithPrefix(buffer *[]byte, offset *int, prefix PrefixType) string {
+	return string(Read(buffer, offset, ReadPrefix(buffer, offset, prefix)))
+}
+
+// PutPrefix writes a length prefix of the given type.
+// If the length does not fit the prefix type, a *LengthError is set on the stream.
+func (stream *Stream) PutPrefix(prefix PrefixType, length int) {
+	if !stream.prefixFits("PutPrefix", prefix, length) || !stream.writable("PutPrefix", prefixSize(prefix, length)) {
+		return
+	}
+	WritePrefix(&stream.Buffer, prefix, length)
+}
+
+// prefixFits checks if the length can be written as a prefix of the given type by the operation op.
+// Otherwise ErrNegativeLength or a *LengthError is set on the stream.
+func (stream *Stream) prefixFits(op string, prefix PrefixType, length int) bool {
+	if length < 0 {
+		stream.SetError(&StreamError{op, len(stream.Buffer), ErrNegativeLength})
+		return false
+	}
+	if max := prefixMax(prefix); length > max {
+		stream.SetError(&StreamError{op, len(stream.Buffer), &LengthError{length, max}})
+		return false
+	}
+	return true
+}
+
+// GetPrefix reads a length prefix of the given type.
+// Negative lengths are set as error on the stream.
+func (stream *Stream) GetPrefix(prefix PrefixType) int {
+	offset := stream.Offset
+	var length int64
+	switch prefix {
+	case PrefixVarInt:
+		length = int64(stream.GetVarInt())
+	case PrefixUnsignedVarInt:
+		length = int64(stream.GetUnsignedVarInt())
+	default:
+		if !stream.check("GetPrefix", prefix.size()) {
+			return 0
+		}
+		length = readFixedPrefix(&stream.Buffer, &stream.Offset, prefix)
+	}
+	if length < 0 {
+		stream.SetError(&StreamError{"GetPrefix", offset, ErrNegativeLength})
+		return 0
+	}
+	return int(length)
+}
+
+// SetStringPrefix sets the prefix type used by PutString, GetString and the length prefixed bytes functions.
+// Streams use unsigned varint prefixes by default.
+func (stream *Stream) SetStringPrefix(prefix PrefixType) {
+	stream.stringPrefix = prefix
+}
+
+// GetStringPrefix returns the prefix type used for strings.
+func (stream *Stream) GetStringPrefix() PrefixType {
+	return stream.stringPrefix
+}
+
+// PutStringWithPrefix writes a string prefixed with its length as the given prefix type.
+// If the length does not fit the prefix type, a *LengthError is set on the stream.
+func (stream *Stream) PutStringWithPrefix(v string, prefix PrefixType) {
+	if !stream.prefixFits("PutStringWithPrefix", prefix, len(v)) || !stream.writable("PutStringWithPrefix", prefixSize(prefix, len(v))+len(v)) {
+		return
+	}
+	WriteStringWithPrefix(&stream.Buffer, v, prefix)
+}
+
+// GetStringWithPrefix reads a string prefixed with its length as the given prefix type.
+// Strings longer than the maximum string length of the stream are set as error on the stream.
+func (stream *Stream) GetStringWithPrefix(prefix PrefixType) string {
+	return string(stream.getPrefixed("GetString", prefix, stream.maxStringLength))
+}
+
+// LengthError is the error set on a stream when a length prefix exceeds the maximum length.
+// It matches ErrLimitExceeded with errors.Is.
+type LengthError struct {
+	Length int
+	Limit  int
+}
+
+func (err *LengthError) Error() string {
+	return fmt.Sprintf("length %d exceeds limit of %d", err.Length, err.Limit)
+}
+
+// Is checks if the target is ErrLimitExceeded.
+func (err *LengthError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// SetMaxStringLength sets the maximum length of strings and length prefixed bytes read from the stream.
+// Longer lengths are rejected before any bytes are read. A length of 0 or less disables the limit, which is the default.
+func (stream *Stream) SetMaxStringLength(length int) {
+	stream.maxStringLength = length
+}
+
+// GetMaxStringLength returns the maximum length of strings and length prefixed bytes read from the stream,
+// or 0 if there is no limit.
+func (stream *Stream) GetMaxStringLength() int {
+	if stream.maxStringLength <= 0 {
+		return 0
+	}
+	return stream.maxStringLength
+}
+
+// GetStringMax reads a string with a length of at most max bytes.
+// If the length prefix exceeds max, a *LengthError is set on the stream and an empty string is returned.
+func (stream *Stream) GetStringMax(max int) string {
+	return string(stream.getPrefixed("GetStringMax", stream.stringPrefix, max))
+}
+
+// GetLengthPrefixedBytesMax reads length prefixed bytes with a length of at most max bytes.
+// If the length prefix exceeds max, a *LengthError is set on the stream and nil is returned.
+func (stream *Stream) GetLengthPrefixedBytesMax(max int) []byte {
+	return copyBytes(stream.getPrefixed("GetLengthPrefixedBytesMax", stream.stringPrefix, max))
+}
+
+// copyBytes returns a copy of the bytes, so that they do not share the buffer of the stream.
+func copyBytes(bytes []byte) []byte {
+	if bytes == nil {
+		return nil
+	}
+	return append([]byte{}, bytes...)
+}
+
+// getPrefixed reads bytes, sharing the buffer of the stream, prefixed with their length as the given prefix type for the operation op.
+// Lengths exceeding max are set as error on the stream, unless max is 0 or less.
+func (stream *Stream) getPrefixed(op string, prefix PrefixType, max int) []byte {
+	offset := stream.Offset
+	length := stream.GetPrefix(prefix)
+	if stream.err != nil {
+		return nil
+	}
+	if max > 0 && length > max {
+		stream.SetError(&StreamError{op, offset, &LengthError{length, max}})
+		return nil
+	}
+	if !stream.check(op, length) {
+		return nil
+	}
+	return Read(&stream.Buffer, &stream.Offset, length)
+}
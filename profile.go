@@ -0,0 +1,140 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"regexp"
+	"sort"
+	"sync"
+	"text/tabwriter"
+)
+
+// indexPattern matches the indices in the paths of fields, which profiles aggregate.
+var indexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// FieldProfile is the encoded size of a field aggregated over all structs recorded by a profiler.
+// Elements of slices and arrays are aggregated under their path without index, such as Items[].ID.
+type FieldProfile struct {
+	Path  string
+	Count uint64
+	Bytes uint64
+	Min   int
+	Max   int
+}
+
+// SizeBucket is the amount of structs with an encoded size from Min up to and including Max.
+type SizeBucket struct {
+	Min   int
+	Max   int
+	Count uint64
+}
+
+// Profiler aggregates the encoded size of every field over many structs encoded or decoded with MarshalOptions
+// holding it, to find the fields that dominate bandwidth. It is safe for concurrent use.
+type Profiler struct {
+	mu      sync.Mutex
+	fields  map[string]*FieldProfile
+	sizes   [65]uint64
+	structs uint64
+	bytes   uint64
+}
+
+// NewProfiler returns a new empty profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{fields: make(map[string]*FieldProfile)}
+}
+
+// record adds the tracked fields of a struct of size bytes.
+func (profiler *Profiler) record(fields []fieldRange, size int) {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	profiler.structs++
+	profiler.bytes += uint64(size)
+	profiler.sizes[bits.Len(uint(size))]++
+	for _, field := range fields {
+		path := indexPattern.ReplaceAllString(field.path, "[]")
+		profile, ok := profiler.fields[path]
+		if !ok {
+			profile = &FieldProfile{Path: path, Min: field.Len()}
+			profiler.fields[path] = profile
+		}
+		profile.Count++
+		profile.Bytes += uint64(field.Len())
+		profile.Min = min(profile.Min, field.Len())
+		profile.Max = max(profile.Max, field.Len())
+	}
+}
+
+// Fields returns the profiles of the fields recorded, with the most bytes first.
+func (profiler *Profiler) Fields() []FieldProfile {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	profiles := make([]FieldProfile, 0, len(profiler.fields))
+	for _, profile := range profiler.fields {
+		profiles = append(profiles, *profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Bytes != profiles[j].Bytes {
+			return profiles[i].Bytes > profiles[j].Bytes
+		}
+		return profiles[i].Path < profiles[j].Path
+	})
+	return profiles
+}
+
+// Histogram returns the amount of structs recorded by encoded size, in buckets of powers of two.
+// Empty buckets are left out.
+func (profiler *Profiler) Histogram() []SizeBucket {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	var buckets []SizeBucket
+	for i, count := range profiler.sizes {
+		if count == 0 {
+			continue
+		}
+		if i == 0 {
+			buckets = append(buckets, SizeBucket{0, 0, count})
+			continue
+		}
+		buckets = append(buckets, SizeBucket{1 << (i - 1), 1<<i - 1, count})
+	}
+	return buckets
+}
+
+// Reset discards everything recorded.
+func (profiler *Profiler) Reset() {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	profiler.fields = make(map[string]*FieldProfile)
+	profiler.sizes = [65]uint64{}
+	profiler.structs = 0
+	profiler.bytes = 0
+}
+
+// WriteReport writes a table of the fields recorded to the writer, with the most bytes first,
+// showing their share of all bytes recorded and their average, minimum and maximum size.
+// Nested structs and slices are listed as well as their fields, so shares add up to more than 100%.
+func (profiler *Profiler) WriteReport(writer io.Writer) error {
+	fields := profiler.Fields()
+	profiler.mu.Lock()
+	structs, total := profiler.structs, profiler.bytes
+	profiler.mu.Unlock()
+
+	if _, err := fmt.Fprintf(writer, "%d structs, %d bytes\n", structs, total); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "field\tcount\tbytes\tshare\tavg\tmin\tmax\n")
+	for _, field := range fields {
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(field.Bytes) / float64(total)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%.1f\t%d\t%d\n", field.Path, field.Count, field.Bytes, share,
+			float64(field.Bytes)/float64(field.Count), field.Min, field.Max)
+	}
+	return w.Flush()
+}
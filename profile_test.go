@@ -0,0 +1,48 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestProfiler(t *testing.T) {
+	type item struct {
+		Count uint16
+	}
+	type packet struct {
+		Name  string
+		Items []item
+	}
+	profiler := NewProfiler()
+	options := MarshalOptions{Profiler: profiler}
+	buffer, err := options.Marshal(packet{Name: "steve", Items: []item{{1}, {2}}})
+	assert.NilError(t, err)
+	var decoded packet
+	assert.NilError(t, options.Unmarshal(buffer, &decoded))
+	_, err = options.Marshal(packet{Name: "alex"})
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, profiler.Fields(), []FieldProfile{
+		{Path: "Name", Count: 3, Bytes: 17, Min: 5, Max: 6},
+		{Path: "Items", Count: 3, Bytes: 11, Min: 1, Max: 5},
+		{Path: "Items[]", Count: 4, Bytes: 8, Min: 2, Max: 2},
+		{Path: "Items[].Count", Count: 4, Bytes: 8, Min: 2, Max: 2},
+	})
+	assert.DeepEqual(t, profiler.Histogram(), []SizeBucket{{4, 7, 1}, {8, 15, 2}})
+
+	var report strings.Builder
+	assert.NilError(t, profiler.WriteReport(&report))
+	assert.Assert(t, strings.HasPrefix(report.String(), "3 structs, 28 bytes"))
+	assert.Assert(t, strings.Contains(report.String(), "60.7%"), report.String())
+
+	// Failed decodes are not recorded.
+	assert.Assert(t, options.Unmarshal(buffer[:3], &decoded) != nil)
+	assert.Equal(t, len(profiler.Histogram()), 2)
+
+	profiler.Reset()
+	assert.Equal(t, len(profiler.Fields()), 0)
+	assert.Equal(t, len(profiler.Histogram()), 0)
+}
@@ -0,0 +1,304 @@
+// Package raknet reads and writes the datagrams of the RakNet protocol on binutils streams:
+// datagram headers, the frames (encapsulated packets) they carry, and ACK and NAK packets.
+//
+// Sequence numbers and indices are little endian triads, while lengths and split information are big endian,
+// regardless of the byte order of the stream.
+package raknet
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/irmine/binutils"
+)
+
+// Datagram flags, set in the first byte of every datagram.
+const (
+	FlagValid          = 0x80
+	FlagACK            = 0x40
+	FlagNAK            = 0x20
+	FlagPacketPair     = 0x10
+	FlagContinuousSend = 0x08
+	FlagNeedsBAndAS    = 0x04
+)
+
+// MaxFrameBodySize is the maximum size of the body of a frame, which length is written in bits as uint16.
+const MaxFrameBodySize = 0xffff / 8
+
+var (
+	// ErrInvalidFlags is used when the flags of a datagram do not match the packet read.
+	ErrInvalidFlags = errors.New("raknet: invalid datagram flags")
+	// ErrInvalidReliability is used when a frame with an unknown reliability is written.
+	ErrInvalidReliability = errors.New("raknet: invalid reliability")
+	// ErrInvalidRange is used when a range of an ACK or NAK ends before it starts.
+	ErrInvalidRange = errors.New("raknet: invalid range")
+)
+
+// Reliability is the delivery guarantee of a frame.
+type Reliability byte
+
+const (
+	Unreliable Reliability = iota
+	UnreliableSequenced
+	Reliable
+	ReliableOrdered
+	ReliableSequenced
+	UnreliableWithAckReceipt
+	ReliableWithAckReceipt
+	ReliableOrderedWithAckReceipt
+)
+
+// Reliable checks if frames of the reliability carry a message index, to be resent until acknowledged.
+func (reliability Reliability) Reliable() bool {
+	switch reliability {
+	case Reliable, ReliableOrdered, ReliableSequenced, ReliableWithAckReceipt, ReliableOrderedWithAckReceipt:
+		return true
+	}
+	return false
+}
+
+// Sequenced checks if frames of the reliability carry a sequence index, to drop frames older than the latest.
+func (reliability Reliability) Sequenced() bool {
+	return reliability == UnreliableSequenced || reliability == ReliableSequenced
+}
+
+// Ordered checks if frames of the reliability carry an order index and channel. Sequenced frames are ordered too.
+func (reliability Reliability) Ordered() bool {
+	return reliability.Sequenced() || reliability == ReliableOrdered || reliability == ReliableOrderedWithAckReceipt
+}
+
+// DatagramHeader is the header of a datagram carrying frames.
+type DatagramHeader struct {
+	// Flags holds FlagValid and the optional flags of the datagram.
+	Flags byte
+	// Sequence is the datagram sequence number, acknowledged by ACKs.
+	Sequence uint32
+}
+
+// PutDatagramHeader writes the header of a datagram. FlagValid is always set.
+func PutDatagramHeader(stream *binutils.Stream, header DatagramHeader) {
+	stream.PutByte(header.Flags | FlagValid)
+	stream.PutLittleTriad(header.Sequence)
+}
+
+// GetDatagramHeader reads the header of a datagram. ErrInvalidFlags is set on the stream if FlagValid is not set,
+// or if the datagram is an ACK or NAK.
+func GetDatagramHeader(stream *binutils.Stream) DatagramHeader {
+	offset := stream.Offset
+	flags := stream.GetByte()
+	if stream.Err() == nil && (flags&FlagValid == 0 || flags&(FlagACK|FlagNAK) != 0) {
+		stream.SetError(&binutils.StreamError{Op: "GetDatagramHeader", Offset: offset, Err: ErrInvalidFlags})
+		return DatagramHeader{}
+	}
+	return DatagramHeader{flags, stream.GetLittleTriad()}
+}
+
+// Frame is a packet encapsulated in a datagram, or a part of one if the packet was split over several datagrams.
+type Frame struct {
+	Reliability Reliability
+	// MessageIndex is set for reliable frames.
+	MessageIndex uint32
+	// SequenceIndex is set for sequenced frames.
+	SequenceIndex uint32
+	// OrderIndex and OrderChannel are set for ordered and sequenced frames.
+	OrderIndex   uint32
+	OrderChannel byte
+	// Split is set if the frame is part SplitIndex of SplitCount parts of the packet with the given split ID.
+	Split      bool
+	SplitCount uint32
+	SplitID    uint16
+	SplitIndex uint32
+	Body       []byte
+}
+
+// Size returns the encoded size of the frame.
+func (frame *Frame) Size() int {
+	size := 3 + len(frame.Body)
+	if frame.Reliability.Reliable() {
+		size += 3
+	}
+	if frame.Reliability.Sequenced() {
+		size += 3
+	}
+	if frame.Reliability.Ordered() {
+		size += 4
+	}
+	if frame.Split {
+		size += 10
+	}
+	return size
+}
+
+// PutFrame writes the frame. Frames with an unknown reliability or a body larger than MaxFrameBodySize
+// set an error on the stream.
+func PutFrame(stream *binutils.Stream, frame *Frame) {
+	if frame.Reliability > ReliableOrderedWithAckReceipt {
+		stream.SetError(&binutils.StreamError{Op: "PutFrame", Offset: len(stream.Buffer), Err: ErrInvalidReliability})
+		return
+	}
+	if len(frame.Body) > MaxFrameBodySize {
+		stream.SetError(&binutils.StreamError{Op: "PutFrame", Offset: len(stream.Buffer), Err: &binutils.LengthError{Length: len(frame.Body), Limit: MaxFrameBodySize}})
+		return
+	}
+	flags := byte(frame.Reliability) << 5
+	if frame.Split {
+		flags |= 0x10
+	}
+	stream.PutByte(flags)
+	stream.PutUnsignedShortEndian(uint16(len(frame.Body)*8), binutils.BigEndian)
+	if frame.Reliability.Reliable() {
+		stream.PutLittleTriad(frame.MessageIndex)
+	}
+	if frame.Reliability.Sequenced() {
+		stream.PutLittleTriad(frame.SequenceIndex)
+	}
+	if frame.Reliability.Ordered() {
+		stream.PutLittleTriad(frame.OrderIndex)
+		stream.PutByte(frame.OrderChannel)
+	}
+	if frame.Split {
+		stream.PutUnsignedIntEndian(frame.SplitCount, binutils.BigEndian)
+		stream.PutUnsignedShortEndian(frame.SplitID, binutils.BigEndian)
+		stream.PutUnsignedIntEndian(frame.SplitIndex, binutils.BigEndian)
+	}
+	stream.PutBytes(frame.Body)
+}
+
+// GetFrame reads a frame. The body is copied from the stream.
+func GetFrame(stream *binutils.Stream) Frame {
+	flags := stream.GetByte()
+	frame := Frame{Reliability: Reliability(flags >> 5), Split: flags&0x10 != 0}
+	length := (int(stream.GetUnsignedShortEndian(binutils.BigEndian)) + 7) / 8
+	if frame.Reliability.Reliable() {
+		frame.MessageIndex = stream.GetLittleTriad()
+	}
+	if frame.Reliability.Sequenced() {
+		frame.SequenceIndex = stream.GetLittleTriad()
+	}
+	if frame.Reliability.Ordered() {
+		frame.OrderIndex = stream.GetLittleTriad()
+		frame.OrderChannel = stream.GetByte()
+	}
+	if frame.Split {
+		frame.SplitCount = stream.GetUnsignedIntEndian(binutils.BigEndian)
+		frame.SplitID = stream.GetUnsignedShortEndian(binutils.BigEndian)
+		frame.SplitIndex = stream.GetUnsignedIntEndian(binutils.BigEndian)
+	}
+	if body := stream.Get(length); stream.Err() == nil {
+		frame.Body = append([]byte{}, body...)
+	}
+	return frame
+}
+
+// Datagram is a datagram carrying frames.
+type Datagram struct {
+	DatagramHeader
+	Frames []Frame
+}
+
+// PutDatagram writes the header and frames of the datagram.
+func PutDatagram(stream *binutils.Stream, datagram *Datagram) {
+	PutDatagramHeader(stream, datagram.DatagramHeader)
+	for i := range datagram.Frames {
+		PutFrame(stream, &datagram.Frames[i])
+	}
+}
+
+// GetDatagram reads a datagram, reading frames up to the end of the stream.
+func GetDatagram(stream *binutils.Stream) Datagram {
+	datagram := Datagram{DatagramHeader: GetDatagramHeader(stream)}
+	for stream.Err() == nil && stream.Remaining() > 0 {
+		frame := GetFrame(stream)
+		if stream.Err() == nil {
+			datagram.Frames = append(datagram.Frames, frame)
+		}
+	}
+	return datagram
+}
+
+// SequenceRange is a range of datagram sequence numbers, from Start up to and including End.
+type SequenceRange struct {
+	Start uint32
+	End   uint32
+}
+
+// Ranges returns the sequence numbers as the fewest ranges covering them, in ascending order.
+func Ranges(sequences []uint32) []SequenceRange {
+	sorted := append([]uint32{}, sequences...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var ranges []SequenceRange
+	for _, sequence := range sorted {
+		if n := len(ranges); n > 0 && sequence <= ranges[n-1].End+1 {
+			ranges[n-1].End = max(ranges[n-1].End, sequence)
+			continue
+		}
+		ranges = append(ranges, SequenceRange{sequence, sequence})
+	}
+	return ranges
+}
+
+// Acknowledgement is an ACK, acknowledging the datagrams received, or a NAK, reporting datagrams missing.
+type Acknowledgement struct {
+	NAK    bool
+	Ranges []SequenceRange
+}
+
+// PutAcknowledgement writes the ACK or NAK, writing ranges of a single sequence number in short form.
+func PutAcknowledgement(stream *binutils.Stream, ack *Acknowledgement) {
+	if ack.NAK {
+		stream.PutByte(FlagValid | FlagNAK)
+	} else {
+		stream.PutByte(FlagValid | FlagACK)
+	}
+	stream.PutUnsignedShortEndian(uint16(len(ack.Ranges)), binutils.BigEndian)
+	for _, r := range ack.Ranges {
+		if r.Start == r.End {
+			stream.PutBool(true)
+			stream.PutLittleTriad(r.Start)
+			continue
+		}
+		stream.PutBool(false)
+		stream.PutLittleTriad(r.Start)
+		stream.PutLittleTriad(r.End)
+	}
+}
+
+// GetAcknowledgement reads an ACK or NAK. ErrInvalidFlags is set on the stream if the packet is neither,
+// and ErrInvalidRange if a range ends before it starts.
+func GetAcknowledgement(stream *binutils.Stream) Acknowledgement {
+	offset := stream.Offset
+	flags := stream.GetByte()
+	kind := flags & (FlagACK | FlagNAK)
+	if stream.Err() == nil && (flags&FlagValid == 0 || kind == 0 || kind == FlagACK|FlagNAK) {
+		stream.SetError(&binutils.StreamError{Op: "GetAcknowledgement", Offset: offset, Err: ErrInvalidFlags})
+	}
+	ack := Acknowledgement{NAK: kind == FlagNAK}
+	count := int(stream.GetUnsignedShortEndian(binutils.BigEndian))
+	if stream.Err() != nil {
+		return ack
+	}
+	// Every record takes at least 4 bytes, which bounds the allocation by the bytes left.
+	if count > stream.Remaining()/4 {
+		stream.SetError(&binutils.StreamError{Op: "GetAcknowledgement", Offset: offset, Err: binutils.ErrUnexpectedEOF})
+		return ack
+	}
+	ack.Ranges = make([]SequenceRange, 0, count)
+	for i := 0; i < count; i++ {
+		offset := stream.Offset
+		single := stream.GetBool()
+		r := SequenceRange{Start: stream.GetLittleTriad()}
+		r.End = r.Start
+		if !single {
+			r.End = stream.GetLittleTriad()
+		}
+		if stream.Err() != nil {
+			break
+		}
+		if r.End < r.Start {
+			stream.SetError(&binutils.StreamError{Op: "GetAcknowledgement", Offset: offset, Err: ErrInvalidRange})
+			break
+		}
+		ack.Ranges = append(ack.Ranges, r)
+	}
+	return ack
+}
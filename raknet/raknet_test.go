@@ -0,0 +1,74 @@
+package raknet
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+
+	"github.com/irmine/binutils"
+)
+
+func TestDatagram(t *testing.T) {
+	datagram := Datagram{
+		DatagramHeader: DatagramHeader{FlagValid | FlagNeedsBAndAS, 0x010203},
+		Frames: []Frame{
+			{Reliability: Unreliable, Body: []byte{0x00}},
+			{Reliability: ReliableOrdered, MessageIndex: 1, OrderIndex: 2, OrderChannel: 3, Body: []byte{0xfe, 0x01}},
+			{Reliability: ReliableSequenced, MessageIndex: 4, SequenceIndex: 5, OrderIndex: 6,
+				Split: true, SplitCount: 2, SplitID: 7, SplitIndex: 1, Body: []byte{0x02}},
+		},
+	}
+	stream := binutils.NewStream()
+	PutDatagram(stream, &datagram)
+	assert.NilError(t, stream.Err())
+	assert.DeepEqual(t, stream.Buffer[:9], []byte{0x84, 0x03, 0x02, 0x01, 0x00, 0x00, 0x08, 0x00, 0x60})
+	size := 4
+	for i := range datagram.Frames {
+		size += datagram.Frames[i].Size()
+	}
+	assert.Equal(t, len(stream.Buffer), size)
+
+	decoded := GetDatagram(&binutils.Stream{Buffer: stream.Buffer})
+	assert.DeepEqual(t, decoded, datagram)
+
+	truncated := &binutils.Stream{Buffer: stream.Buffer[:len(stream.Buffer)-1]}
+	GetDatagram(truncated)
+	assert.Assert(t, errors.Is(truncated.Err(), binutils.ErrUnexpectedEOF))
+
+	ack := &binutils.Stream{Buffer: []byte{FlagValid | FlagACK, 0, 0, 0}}
+	GetDatagramHeader(ack)
+	assert.Assert(t, errors.Is(ack.Err(), ErrInvalidFlags))
+
+	stream = binutils.NewStream()
+	PutFrame(stream, &Frame{Body: make([]byte, MaxFrameBodySize+1)})
+	var lengthErr *binutils.LengthError
+	assert.Assert(t, errors.As(stream.Err(), &lengthErr))
+}
+
+func TestAcknowledgement(t *testing.T) {
+	ranges := Ranges([]uint32{7, 1, 2, 3, 5, 2, 6})
+	assert.DeepEqual(t, ranges, []SequenceRange{{1, 3}, {5, 7}})
+
+	stream := binutils.NewStream()
+	PutAcknowledgement(stream, &Acknowledgement{NAK: true, Ranges: []SequenceRange{{1, 1}, {5, 7}}})
+	assert.DeepEqual(t, stream.Buffer, []byte{0xa0, 0x00, 0x02, 0x01, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x07, 0x00, 0x00})
+	assert.DeepEqual(t, GetAcknowledgement(stream), Acknowledgement{NAK: true, Ranges: []SequenceRange{{1, 1}, {5, 7}}})
+	assert.NilError(t, stream.Err())
+
+	for _, buffer := range [][]byte{
+		{0x84, 0x00, 0x00},
+		{0xe0, 0x00, 0x00},
+	} {
+		stream := &binutils.Stream{Buffer: buffer}
+		GetAcknowledgement(stream)
+		assert.Assert(t, errors.Is(stream.Err(), ErrInvalidFlags))
+	}
+	stream = &binutils.Stream{Buffer: []byte{0xc0, 0x00, 0x01, 0x00, 0x05, 0x00, 0x00, 0x01, 0x00, 0x00}}
+	GetAcknowledgement(stream)
+	assert.Assert(t, errors.Is(stream.Err(), ErrInvalidRange))
+
+	// Counts beyond the bytes left are rejected before allocating.
+	stream = &binutils.Stream{Buffer: []byte{0xc0, 0xff, 0xff, 0x01, 0x00, 0x00, 0x00}}
+	GetAcknowledgement(stream)
+	assert.Assert(t, errors.Is(stream.Err(), binutils.ErrUnexpectedEOF))
+}
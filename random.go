@@ -0,0 +1,67 @@
+package binutils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// PutNonce writes n cryptographically random bytes. A negative n sets ErrNegativeLength on the stream.
+func (stream *Stream) PutNonce(n int) {
+	if n < 0 {
+		stream.SetError(&StreamError{"PutNonce", len(stream.Buffer), ErrNegativeLength})
+		return
+	}
+	if !stream.writable("PutNonce", n) {
+		return
+	}
+	nonce := make([]byte, n)
+	if _, err := rand.Read(nonce); err != nil {
+		stream.SetError(&StreamError{"PutNonce", len(stream.Buffer), err})
+		return
+	}
+	stream.PutBytes(nonce)
+}
+
+// GetNonce reads a nonce of n bytes. The nonce is copied from the stream, so that it can be kept.
+func (stream *Stream) GetNonce(n int) []byte {
+	if !stream.check("GetNonce", n) {
+		return nil
+	}
+	return copyBytes(Read(&stream.Buffer, &stream.Offset, n))
+}
+
+// PutRandomPadding writes between min and max random bytes of padding, prefixed with their length as unsigned varint.
+// The padding is skipped by SkipPadding. A negative min sets ErrNegativeLength on the stream.
+func (stream *Stream) PutRandomPadding(min, max int) {
+	if min < 0 {
+		stream.SetError(&StreamError{"PutRandomPadding", len(stream.Buffer), ErrNegativeLength})
+		return
+	}
+	if !stream.writable("PutRandomPadding", 0) {
+		return
+	}
+	length := min
+	if max > min {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min+1)))
+		if err != nil {
+			stream.SetError(&StreamError{"PutRandomPadding", len(stream.Buffer), err})
+			return
+		}
+		length += int(n.Int64())
+	}
+	padding := make([]byte, length)
+	if _, err := rand.Read(padding); err != nil {
+		stream.SetError(&StreamError{"PutRandomPadding", len(stream.Buffer), err})
+		return
+	}
+	stream.PutPrefix(PrefixUnsignedVarInt, length)
+	stream.PutBytes(padding)
+}
+
+// SkipPadding skips padding written by PutRandomPadding.
+func (stream *Stream) SkipPadding() {
+	length := stream.GetPrefix(PrefixUnsignedVarInt)
+	if stream.check("SkipPadding", length) {
+		stream.Offset += length
+	}
+}
@@ -0,0 +1,46 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestRandomPadding(t *testing.T) {
+	stream := NewStream()
+	stream.PutNonce(12)
+	stream.PutRandomPadding(4, 16)
+	stream.PutInt(42)
+
+	assert.Equal(t, len(stream.GetNonce(12)), 12)
+	stream.SkipPadding()
+	assert.Equal(t, stream.GetInt(), int32(42))
+	assert.Assert(t, len(stream.Buffer) >= 12+5+4 && len(stream.Buffer) <= 12+17+4)
+	assert.NilError(t, stream.Error())
+
+	// The nonce does not share the buffer of the stream.
+	stream = NewStream()
+	stream.PutNonce(8)
+	nonce := stream.GetNonce(8)
+	expected := append([]byte{}, nonce...)
+	stream.Reset()
+	stream.PutLong(0)
+	assert.DeepEqual(t, nonce, expected)
+
+	// The padding is prefixed as unsigned varint whatever the string prefix of the stream.
+	stream = NewStream()
+	stream.SetStringPrefix(PrefixUnsignedShort)
+	stream.PutRandomPadding(3, 3)
+	stream.PutByte(7)
+	assert.Equal(t, len(stream.Buffer), 5)
+	stream.SkipPadding()
+	assert.Equal(t, stream.GetByte(), byte(7))
+
+	stream = NewStream()
+	stream.PutRandomPadding(-2, 4)
+	assert.Assert(t, errors.Is(stream.Err(), ErrNegativeLength))
+	stream = NewStream()
+	stream.PutNonce(-1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrNegativeLength))
+	assert.Equal(t, len(stream.Buffer), 0)
+}
@@ -0,0 +1,101 @@
+package binutils
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// RateLimitedReader limits the rate at which bytes are read from a reader.
+// It allows bursts of up to one second worth of bytes.
+type RateLimitedReader struct {
+	reader io.Reader
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewRateLimitedReader returns a reader reading at most bytesPerSecond bytes per second from reader.
+// It panics if bytesPerSecond is not positive, as reads would never be allowed.
+func NewRateLimitedReader(reader io.Reader, bytesPerSecond int) *RateLimitedReader {
+	if bytesPerSecond <= 0 {
+		panic(fmt.Sprintf("binutils: invalid rate of %d bytes per second", bytesPerSecond))
+	}
+	return &RateLimitedReader{
+		reader: reader,
+		rate:   float64(bytesPerSecond),
+		tokens: float64(bytesPerSecond),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Read reads from the underlying reader, blocking until reading is allowed by the rate limit.
+func (reader *RateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return reader.reader.Read(p)
+	}
+	reader.refill()
+	if reader.tokens < 1 {
+		reader.sleep(time.Duration((1 - reader.tokens) / reader.rate * float64(time.Second)))
+		reader.refill()
+	}
+	if allowed := int(reader.tokens); len(p) > allowed {
+		p = p[:allowed]
+	}
+	n, err := reader.reader.Read(p)
+	reader.tokens -= float64(n)
+	return n, err
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (reader *RateLimitedReader) refill() {
+	now := reader.now()
+	reader.tokens += now.Sub(reader.last).Seconds() * reader.rate
+	if reader.tokens > reader.rate {
+		reader.tokens = reader.rate
+	}
+	reader.last = now
+}
+
+// SizeLimitedReader limits the amount of bytes read from a reader.
+// Reading past the limit returns ErrLimitExceeded, unlike io.LimitedReader which returns io.EOF.
+// The limit typically applies to a single frame, and is reset with Reset after reading each frame.
+type SizeLimitedReader struct {
+	reader io.Reader
+	max    int64
+	read   int64
+}
+
+// NewSizeLimitedReader returns a reader reading at most max bytes from reader.
+func NewSizeLimitedReader(reader io.Reader, max int64) *SizeLimitedReader {
+	return &SizeLimitedReader{reader: reader, max: max}
+}
+
+// Read reads from the underlying reader, returning ErrLimitExceeded once the limit was reached.
+func (reader *SizeLimitedReader) Read(p []byte) (int, error) {
+	remaining := reader.max - reader.read
+	if remaining <= 0 {
+		return 0, ErrLimitExceeded
+	}
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := reader.reader.Read(p)
+	reader.read += int64(n)
+	return n, err
+}
+
+// Reset resets the amount of bytes read, typically after reading a frame.
+func (reader *SizeLimitedReader) Reset() {
+	reader.read = 0
+}
+
+// BytesRead returns the amount of bytes read since the last reset.
+func (reader *SizeLimitedReader) BytesRead() int64 {
+	return reader.read
+}
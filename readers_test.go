@@ -0,0 +1,55 @@
+package binutils
+
+import (
+	"bytes"
+	"fmt"
+	"gotest.tools/assert"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedReader(t *testing.T) {
+	clock := time.Unix(0, 0)
+	var slept time.Duration
+
+	reader := NewRateLimitedReader(bytes.NewReader(make([]byte, 250)), 100)
+	reader.last = clock
+	reader.now = func() time.Time { return clock }
+	reader.sleep = func(d time.Duration) {
+		slept += d
+		clock = clock.Add(d)
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, len(data), 250)
+	assert.Assert(t, slept >= 1490*time.Millisecond && slept <= 1510*time.Millisecond, "slept %v", slept)
+
+	for _, rate := range []int{0, -1} {
+		func() {
+			defer func() {
+				assert.Equal(t, recover(), fmt.Sprintf("binutils: invalid rate of %d bytes per second", rate))
+			}()
+			NewRateLimitedReader(bytes.NewReader(nil), rate)
+		}()
+	}
+}
+
+func TestSizeLimitedReader(t *testing.T) {
+	reader := NewSizeLimitedReader(bytes.NewReader(make([]byte, 10)), 4)
+
+	buffer := make([]byte, 8)
+	n, err := reader.Read(buffer)
+	assert.NilError(t, err)
+	assert.Equal(t, n, 4)
+
+	_, err = reader.Read(buffer)
+	assert.Equal(t, err, ErrLimitExceeded)
+
+	reader.Reset()
+	n, err = reader.Read(buffer)
+	assert.NilError(t, err)
+	assert.Equal(t, n, 4)
+	assert.Equal(t, reader.BytesRead(), int64(4))
+}
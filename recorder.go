@@ -0,0 +1,123 @@
+package binutils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedFrame is a frame kept by a Recorder.
+type RecordedFrame struct {
+	Time time.Time
+	// Outbound is set for frames written, and unset for frames read.
+	Outbound bool
+	Data     []byte
+}
+
+// FrameDecoder decodes a frame into a description for dumps of a Recorder, such as a tree of its fields.
+type FrameDecoder func(frame []byte) (string, error)
+
+// Recorder keeps the last frames of a connection in a ring, to dump the exact bytes leading up to an error,
+// such as a decoding failure or a crash. Recording a frame copies it, so a recorder is kept per connection.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	frames  []RecordedFrame
+	next    int
+	total   uint64
+	decoder FrameDecoder
+}
+
+// NewRecorder returns a new recorder keeping the last size frames.
+func NewRecorder(size int) *Recorder {
+	return &Recorder{frames: make([]RecordedFrame, 0, max(size, 1))}
+}
+
+// SetDecoder sets the decoder describing frames in dumps, in addition to their bytes.
+func (recorder *Recorder) SetDecoder(decoder FrameDecoder) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.decoder = decoder
+}
+
+// Record records a copy of the frame, replacing the oldest frame once the recorder is full.
+func (recorder *Recorder) Record(outbound bool, frame []byte) {
+	recorded := RecordedFrame{time.Now(), outbound, append([]byte{}, frame...)}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.total++
+	if len(recorder.frames) < cap(recorder.frames) {
+		recorder.frames = append(recorder.frames, recorded)
+		return
+	}
+	recorder.frames[recorder.next] = recorded
+	recorder.next = (recorder.next + 1) % len(recorder.frames)
+}
+
+// Frames returns the frames kept, oldest first.
+func (recorder *Recorder) Frames() []RecordedFrame {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append(append([]RecordedFrame{}, recorder.frames[recorder.next:]...), recorder.frames[:recorder.next]...)
+}
+
+// Total returns the amount of frames recorded, including the frames no longer kept.
+func (recorder *Recorder) Total() uint64 {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.total
+}
+
+// Reset discards the frames kept.
+func (recorder *Recorder) Reset() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.frames = recorder.frames[:0]
+	recorder.next = 0
+	recorder.total = 0
+}
+
+// Dump writes the frames kept to the writer, oldest first, as hex dump followed by their description
+// if a decoder is set.
+func (recorder *Recorder) Dump(writer io.Writer) error {
+	frames := recorder.Frames()
+	recorder.mu.Lock()
+	decoder, total := recorder.decoder, recorder.total
+	recorder.mu.Unlock()
+
+	first := total - uint64(len(frames))
+	for i, frame := range frames {
+		direction := "inbound"
+		if frame.Outbound {
+			direction = "outbound"
+		}
+		if _, err := fmt.Fprintf(writer, "frame %d, %s, %d bytes at %s:\n%s", first+uint64(i), direction,
+			len(frame.Data), frame.Time.Format(time.RFC3339Nano), hex.Dump(frame.Data)); err != nil {
+			return err
+		}
+		if decoder == nil {
+			continue
+		}
+		description, err := decoder(frame.Data)
+		if err != nil {
+			description = "cannot decode: " + err.Error()
+		}
+		if _, err := fmt.Fprintf(writer, "%s\n", description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpError writes the error followed by the frames kept to the writer if the error is not nil,
+// and returns the error, so that failures of a connection can be dumped where they are returned.
+func (recorder *Recorder) DumpError(writer io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	fmt.Fprintf(writer, "error: %v\n", err)
+	recorder.Dump(writer)
+	return err
+}
@@ -0,0 +1,57 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(2)
+	frame := []byte{1}
+	recorder.Record(false, frame)
+	frame[0] = 9
+	recorder.Record(true, []byte{2})
+	recorder.Record(false, []byte{3, 4})
+
+	frames := recorder.Frames()
+	assert.Equal(t, len(frames), 2)
+	assert.DeepEqual(t, frames[0].Data, []byte{2})
+	assert.Assert(t, frames[0].Outbound)
+	assert.DeepEqual(t, frames[1].Data, []byte{3, 4})
+	assert.Equal(t, recorder.Total(), uint64(3))
+
+	recorder.SetDecoder(func(frame []byte) (string, error) {
+		if len(frame) > 1 {
+			return "", errors.New("too long")
+		}
+		return "ping", nil
+	})
+	var dump strings.Builder
+	err := errors.New("connection reset")
+	assert.Equal(t, recorder.DumpError(&dump, err), err)
+	assert.Assert(t, strings.HasPrefix(dump.String(), "error: connection reset\nframe 1, outbound, 1 bytes at "), dump.String())
+	assert.Assert(t, strings.Contains(dump.String(), "|.|\nping\nframe 2, inbound, 2 bytes"), dump.String())
+	assert.Assert(t, strings.HasSuffix(dump.String(), "cannot decode: too long\n"), dump.String())
+	assert.NilError(t, recorder.DumpError(&dump, nil))
+
+	recorder.Reset()
+	assert.Equal(t, len(recorder.Frames()), 0)
+}
+
+func TestFramerRecorder(t *testing.T) {
+	var written bytes.Buffer
+	recorder := NewRecorder(4)
+	framer := NewFramer(testConn{&written, &written}, PrefixUnsignedVarInt)
+	framer.SetRecorder(recorder)
+	assert.NilError(t, framer.WriteFrame([]byte("hello")))
+	_, err := framer.ReadFrame()
+	assert.NilError(t, err)
+
+	frames := recorder.Frames()
+	assert.Equal(t, len(frames), 2)
+	assert.Assert(t, frames[0].Outbound && !frames[1].Outbound)
+	assert.DeepEqual(t, frames[1].Data, []byte("hello"))
+}
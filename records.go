@@ -0,0 +1,31 @@
+package binutils
+
+import (
+	"iter"
+)
+
+// Records returns an iterator over the length prefixed records left in the buffer, consuming them.
+// Each record is yielded as a sub-stream bounded to the record, sharing the backing array of the stream.
+// Iteration stops at the end of the buffer, or once an error is set on the stream,
+// such as when a record is longer than the bytes left.
+func (stream *Stream) Records(prefix PrefixType) iter.Seq[*Stream] {
+	return func(yield func(*Stream) bool) {
+		for stream.err == nil && (stream.Offset < len(stream.Buffer) || stream.fill("Records", 1)) {
+			offset := stream.Offset
+			length := stream.GetPrefix(prefix)
+			if stream.err != nil {
+				return
+			}
+			if !stream.available("Records", length, 1) {
+				stream.Offset = offset
+				stream.SetError(&StreamError{"Records", offset, ErrUnexpectedEOF})
+				return
+			}
+			record := stream.child(stream.Buffer[stream.Offset : stream.Offset+length])
+			stream.Offset += length
+			if !yield(record) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestRecords(t *testing.T) {
+	stream := NewStream()
+	for _, record := range []string{"a", "bc", "def"} {
+		stream.PutPrefix(PrefixLittleUnsignedShort, len(record))
+		stream.PutBytes([]byte(record))
+	}
+
+	var records []string
+	for record := range stream.Records(PrefixLittleUnsignedShort) {
+		records = append(records, string(record.Get(len(record.Buffer))))
+	}
+	assert.DeepEqual(t, records, []string{"a", "bc", "def"})
+	assert.NilError(t, stream.Error())
+}
+
+func TestRecordsTruncated(t *testing.T) {
+	stream := NewStream()
+	stream.PutPrefix(PrefixUnsignedInt, 10)
+	stream.PutBytes([]byte("short"))
+
+	for range stream.Records(PrefixUnsignedInt) {
+		t.Fatal("unexpected record")
+	}
+	assert.Assert(t, errors.Is(stream.Error(), ErrUnexpectedEOF))
+	assert.Equal(t, stream.Offset, 0)
+}
+
+func TestPrefixNegative(t *testing.T) {
+	stream := NewStream()
+	stream.PutPrefix(PrefixInt, -1)
+	assert.Equal(t, stream.GetPrefix(PrefixInt), 0)
+	assert.Assert(t, errors.Is(stream.Error(), ErrNegativeLength))
+}
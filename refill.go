@@ -0,0 +1,77 @@
+package binutils
+
+import (
+	"io"
+	"math"
+)
+
+// maxEmptyRefills is the amount of reads returning no bytes and no error after which a reader stream gives up.
+const maxEmptyRefills = 100
+
+// RefillFunc returns more bytes for a stream that ran out of bytes to read. The bytes are copied into the stream.
+// It returns an error, such as io.EOF, if no more bytes are available. Returning no bytes and no error
+// also stops the refill, so that a parser may fail with ErrUnexpectedEOF and be retried once more data arrived.
+type RefillFunc func() ([]byte, error)
+
+// NewRefillStream returns a new stream which calls refill for more bytes when a read needs more bytes than are left,
+// before failing with ErrUnexpectedEOF.
+func NewRefillStream(refill RefillFunc) *Stream {
+	return &Stream{Buffer: []byte{}, refill: refill}
+}
+
+// NewReaderStream returns a new stream which reads more bytes from the reader when a read needs more bytes than are left.
+func NewReaderStream(reader io.Reader) *Stream {
+	chunk := make([]byte, readFromChunk)
+	return NewRefillStream(func() ([]byte, error) {
+		for i := 0; i < maxEmptyRefills; i++ {
+			n, err := reader.Read(chunk)
+			if n > 0 {
+				return chunk[:n], nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, io.ErrNoProgress
+	})
+}
+
+// SetRefill sets the function called for more bytes when a read needs more bytes than are left. nil disables refilling.
+// The refilled bytes are appended to the buffer and count towards the write limit, so that Compact should be called
+// on long-lived streams to drop the bytes already read.
+func (stream *Stream) SetRefill(refill RefillFunc) {
+	stream.refill = refill
+}
+
+// fill calls the refill function of the stream until at least n bytes are left for the operation op.
+// It returns false if the refill function ran dry first. Errors other than io.EOF are set on the stream.
+func (stream *Stream) fill(op string, n int) bool {
+	if stream.refill == nil || stream.Offset < 0 {
+		return false
+	}
+	for len(stream.Buffer)-stream.Offset < n {
+		more, err := stream.refill()
+		if len(more) > 0 {
+			if !stream.writable(op, len(more)) {
+				return false
+			}
+			stream.Buffer = append(stream.Buffer, more...)
+			continue
+		}
+		if err != nil && err != io.EOF {
+			stream.SetError(&StreamError{op, stream.Offset, err})
+		}
+		return false
+	}
+	return true
+}
+
+// available checks if count elements of at least size bytes each are left to read, refilling the buffer
+// for the operation op if needed. Counts too large to ever be read are rejected without overflowing.
+// Errors other than io.EOF are set on the stream by the refill, while callers set their own error otherwise.
+func (stream *Stream) available(op string, count, size int) bool {
+	if count < 0 || count > math.MaxInt/size {
+		return false
+	}
+	return count*size <= len(stream.Buffer)-stream.Offset || stream.fill(op, count*size)
+}
@@ -0,0 +1,95 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"gotest.tools/assert"
+)
+
+func TestReaderStream(t *testing.T) {
+	source := NewStream()
+	source.PutInt(-7)
+	source.PutVarInt(300)
+	source.PutString("hello")
+	source.PutCString("world")
+
+	stream := NewReaderStream(iotest.OneByteReader(bytes.NewReader(source.Buffer)))
+	assert.Equal(t, stream.GetInt(), int32(-7))
+	assert.Equal(t, len(stream.Buffer), 4)
+	assert.Equal(t, stream.GetVarInt(), int32(300))
+	assert.Equal(t, stream.GetString(), "hello")
+	assert.Equal(t, stream.GetCString(), "world")
+	assert.NilError(t, stream.Err())
+
+	stream.GetByte()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
+
+func TestReaderStreamCounts(t *testing.T) {
+	source := NewStream()
+	source.PutUnsignedIntSlice([]uint32{1, 2, 3})
+	source.PutGroupVarInts([]uint32{4, 5, 300})
+	source.PutPrefix(PrefixUnsignedShort, 2)
+	source.PutBytes([]byte("ab"))
+
+	// Counts are checked against the bytes left after refilling, not the bytes buffered.
+	stream := NewReaderStream(iotest.OneByteReader(bytes.NewReader(source.Buffer)))
+	assert.DeepEqual(t, stream.GetUnsignedIntSlice(), []uint32{1, 2, 3})
+	assert.DeepEqual(t, stream.GetGroupVarInts(), []uint32{4, 5, 300})
+	records := 0
+	for record := range stream.Records(PrefixUnsignedShort) {
+		assert.DeepEqual(t, record.Buffer, []byte("ab"))
+		records++
+	}
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, records, 1)
+
+	format := TLVFormat{Type: PrefixByte, Length: PrefixUnsignedShort}
+	source.Reset()
+	source.PutTLV(format, 7, []byte("cd"))
+	stream = NewReaderStream(iotest.OneByteReader(bytes.NewReader(source.Buffer)))
+	for typ, value := range stream.TLVs(format) {
+		assert.Equal(t, typ, uint32(7))
+		assert.DeepEqual(t, value.Buffer, []byte("cd"))
+	}
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, stream.Offset, len(source.Buffer))
+}
+
+func TestRefillStreamIncremental(t *testing.T) {
+	var pending []byte
+	stream := NewRefillStream(func() ([]byte, error) {
+		more := pending
+		pending = nil
+		return more, nil
+	})
+
+	pending = []byte{0x00, 0x01}
+	assert.Equal(t, stream.GetInt(), int32(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream.ClearError()
+	pending = []byte{0x02, 0x03}
+	assert.Equal(t, stream.GetInt(), int32(0x010203))
+	assert.NilError(t, stream.Err())
+}
+
+func TestRefillStreamErrors(t *testing.T) {
+	stream := NewReaderStream(iotest.ErrReader(io.ErrClosedPipe))
+	stream.GetByte()
+	assert.Assert(t, errors.Is(stream.Err(), io.ErrClosedPipe))
+
+	stream = NewReaderStream(bytes.NewReader(make([]byte, 16)))
+	stream.SetWriteLimit(8)
+	stream.Get(12)
+	assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+
+	stream = NewStream()
+	stream.SetRefill(nil)
+	stream.GetVarLong()
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
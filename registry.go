@@ -0,0 +1,71 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Registry maps packet IDs to factories of packets.
+// Packets are written prefixed with their ID as unsigned varint.
+type Registry struct {
+	factories map[uint32]func() Serializable
+	ids       map[reflect.Type]uint32
+}
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{make(map[uint32]func() Serializable), make(map[reflect.Type]uint32)}
+}
+
+// Register registers the factory for packets with the given ID.
+func (registry *Registry) Register(id uint32, factory func() Serializable) {
+	registry.factories[id] = factory
+	registry.ids[reflect.TypeOf(factory())] = id
+}
+
+// New returns a new packet with the given ID, and whether the ID was registered.
+func (registry *Registry) New(id uint32) (Serializable, bool) {
+	factory, ok := registry.factories[id]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// ID returns the ID of the packet, and whether its type was registered.
+func (registry *Registry) ID(packet Serializable) (uint32, bool) {
+	id, ok := registry.ids[reflect.TypeOf(packet)]
+	return id, ok
+}
+
+// Encode writes the packet prefixed with its ID to the stream.
+func (registry *Registry) Encode(stream *Stream, packet Serializable) error {
+	defer stream.TraceEncode("Registry.Encode")()
+	id, ok := registry.ID(packet)
+	if !ok {
+		return fmt.Errorf("binutils: packet type %T is not registered", packet)
+	}
+	stream.PutUnsignedVarInt(id)
+	packet.Encode(stream)
+	return stream.Error()
+}
+
+// Decode reads an ID prefixed packet from the stream.
+// A *PacketError wrapping ErrUnknownPacket is set on the stream and returned if the ID was not registered.
+func (registry *Registry) Decode(stream *Stream) (uint32, Serializable, error) {
+	defer stream.TraceDecode("Registry.Decode")()
+	id := stream.GetUnsignedVarInt()
+	if stream.err != nil {
+		return 0, nil, stream.err
+	}
+	packet, ok := registry.New(id)
+	if !ok {
+		err := &PacketError{id, ErrUnknownPacket}
+		stream.SetError(err)
+		return id, nil, err
+	}
+	stream.decode("Decode", packet)
+	return id, packet, stream.err
+}
@@ -0,0 +1,41 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type panickingPacket struct{}
+
+func (packet *panickingPacket) Encode(stream *Stream) {}
+
+func (packet *panickingPacket) Decode(stream *Stream) {
+	stream.GetByte()
+	panic("broken decoder")
+}
+
+func TestStreamRecoverPanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(1, func() Serializable { return &panickingPacket{} })
+
+	stream := NewStream()
+	stream.SetBuffer(b(0x01, 0x00))
+	stream.SetRecoverPanics(true)
+
+	_, _, err := registry.Decode(stream)
+	var streamErr *StreamError
+	assert.Assert(t, errors.As(err, &streamErr))
+	assert.Equal(t, streamErr.Op, "Decode")
+	assert.Equal(t, streamErr.Offset, 1)
+	assert.Equal(t, stream.GetOffset(), 1)
+
+	stream.SetRecoverPanics(false)
+	stream.ClearError()
+	defer func() {
+		assert.Equal(t, recover(), "broken decoder")
+	}()
+	registry.Decode(&Stream{Buffer: b(0x01, 0x00)})
+}
@@ -0,0 +1,30 @@
+package binutils_test
+
+import (
+	"testing"
+
+	"github.com/irmine/binutils"
+	"github.com/irmine/binutils/binutilstest"
+)
+
+// TestRandomRoundTrips checks the primitives of streams against random values.
+func TestRandomRoundTrips(t *testing.T) {
+	type S = binutils.Stream
+	type R = binutilstest.Rand
+	binutilstest.CheckRandomRoundTrips(t, (*R).Bool, (*S).PutBool, (*S).GetBool)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Byte, (*S).PutByte, (*S).GetByte)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int16, (*S).PutShort, (*S).GetShort)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Uint16, (*S).PutLittleUnsignedShort, (*S).GetLittleUnsignedShort)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int32, (*S).PutInt, (*S).GetInt)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int32, (*S).PutVarInt, (*S).GetVarInt)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Uint32, (*S).PutUnsignedVarInt, (*S).GetUnsignedVarInt)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int64, (*S).PutLittleLong, (*S).GetLittleLong)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int64, (*S).PutVarLong, (*S).GetVarLong)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Uint64, (*S).PutUnsignedVarLong, (*S).GetUnsignedVarLong)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Triad, (*S).PutLittleTriad, (*S).GetLittleTriad)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Int24, (*S).PutInt24, (*S).GetInt24)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Float32, (*S).PutFloat, (*S).GetFloat)
+	binutilstest.CheckRandomRoundTrips(t, (*R).Float64, (*S).PutLittleDouble, (*S).GetLittleDouble)
+	binutilstest.CheckRandomRoundTrips(t, func(r *R) string { return r.String(64) }, (*S).PutString, (*S).GetString)
+	binutilstest.CheckRandomRoundTrips(t, func(r *R) []byte { return r.Bytes(300) }, (*S).PutLengthPrefixedBytes, (*S).GetLengthPrefixedBytes)
+}
@@ -0,0 +1,84 @@
+package binutils
+
+// SafeLimits are the limits a stream enforces while decoding, bundled so that fuzz targets and servers decoding
+// untrusted input can opt in to all of them at once. Zero fields disable the respective limit, or use its default.
+type SafeLimits struct {
+	// MaxVarIntBytes and MaxVarLongBytes are the maximum encoded sizes of varints, see SetMaxVarIntBytes.
+	MaxVarIntBytes  int
+	MaxVarLongBytes int
+	// MaxStringLength is the maximum length of strings and length prefixed bytes, see SetMaxStringLength.
+	MaxStringLength int
+	// MaxSliceLength is the maximum element count of slices, see SetMaxSliceLength.
+	MaxSliceLength int
+	// MaxDepth is the maximum nesting depth of structures, see SetMaxDepth.
+	MaxDepth int
+	// MaxDecompressedSize is the maximum size of decompressed data, see SetMaxDecompressedSize.
+	MaxDecompressedSize int
+}
+
+// DefaultSafeLimits are conservative limits for decoding untrusted input, which still fit typical network protocols.
+var DefaultSafeLimits = SafeLimits{
+	MaxVarIntBytes:      DefaultMaxVarIntBytes,
+	MaxVarLongBytes:     DefaultMaxVarLongBytes,
+	MaxStringLength:     1 << 20,
+	MaxSliceLength:      1 << 16,
+	MaxDepth:            64,
+	MaxDecompressedSize: 16 << 20,
+}
+
+// NewSafeStream returns a new stream reading the buffer with DefaultSafeLimits, as set by SetSafeLimits.
+func NewSafeStream(buffer []byte) *Stream {
+	stream := &Stream{Buffer: buffer}
+	stream.SetSafeLimits(DefaultSafeLimits)
+	return stream
+}
+
+// SetSafeLimits sets the decoding limits of the stream, and enables panic recovery for decoders as described for
+// SetRecoverPanics. Reads exceeding a limit set an error matching ErrLimitExceeded, or ErrMalformedVarInt for varints,
+// instead of allocating for hostile lengths or panicking.
+func (stream *Stream) SetSafeLimits(limits SafeLimits) {
+	stream.SetMaxVarIntBytes(limits.MaxVarIntBytes)
+	stream.SetMaxVarLongBytes(limits.MaxVarLongBytes)
+	stream.SetMaxStringLength(limits.MaxStringLength)
+	stream.SetMaxSliceLength(limits.MaxSliceLength)
+	stream.SetMaxDepth(limits.MaxDepth)
+	stream.SetMaxDecompressedSize(limits.MaxDecompressedSize)
+	stream.SetRecoverPanics(true)
+}
+
+// GetSafeLimits returns the decoding limits of the stream.
+func (stream *Stream) GetSafeLimits() SafeLimits {
+	return SafeLimits{
+		MaxVarIntBytes:      stream.GetMaxVarIntBytes(),
+		MaxVarLongBytes:     stream.GetMaxVarLongBytes(),
+		MaxStringLength:     stream.GetMaxStringLength(),
+		MaxSliceLength:      stream.GetMaxSliceLength(),
+		MaxDepth:            stream.GetMaxDepth(),
+		MaxDecompressedSize: stream.GetMaxDecompressedSize(),
+	}
+}
+
+// SetMaxSliceLength sets the maximum element count of slices read from the stream, by the slice functions, Marshal,
+// schemas and Elements. Larger counts are rejected with a *LengthError before anything is allocated.
+// A length of 0 or less disables the limit, which is the default.
+func (stream *Stream) SetMaxSliceLength(length int) {
+	stream.maxSliceLength = length
+}
+
+// GetMaxSliceLength returns the maximum element count of slices read from the stream, or 0 if there is no limit.
+func (stream *Stream) GetMaxSliceLength() int {
+	if stream.maxSliceLength <= 0 {
+		return 0
+	}
+	return stream.maxSliceLength
+}
+
+// checkCount checks if the element count of a slice read by the operation op at offset is within the slice limit
+// of the stream, setting a *LengthError on the stream otherwise.
+func (stream *Stream) checkCount(op string, offset, count int) bool {
+	if stream.maxSliceLength > 0 && count > stream.maxSliceLength {
+		stream.SetError(&StreamError{op, offset, &LengthError{count, stream.maxSliceLength}})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestSafeLimits(t *testing.T) {
+	stream := NewStream()
+	stream.PutIntSlice(make([]int32, 3))
+	stream.PutGroupVarInts(make([]uint32, 3))
+	stream.PutString("steve")
+
+	safe := NewSafeStream(stream.Buffer)
+	assert.DeepEqual(t, safe.GetSafeLimits(), DefaultSafeLimits)
+	assert.Assert(t, safe.RecoversPanics())
+	safe.SetSafeLimits(SafeLimits{MaxSliceLength: 2, MaxStringLength: 4})
+	assert.Equal(t, safe.GetMaxVarIntBytes(), DefaultMaxVarIntBytes)
+	assert.Equal(t, safe.GetMaxDepth(), DefaultMaxDepth)
+
+	assert.Assert(t, safe.GetIntSlice() == nil)
+	var err *LengthError
+	assert.Assert(t, errors.As(safe.Err(), &err))
+	assert.Equal(t, *err, LengthError{3, 2})
+
+	safe.ClearError()
+	safe.Offset = 13
+	assert.Assert(t, safe.GetGroupVarInts() == nil)
+	assert.Assert(t, errors.Is(safe.Err(), ErrLimitExceeded))
+
+	safe.ClearError()
+	safe.Offset = 19
+	assert.Equal(t, safe.GetString(), "")
+	assert.Assert(t, errors.Is(safe.Err(), ErrLimitExceeded))
+}
+
+func TestSafeLimitsDecompressedSize(t *testing.T) {
+	stream := NewStream()
+	stream.PutCompressed(make([]byte, 100), CompressionZlib)
+	stream.PutCompressed(make([]byte, 101), CompressionGzip)
+
+	safe := NewSafeStream(stream.Buffer)
+	safe.SetSafeLimits(SafeLimits{MaxDecompressedSize: 100})
+	assert.Equal(t, safe.GetMaxDecompressedSize(), 100)
+	assert.Equal(t, len(safe.GetCompressed(CompressionZlib)), 100)
+	assert.Assert(t, safe.GetCompressed(CompressionGzip) == nil)
+	assert.Assert(t, errors.Is(safe.Err(), ErrLimitExceeded))
+
+	safe.SetMaxDecompressedSize(0)
+	assert.Equal(t, safe.GetMaxDecompressedSize(), DefaultMaxDecompressedSize)
+}
+
+// FuzzSafeStream checks that reads of crafted input set errors on the stream rather than panicking.
+func FuzzSafeStream(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x05, 's', 't', 'e', 'v', 'e', 0x02, 0x00, 0x01, 0x00, 0x02})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0x0f, 0x80})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		stream := NewSafeStream(data)
+		for stream.Err() == nil && stream.Remaining() > 0 {
+			op := stream.GetByte()
+			switch op % 12 {
+			case 0:
+				stream.GetString()
+			case 1:
+				stream.GetVarLong()
+			case 2:
+				stream.GetUnsignedVarInt()
+			case 3:
+				stream.GetLengthPrefixedBytes()
+			case 4:
+				stream.GetIntSlice()
+			case 5:
+				stream.GetGroupVarInts()
+			case 6:
+				stream.GetTriad()
+			case 7:
+				stream.GetCString()
+			case 8:
+				stream.GetLEB128()
+			case 9:
+				stream.GetBitsAt(stream.Offset, uint(op>>4), uint(op>>2))
+			case 10:
+				stream.GetFixedString(int(op))
+			case 11:
+				stream.Sub(int(stream.GetVarInt()))
+			}
+		}
+	})
+}
@@ -0,0 +1,446 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// FieldType is the type of a field of a Schema.
+type FieldType int
+
+const (
+	TypeBool FieldType = iota
+	TypeInt8
+	TypeUint8
+	TypeInt16
+	TypeUint16
+	TypeInt32
+	TypeUint32
+	TypeInt64
+	TypeUint64
+	TypeFloat32
+	TypeFloat64
+	TypeVarInt
+	TypeVarLong
+	TypeUnsignedVarInt
+	TypeUnsignedVarLong
+	TypeTriad
+	TypeInt24
+	// TypeString is a string prefixed with its length, using the string prefix type of the stream.
+	TypeString
+	// TypeBytes is a byte slice prefixed with its length, using the string prefix type of the stream.
+	TypeBytes
+	// TypeStruct is a nested schema. Fields of this type are added with Struct.
+	TypeStruct
+)
+
+// fieldTypeNames holds the names of the field types, which match the wire types of struct tags.
+var fieldTypeNames = [...]string{"bool", "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64",
+	"float32", "float64", "varint", "varlong", "uvarint", "uvarlong", "triad", "int24", "string", "bytes", "struct"}
+
+// fieldTypeWires maps the numeric field types to wire types.
+var fieldTypeWires = [...]wireType{wireBool, wireInt8, wireUint8, wireInt16, wireUint16, wireInt32, wireUint32, wireInt64, wireUint64,
+	wireFloat32, wireFloat64, wireVarInt, wireVarLong, wireUnsignedVarInt, wireUnsignedVarLong, wireTriad, wireInt24}
+
+// fieldTypeBits holds the widths in bits of the integer field types.
+var fieldTypeBits = [...]uint{TypeInt8: 8, TypeUint8: 8, TypeInt16: 16, TypeUint16: 16, TypeInt32: 32, TypeUint32: 32,
+	TypeInt64: 64, TypeUint64: 64, TypeVarInt: 32, TypeVarLong: 64, TypeUnsignedVarInt: 32, TypeUnsignedVarLong: 64,
+	TypeTriad: 24, TypeInt24: 24}
+
+// String returns the name of the field type.
+func (t FieldType) String() string {
+	if t >= 0 && int(t) < len(fieldTypeNames) {
+		return fieldTypeNames[t]
+	}
+	return fmt.Sprintf("FieldType(%d)", int(t))
+}
+
+// ParseFieldType returns the field type with the given name, such as "varint" or "string".
+func ParseFieldType(name string) (FieldType, error) {
+	for i, typeName := range fieldTypeNames {
+		if typeName == name {
+			return FieldType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("binutils: unknown field type %q", name)
+}
+
+// signed checks if the field type is a signed integer type.
+func (t FieldType) signed() bool {
+	switch t {
+	case TypeInt8, TypeInt16, TypeInt32, TypeInt64, TypeVarInt, TypeVarLong, TypeInt24:
+		return true
+	}
+	return false
+}
+
+// fits checks if an integer, given in two's complement as u, fits the integer field type.
+func (t FieldType) fits(u uint64, negative bool) bool {
+	bits := fieldTypeBits[t]
+	switch {
+	case !t.signed():
+		return !negative && (bits == 64 || u < 1<<bits)
+	case negative:
+		return bits == 64 || int64(u) >= -1<<(bits-1)
+	}
+	return u < 1<<(bits-1)
+}
+
+// schemaField is a field of a schema.
+type schemaField struct {
+	name   string
+	t      FieldType
+	array  bool
+	schema *Schema
+}
+
+// Schema describes the fields of a packet at runtime, for protocols defined by data, such as JSON files,
+// rather than by Go structs. Fields are encoded in order like struct fields by Marshal, in the byte order of the stream.
+// Arrays are prefixed with their count, using the string prefix type of the stream.
+//
+// A schema is built by chaining calls:
+//
+//	schema := NewSchema().Field("id", TypeVarInt).Field("name", TypeString).StructArray("items", item)
+type Schema struct {
+	fields     []schemaField
+	validators []schemaValidator
+}
+
+// NewSchema returns a new schema without fields.
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Field adds a field of the type. It panics if the type is TypeStruct, which is added with Struct.
+func (schema *Schema) Field(name string, t FieldType) *Schema {
+	if t < 0 || t >= TypeStruct {
+		panic(fmt.Sprintf("binutils: invalid field type %s for field %s", t, name))
+	}
+	schema.fields = append(schema.fields, schemaField{name: name, t: t})
+	return schema
+}
+
+// Array adds a field holding an array of values of the type.
+func (schema *Schema) Array(name string, t FieldType) *Schema {
+	schema.Field(name, t)
+	schema.fields[len(schema.fields)-1].array = true
+	return schema
+}
+
+// Struct adds a field holding a nested schema.
+func (schema *Schema) Struct(name string, nested *Schema) *Schema {
+	schema.fields = append(schema.fields, schemaField{name: name, t: TypeStruct, schema: nested})
+	return schema
+}
+
+// StructArray adds a field holding an array of values of a nested schema.
+func (schema *Schema) StructArray(name string, nested *Schema) *Schema {
+	schema.fields = append(schema.fields, schemaField{name: name, t: TypeStruct, array: true, schema: nested})
+	return schema
+}
+
+// Encode returns the encoding of the values, big endian, with the values of the fields mapped by their names.
+// See Put for the values accepted.
+func (schema *Schema) Encode(values map[string]interface{}) ([]byte, error) {
+	stream := NewStream()
+	if err := schema.Put(stream, values); err != nil {
+		return nil, err
+	}
+	return stream.Buffer, nil
+}
+
+// Decode decodes the buffer, big endian, and returns the values of the fields mapped by their names.
+// See Get for the types of the values.
+func (schema *Schema) Decode(buffer []byte) (map[string]interface{}, error) {
+	return schema.Get(&Stream{Buffer: buffer})
+}
+
+// FrameDecoder returns a decoder for recorders describing frames decoded with the schema, big endian,
+// as a tree of their fields in schema order. Bytes left after the fields are reported as error.
+func (schema *Schema) FrameDecoder() FrameDecoder {
+	return func(frame []byte) (string, error) {
+		stream := &Stream{Buffer: frame}
+		values, err := schema.Get(stream)
+		if err == nil && stream.Remaining() > 0 {
+			err = errTrailingBytes
+		}
+		if err != nil {
+			return "", err
+		}
+		var tree strings.Builder
+		schema.writeTree(&tree, values, "")
+		return strings.TrimSuffix(tree.String(), "\n"), nil
+	}
+}
+
+// writeTree writes a line per field of the values, with the fields of nested schemas indented below their name.
+func (schema *Schema) writeTree(tree *strings.Builder, values map[string]interface{}, indent string) {
+	for _, field := range schema.fields {
+		v := values[field.name]
+		switch {
+		case field.t == TypeStruct && field.array:
+			fmt.Fprintf(tree, "%s%s:\n", indent, field.name)
+			for i, element := range v.([]interface{}) {
+				fmt.Fprintf(tree, "%s  [%d]:\n", indent, i)
+				field.schema.writeTree(tree, element.(map[string]interface{}), indent+"    ")
+			}
+		case field.t == TypeStruct:
+			fmt.Fprintf(tree, "%s%s:\n", indent, field.name)
+			field.schema.writeTree(tree, v.(map[string]interface{}), indent+"  ")
+		case field.t == TypeString && !field.array:
+			fmt.Fprintf(tree, "%s%s: %q\n", indent, field.name, v)
+		case field.t == TypeBytes && !field.array:
+			fmt.Fprintf(tree, "%s%s: [% x]\n", indent, field.name, v)
+		default:
+			fmt.Fprintf(tree, "%s%s: %v\n", indent, field.name, v)
+		}
+	}
+}
+
+// Put writes the values to the stream, with the values of the fields mapped by their names.
+// Numeric fields accept any Go number that fits the field type, including integral float64 values as decoded from JSON.
+// String and bytes fields accept strings and byte slices, arrays accept slices, and nested schemas accept maps.
+func (schema *Schema) Put(stream *Stream, values map[string]interface{}) error {
+	for _, field := range schema.fields {
+		v, ok := values[field.name]
+		if !ok {
+			return fmt.Errorf("binutils: missing value of field %s", field.name)
+		}
+		if err := field.put(stream, v); err != nil {
+			return fmt.Errorf("binutils: field %s: %w", field.name, err)
+		}
+	}
+	return nil
+}
+
+// Get reads the values of the fields from the stream, mapped by their names, and checks them with the validation
+// rules of the schema.
+// Signed integers are returned as int64, unsigned integers as uint64, floats as float64, bools as bool,
+// strings as string, bytes as []byte, arrays as []interface{} and nested schemas as map[string]interface{}.
+func (schema *Schema) Get(stream *Stream) (map[string]interface{}, error) {
+	offset := stream.Offset
+	values := make(map[string]interface{}, len(schema.fields))
+	for _, field := range schema.fields {
+		values[field.name] = field.get(stream)
+		if stream.err != nil {
+			return nil, stream.err
+		}
+	}
+	if !schema.validate(stream, offset, values) {
+		return nil, stream.err
+	}
+	return values, nil
+}
+
+// put writes the value of the field.
+func (field schemaField) put(stream *Stream, v interface{}) error {
+	if !field.array {
+		return field.putValue(stream, v)
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return fmt.Errorf("expected a slice, got %T", v)
+	}
+	stream.PutPrefix(stream.stringPrefix, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if err := field.putValue(stream, value.Index(i).Interface()); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// putValue writes a single value of the field type.
+func (field schemaField) putValue(stream *Stream, v interface{}) error {
+	switch field.t {
+	case TypeStruct:
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected a map[string]interface{}, got %T", v)
+		}
+		return field.schema.Put(stream, values)
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", v)
+		}
+		stream.PutString(s)
+		return nil
+	case TypeBytes:
+		b, ok := v.([]byte)
+		if !ok {
+			return fmt.Errorf("expected a []byte, got %T", v)
+		}
+		stream.PutPrefix(stream.stringPrefix, len(b))
+		stream.PutBytes(b)
+		return nil
+	}
+	u, f, err := field.number(v)
+	if err != nil {
+		return err
+	}
+	m := marshaler{stream: stream}
+	m.putNumber(fieldTypeWires[field.t], stream.endian, u, f)
+	return nil
+}
+
+// number converts a Go value to a number of the field type, returning integers as u and floats as f.
+func (field schemaField) number(v interface{}) (u uint64, f float64, err error) {
+	value := reflect.ValueOf(v)
+	switch {
+	case field.t == TypeBool:
+		if value.Kind() != reflect.Bool {
+			return 0, 0, fmt.Errorf("expected a bool, got %T", v)
+		}
+		if value.Bool() {
+			u = 1
+		}
+		return u, 0, nil
+	case field.t == TypeFloat32 || field.t == TypeFloat64:
+		switch {
+		case value.CanInt():
+			return 0, float64(value.Int()), nil
+		case value.CanUint():
+			return 0, float64(value.Uint()), nil
+		case value.CanFloat():
+			return 0, value.Float(), nil
+		}
+		return 0, 0, fmt.Errorf("expected a number, got %T", v)
+	}
+	negative := false
+	switch {
+	case value.CanInt():
+		u, negative = uint64(value.Int()), value.Int() < 0
+	case value.CanUint():
+		u = value.Uint()
+	case value.CanFloat():
+		f := value.Float()
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return 0, 0, fmt.Errorf("non-integral value %v for integer type %s", v, field.t)
+		}
+		// Floats outside of the range of int64 and uint64 cannot be converted.
+		if f < math.MinInt64 || f >= 1<<64 {
+			return 0, 0, fmt.Errorf("value %v out of range for type %s", v, field.t)
+		}
+		if f < 0 {
+			u, negative = uint64(int64(f)), true
+		} else {
+			u = uint64(f)
+		}
+	default:
+		return 0, 0, fmt.Errorf("expected a number, got %T", v)
+	}
+	if negative && !field.t.signed() {
+		return 0, 0, fmt.Errorf("negative value %v for unsigned type %s", v, field.t)
+	}
+	if !field.t.fits(u, negative) {
+		return 0, 0, fmt.Errorf("value %v out of range for type %s", v, field.t)
+	}
+	return u, 0, nil
+}
+
+// get reads the value of the field.
+func (field schemaField) get(stream *Stream) interface{} {
+	if !field.array {
+		return field.getValue(stream)
+	}
+	offset := stream.Offset
+	n := stream.GetPrefix(stream.stringPrefix)
+	if stream.err != nil || !stream.checkCount("GetSchema", offset, n) {
+		return nil
+	}
+	// Every element takes at least a byte, so larger counts cannot be valid.
+	if !stream.available("GetSchema", n, 1) {
+		stream.SetError(&StreamError{"GetSchema", offset, ErrUnexpectedEOF})
+		return nil
+	}
+	elements := make([]interface{}, 0, n)
+	for i := 0; i < n && stream.err == nil; i++ {
+		elements = append(elements, field.getValue(stream))
+	}
+	return elements
+}
+
+// getValue reads a single value of the field type.
+func (field schemaField) getValue(stream *Stream) interface{} {
+	switch field.t {
+	case TypeStruct:
+		values, _ := field.schema.Get(stream)
+		return values
+	case TypeString:
+		return stream.GetString()
+	case TypeBytes:
+		return stream.GetLengthPrefixedBytes()
+	}
+	m := marshaler{stream: stream}
+	u, f := m.getNumber(fieldTypeWires[field.t], stream.endian)
+	switch {
+	case field.t == TypeBool:
+		return u != 0
+	case field.t == TypeFloat32 || field.t == TypeFloat64:
+		return f
+	case field.t.signed():
+		return int64(u)
+	}
+	return u
+}
+
+// jsonSchemaField is the JSON form of a schema field.
+type jsonSchemaField struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Array  bool              `json:"array,omitempty"`
+	Fields []jsonSchemaField `json:"fields,omitempty"`
+}
+
+// toJSON returns the JSON form of the fields of the schema.
+func (schema *Schema) toJSON() []jsonSchemaField {
+	fields := make([]jsonSchemaField, len(schema.fields))
+	for i, field := range schema.fields {
+		fields[i] = jsonSchemaField{Name: field.name, Type: field.t.String(), Array: field.array}
+		if field.t == TypeStruct {
+			fields[i].Fields = field.schema.toJSON()
+		}
+	}
+	return fields
+}
+
+// fromJSON adds the fields in JSON form to the schema.
+func (schema *Schema) fromJSON(fields []jsonSchemaField) error {
+	for _, field := range fields {
+		t, err := ParseFieldType(field.Type)
+		if err != nil {
+			return fmt.Errorf("%w of field %s", err, field.Name)
+		}
+		var nested *Schema
+		if t == TypeStruct {
+			nested = NewSchema()
+			if err := nested.fromJSON(field.Fields); err != nil {
+				return err
+			}
+		}
+		schema.fields = append(schema.fields, schemaField{field.Name, t, field.Array, nested})
+	}
+	return nil
+}
+
+// MarshalJSON encodes the schema as a JSON array of fields with their name, type and, if set, array flag
+// and nested fields, for example [{"name":"id","type":"varint"},{"name":"tags","type":"string","array":true}].
+func (schema *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(schema.toJSON())
+}
+
+// UnmarshalJSON decodes a schema encoded by MarshalJSON, replacing the fields of the schema.
+func (schema *Schema) UnmarshalJSON(data []byte) error {
+	var fields []jsonSchemaField
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	schema.fields = nil
+	return schema.fromJSON(fields)
+}
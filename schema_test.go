@@ -0,0 +1,135 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"encoding/json"
+	"errors"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	item := NewSchema().Field("id", TypeUint16).Field("count", TypeUint8)
+	schema := NewSchema().
+		Field("id", TypeVarInt).
+		Field("name", TypeString).
+		Field("x", TypeFloat32).
+		Field("ok", TypeBool).
+		Field("raw", TypeBytes).
+		Array("tags", TypeString).
+		Struct("pos", NewSchema().Field("x", TypeInt32).Field("y", TypeInt32)).
+		StructArray("items", item)
+
+	buffer, err := schema.Encode(map[string]interface{}{
+		"id":    -3,
+		"name":  "steve",
+		"x":     1.5,
+		"ok":    true,
+		"raw":   []byte{0xde, 0xad},
+		"tags":  []string{"a", "b"},
+		"pos":   map[string]interface{}{"x": float64(1), "y": int8(-1)},
+		"items": []interface{}{map[string]interface{}{"id": 7, "count": uint(2)}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer[:7], b(0x05, 0x05, 's', 't', 'e', 'v', 'e'))
+
+	values, err := schema.Decode(buffer)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, values, map[string]interface{}{
+		"id":    int64(-3),
+		"name":  "steve",
+		"x":     1.5,
+		"ok":    true,
+		"raw":   []byte{0xde, 0xad},
+		"tags":  []interface{}{"a", "b"},
+		"pos":   map[string]interface{}{"x": int64(1), "y": int64(-1)},
+		"items": []interface{}{map[string]interface{}{"id": uint64(7), "count": uint64(2)}},
+	})
+
+	_, err = schema.Decode(buffer[:len(buffer)-1])
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+}
+
+func TestSchemaFrameDecoder(t *testing.T) {
+	point := NewSchema().Field("x", TypeInt16).Field("y", TypeInt16)
+	schema := NewSchema().Field("name", TypeString).Field("data", TypeBytes).Struct("origin", point).StructArray("path", point)
+	frame, err := schema.Encode(map[string]interface{}{
+		"name":   "steve",
+		"data":   []byte{1, 2},
+		"origin": map[string]interface{}{"x": 1, "y": -1},
+		"path":   []interface{}{map[string]interface{}{"x": 2, "y": 3}},
+	})
+	assert.NilError(t, err)
+	tree, err := schema.FrameDecoder()(frame)
+	assert.NilError(t, err)
+	assert.Equal(t, tree, `name: "steve"
+data: [01 02]
+origin:
+  x: 1
+  y: -1
+path:
+  [0]:
+    x: 2
+    y: 3`)
+
+	_, err = schema.FrameDecoder()(append(frame, 0))
+	assert.Equal(t, err, errTrailingBytes)
+}
+
+func TestSchemaErrors(t *testing.T) {
+	schema := NewSchema().Field("id", TypeUint16)
+	_, err := schema.Encode(map[string]interface{}{})
+	assert.ErrorContains(t, err, "missing value of field id")
+	_, err = schema.Encode(map[string]interface{}{"id": -1})
+	assert.ErrorContains(t, err, "field id: negative value")
+	_, err = schema.Encode(map[string]interface{}{"id": 1.5})
+	assert.ErrorContains(t, err, "non-integral value")
+	_, err = schema.Encode(map[string]interface{}{"id": "1"})
+	assert.ErrorContains(t, err, "expected a number")
+
+	for _, test := range []struct {
+		t     FieldType
+		value interface{}
+	}{
+		{TypeUint8, 300}, {TypeInt16, 70000.0}, {TypeInt8, -129}, {TypeTriad, 1 << 24}, {TypeInt24, -1<<23 - 1},
+		{TypeVarInt, int64(math.MaxInt32 + 1)}, {TypeUint64, 1e20}, {TypeInt64, uint64(math.MaxInt64 + 1)},
+	} {
+		_, err = NewSchema().Field("v", test.t).Encode(map[string]interface{}{"v": test.value})
+		assert.ErrorContains(t, err, "out of range", "%s %v", test.t, test.value)
+	}
+	for _, test := range []struct {
+		t     FieldType
+		value interface{}
+	}{
+		{TypeUint8, 255}, {TypeInt16, -32768.0}, {TypeInt24, -1 << 23}, {TypeUint64, uint64(math.MaxUint64)},
+		{TypeInt64, math.MinInt64}, {TypeInt64, float64(math.MinInt64)}, {TypeUnsignedVarInt, math.MaxUint32},
+	} {
+		_, err = NewSchema().Field("v", test.t).Encode(map[string]interface{}{"v": test.value})
+		assert.NilError(t, err, "%s %v", test.t, test.value)
+	}
+
+	_, err = NewSchema().Array("ids", TypeVarInt).Decode(b(0x7f))
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+}
+
+func TestSchemaJSON(t *testing.T) {
+	schema := NewSchema().Field("id", TypeVarInt).Array("tags", TypeString).StructArray("items", NewSchema().Field("count", TypeUint8))
+	data, err := json.Marshal(schema)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `[{"name":"id","type":"varint"},{"name":"tags","type":"string","array":true},`+
+		`{"name":"items","type":"struct","array":true,"fields":[{"name":"count","type":"uint8"}]}]`)
+
+	var decoded Schema
+	assert.NilError(t, json.Unmarshal(data, &decoded))
+	assert.DeepEqual(t, decoded.toJSON(), schema.toJSON())
+
+	var values map[string]interface{}
+	assert.NilError(t, json.Unmarshal([]byte(`{"id":-1,"tags":["a"],"items":[{"count":3}]}`), &values))
+	buffer, err := decoded.Encode(values)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, buffer, b(0x01, 0x01, 0x01, 'a', 0x01, 0x03))
+
+	assert.ErrorContains(t, json.Unmarshal([]byte(`[{"name":"id","type":"int128"}]`), &decoded), `unknown field type "int128" of field id`)
+}
@@ -0,0 +1,82 @@
+package binutils
+
+import (
+	"errors"
+	"math"
+)
+
+// errNoSection is returned by EndSection if no section is open.
+var errNoSection = errors.New("binutils: no section open")
+
+// section is a section opened by BeginSection.
+type section struct {
+	start  int
+	prefix PrefixType
+}
+
+// BeginSection opens a section whose byte length is written as a prefix of the given type in front of it once
+// the section is ended with EndSection, so that chunked formats can be encoded without knowing the size of
+// their chunks in advance. Sections are nested: each EndSection ends the most recently opened section.
+//
+// Fixed width prefixes are reserved in the buffer and filled in when the section ends. Varint prefixes are
+// inserted when the section ends instead, moving the bytes of the section by the size of the prefix.
+func (stream *Stream) BeginSection(prefix PrefixType) {
+	if prefix.size() > 0 {
+		stream.PutPrefix(prefix, 0)
+	}
+	stream.sections = append(stream.sections, section{len(stream.Buffer), prefix})
+}
+
+// EndSection ends the most recently opened section, writing its length in front of it.
+// If the length does not fit the prefix type, a *LengthError is set on the stream and returned.
+func (stream *Stream) EndSection() error {
+	if len(stream.sections) == 0 {
+		return errNoSection
+	}
+	s := stream.sections[len(stream.sections)-1]
+	stream.sections = stream.sections[:len(stream.sections)-1]
+	if stream.err != nil {
+		return stream.err
+	}
+	length := len(stream.Buffer) - s.start
+	if max := prefixMax(s.prefix); length > max {
+		stream.SetError(&StreamError{"EndSection", s.start, &LengthError{length, max}})
+		return stream.err
+	}
+	var b [maxVarLongBytes]byte
+	encoded := b[:0]
+	WritePrefix(&encoded, s.prefix, length)
+	if s.prefix.size() > 0 {
+		if !stream.writable("EndSection", 0) {
+			return stream.err
+		}
+		copy(stream.Buffer[s.start-len(encoded):], encoded)
+		return nil
+	}
+	if !stream.writable("EndSection", len(encoded)) {
+		return stream.err
+	}
+	stream.Buffer = append(stream.Buffer, encoded...)
+	copy(stream.Buffer[s.start+len(encoded):], stream.Buffer[s.start:s.start+length])
+	copy(stream.Buffer[s.start:], encoded)
+	return nil
+}
+
+// Sections returns the number of open sections.
+func (stream *Stream) Sections() int {
+	return len(stream.sections)
+}
+
+// prefixMax returns the largest length that can be written as a prefix of the given type.
+func prefixMax(prefix PrefixType) int {
+	switch prefix {
+	case PrefixByte:
+		return math.MaxUint8
+	case PrefixUnsignedShort, PrefixLittleUnsignedShort:
+		return math.MaxUint16
+	case PrefixUnsignedVarInt, PrefixUnsignedInt, PrefixLittleUnsignedInt:
+		return min(math.MaxUint32, math.MaxInt)
+	default:
+		return math.MaxInt32
+	}
+}
@@ -0,0 +1,42 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	stream := NewStream()
+	stream.BeginSection(PrefixUnsignedShort)
+	stream.PutByte(1)
+	stream.BeginSection(PrefixUnsignedVarInt)
+	stream.PutBytes(make([]byte, 200))
+	assert.Equal(t, stream.Sections(), 2)
+	assert.NilError(t, stream.EndSection())
+	stream.BeginSection(PrefixLittleInt)
+	stream.PutByte(2)
+	assert.NilError(t, stream.EndSection())
+	assert.NilError(t, stream.EndSection())
+	assert.Equal(t, stream.Sections(), 0)
+
+	assert.Equal(t, len(stream.Buffer), 2+1+2+200+4+1)
+	assert.Equal(t, stream.GetUnsignedShort(), uint16(208))
+	assert.Equal(t, stream.GetByte(), byte(1))
+	assert.DeepEqual(t, stream.GetLengthPrefixedBytes(), make([]byte, 200))
+	assert.Equal(t, stream.GetLittleInt(), int32(1))
+	assert.Equal(t, stream.GetByte(), byte(2))
+	assert.NilError(t, stream.Err())
+
+	assert.Equal(t, stream.EndSection(), errNoSection)
+}
+
+func TestSectionTooLong(t *testing.T) {
+	stream := NewStream()
+	stream.BeginSection(PrefixByte)
+	stream.PutBytes(make([]byte, 256))
+	err := stream.EndSection()
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(err, &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{256, 255})
+}
@@ -0,0 +1,126 @@
+package binutils
+
+// SequenceDistance returns the signed distance from one sequence number to another,
+// for sequence numbers of the given bit width that wrap around.
+// The distance is positive if to is newer than from.
+func SequenceDistance(from, to uint32, bits uint) int64 {
+	mask := sequenceMask(bits)
+	distance := int64((to - from) & mask)
+	if distance > int64(mask>>1) {
+		distance -= int64(mask) + 1
+	}
+	return distance
+}
+
+// NextSequence returns the sequence number following seq, for sequence numbers of the given bit width.
+func NextSequence(seq uint32, bits uint) uint32 {
+	return (seq + 1) & sequenceMask(bits)
+}
+
+// sequenceMask returns the mask of sequence numbers of the given bit width.
+func sequenceMask(bits uint) uint32 {
+	if bits >= 32 {
+		return 0xFFFFFFFF
+	}
+	return 1<<bits - 1
+}
+
+// SequenceWindow validates sequence numbers against a sliding window of recently seen sequence numbers,
+// for datagram protocols that must drop duplicates and replays.
+// Sequence numbers newer than the highest seen move the window forward,
+// older sequence numbers are accepted only once and only if they are still inside of the window.
+type SequenceWindow struct {
+	bits    uint
+	size    uint
+	highest uint32
+	started bool
+	bitmap  []uint64
+}
+
+// NewSequenceWindow returns a new window for sequence numbers of the given bit width, such as 24 for triads,
+// tracking size sequence numbers. The size is rounded up to a multiple of 64.
+func NewSequenceWindow(bits uint, size uint) *SequenceWindow {
+	words := (size + 63) / 64
+	if words == 0 {
+		words = 1
+	}
+	return &SequenceWindow{bits: bits, size: words * 64, bitmap: make([]uint64, words)}
+}
+
+// Check checks if the sequence number would be accepted, without recording it.
+func (window *SequenceWindow) Check(seq uint32) bool {
+	if !window.started {
+		return true
+	}
+	distance := SequenceDistance(window.highest, seq&sequenceMask(window.bits), window.bits)
+	if distance > 0 {
+		return true
+	}
+	age := uint(-distance)
+	return age < window.size && !window.seen(age)
+}
+
+// Accept checks if the sequence number is accepted, and records it if so.
+// Sequence numbers that were already seen or that are older than the window are rejected.
+func (window *SequenceWindow) Accept(seq uint32) bool {
+	seq &= sequenceMask(window.bits)
+	if !window.started {
+		window.started = true
+		window.highest = seq
+		window.bitmap[0] = 1
+		return true
+	}
+	distance := SequenceDistance(window.highest, seq, window.bits)
+	if distance > 0 {
+		window.shift(uint(distance))
+		window.highest = seq
+		window.bitmap[0] |= 1
+		return true
+	}
+	age := uint(-distance)
+	if age >= window.size || window.seen(age) {
+		return false
+	}
+	window.bitmap[age/64] |= 1 << (age % 64)
+	return true
+}
+
+// Highest returns the highest sequence number accepted.
+func (window *SequenceWindow) Highest() uint32 {
+	return window.highest
+}
+
+// Reset forgets all sequence numbers seen.
+func (window *SequenceWindow) Reset() {
+	window.started = false
+	window.highest = 0
+	for i := range window.bitmap {
+		window.bitmap[i] = 0
+	}
+}
+
+// seen checks if the sequence number age numbers older than the highest was seen.
+func (window *SequenceWindow) seen(age uint) bool {
+	return window.bitmap[age/64]&(1<<(age%64)) != 0
+}
+
+// shift ages all sequence numbers in the window by n.
+func (window *SequenceWindow) shift(n uint) {
+	if n >= window.size {
+		for i := range window.bitmap {
+			window.bitmap[i] = 0
+		}
+		return
+	}
+	words, bits := int(n/64), n%64
+	for i := len(window.bitmap) - 1; i >= 0; i-- {
+		var word uint64
+		if j := i - words; j >= 0 {
+			word = window.bitmap[j] << bits
+			if bits > 0 && j > 0 {
+				word |= window.bitmap[j-1] >> (64 - bits)
+			}
+		}
+		window.bitmap[i] = word
+	}
+}
@@ -0,0 +1,41 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestSequenceDistance(t *testing.T) {
+	assert.Equal(t, SequenceDistance(10, 12, 24), int64(2))
+	assert.Equal(t, SequenceDistance(12, 10, 24), int64(-2))
+	assert.Equal(t, SequenceDistance(0xFFFFFF, 1, 24), int64(2))
+	assert.Equal(t, SequenceDistance(1, 0xFFFFFF, 24), int64(-2))
+	assert.Equal(t, SequenceDistance(0xFFFFFFFF, 0, 32), int64(1))
+	assert.Equal(t, NextSequence(0xFFFFFF, 24), uint32(0))
+}
+
+func TestSequenceWindow(t *testing.T) {
+	window := NewSequenceWindow(24, 100)
+
+	assert.Assert(t, window.Accept(5))
+	assert.Assert(t, !window.Accept(5))
+	assert.Assert(t, window.Accept(7))
+	assert.Assert(t, window.Check(6))
+	assert.Assert(t, window.Accept(6))
+	assert.Assert(t, !window.Check(6))
+
+	assert.Assert(t, window.Accept(200))
+	assert.Assert(t, !window.Accept(7))
+	assert.Assert(t, window.Accept(150))
+	assert.Assert(t, !window.Accept(150))
+	assert.Assert(t, window.Accept(73))
+	assert.Assert(t, !window.Accept(72))
+	assert.Equal(t, window.Highest(), uint32(200))
+
+	window.Reset()
+	assert.Assert(t, window.Accept(0xFFFFFE))
+	assert.Assert(t, window.Accept(0x000001))
+	assert.Assert(t, window.Accept(0xFFFFFF))
+	assert.Assert(t, !window.Accept(0xFFFFFE))
+	assert.Equal(t, window.Highest(), uint32(1))
+}
@@ -0,0 +1,71 @@
+package binutils
+
+import "fmt"
+
+// Serializable is implemented by types that encode and decode themselves on a stream.
+type Serializable interface {
+	Encode(stream *Stream)
+	Decode(stream *Stream)
+}
+
+// PacketError is an error concerning the packet with the given ID.
+type PacketError struct {
+	ID  uint32
+	Err error
+}
+
+func (err *PacketError) Error() string {
+	return fmt.Sprintf("binutils: packet 0x%02x: %v", err.ID, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *PacketError) Unwrap() error {
+	return err.Err
+}
+
+// decode decodes the serializable from the stream.
+// A panic in the decoding is recovered as error of the operation op if panic recovery is enabled.
+func (stream *Stream) decode(op string, serializable Serializable) {
+	defer stream.guard(op, stream.Offset)
+	serializable.Decode(stream)
+}
+
+// PutPolymorphicSlice writes the elements, each prefixed with the type ID returned by id as unsigned varint.
+// The element count is not written, so that it can be written with any prefix beforehand.
+func (stream *Stream) PutPolymorphicSlice(elements []Serializable, id func(Serializable) uint32) {
+	if !stream.writable("PutPolymorphicSlice", 0) {
+		return
+	}
+	for _, element := range elements {
+		stream.PutUnsignedVarInt(id(element))
+		element.Encode(stream)
+	}
+}
+
+// GetPolymorphicSlice reads count elements, each prefixed with its type ID as unsigned varint,
+// decoding each into the element the factory returns for its ID. If the factory returns nil,
+// a *PacketError wrapping ErrUnknownPacket is set on the stream. The elements read before an error are returned.
+func (stream *Stream) GetPolymorphicSlice(count int, factory func(id uint32) Serializable) []Serializable {
+	// Every element takes at least one byte for its ID, which bounds the allocation by the bytes left.
+	if !stream.check("GetPolymorphicSlice", count) || !stream.checkCount("GetPolymorphicSlice", stream.Offset, count) {
+		return nil
+	}
+	elements := make([]Serializable, 0, count)
+	for i := 0; i < count; i++ {
+		id := stream.GetUnsignedVarInt()
+		if stream.err != nil {
+			break
+		}
+		element := factory(id)
+		if element == nil {
+			stream.SetError(&PacketError{id, ErrUnknownPacket})
+			break
+		}
+		stream.decode("GetPolymorphicSlice", element)
+		if stream.err != nil {
+			break
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
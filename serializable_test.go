@@ -0,0 +1,75 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type testPoint struct {
+	X, Y int16
+}
+
+func (point *testPoint) Encode(stream *Stream) {
+	stream.PutShort(point.X)
+	stream.PutShort(point.Y)
+}
+
+func (point *testPoint) Decode(stream *Stream) {
+	point.X = stream.GetShort()
+	point.Y = stream.GetShort()
+}
+
+type testLabel struct {
+	Text string
+}
+
+func (label *testLabel) Encode(stream *Stream) {
+	stream.PutString(label.Text)
+}
+
+func (label *testLabel) Decode(stream *Stream) {
+	label.Text = stream.GetString()
+}
+
+func testFactory(id uint32) Serializable {
+	switch id {
+	case 1:
+		return &testPoint{}
+	case 2:
+		return &testLabel{}
+	}
+	return nil
+}
+
+func testID(element Serializable) uint32 {
+	if _, ok := element.(*testPoint); ok {
+		return 1
+	}
+	return 2
+}
+
+func TestPolymorphicSlice(t *testing.T) {
+	elements := []Serializable{&testPoint{1, -2}, &testLabel{"sign"}, &testPoint{3, 4}}
+	stream := NewStream()
+	stream.PutPolymorphicSlice(elements, testID)
+	assert.DeepEqual(t, stream.Buffer[:5], b(0x01, 0x00, 0x01, 0xff, 0xfe))
+
+	decoded := stream.GetPolymorphicSlice(len(elements), testFactory)
+	assert.NilError(t, stream.Err())
+	assert.DeepEqual(t, decoded, elements)
+
+	stream.Offset = 0
+	stream.Buffer[5] = 0x07
+	decoded = stream.GetPolymorphicSlice(len(elements), testFactory)
+	var packetErr *PacketError
+	assert.Assert(t, errors.As(stream.Err(), &packetErr))
+	assert.Equal(t, packetErr.ID, uint32(7))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnknownPacket))
+	assert.Equal(t, len(decoded), 1)
+
+	stream = &Stream{Buffer: b(0x01)}
+	assert.Assert(t, stream.GetPolymorphicSlice(1<<30, testFactory) == nil)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
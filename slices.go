@@ -0,0 +1,193 @@
+package binutils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// byteOrder returns the default byte order of the stream as binary.ByteOrder.
+func (stream *Stream) byteOrder() binary.ByteOrder {
+	return byteOrder(stream.endian)
+}
+
+// putSlice writes the values of the given encoded size prefixed with their count,
+// growing the buffer once for all values.
+func putSlice[T any](stream *Stream, op string, values []T, size int, put func(b []byte, order binary.ByteOrder, v T)) {
+	if !stream.writable(op, prefixSize(stream.stringPrefix, len(values))+len(values)*size) {
+		return
+	}
+	stream.PutPrefix(stream.stringPrefix, len(values))
+	order := stream.byteOrder()
+	start := len(stream.Buffer)
+	if n := start + len(values)*size; n <= cap(stream.Buffer) {
+		stream.Buffer = stream.Buffer[:n]
+	} else {
+		stream.Buffer = append(stream.Buffer, make([]byte, len(values)*size)...)
+	}
+	for i, v := range values {
+		put(stream.Buffer[start+i*size:], order, v)
+	}
+}
+
+// getSlice reads values of the given encoded size prefixed with their count for the operation op.
+func getSlice[T any](stream *Stream, op string, size int, get func(b []byte, order binary.ByteOrder) T) []T {
+	offset := stream.Offset
+	length := stream.GetPrefix(stream.stringPrefix)
+	if stream.err != nil || !stream.checkCount(op, offset, length) {
+		return nil
+	}
+	if !stream.available(op, length, size) {
+		stream.SetError(&StreamError{op, stream.Offset, ErrUnexpectedEOF})
+		return nil
+	}
+	order := stream.byteOrder()
+	values := make([]T, length)
+	for i := range values {
+		values[i] = get(stream.Buffer[stream.Offset+i*size:], order)
+	}
+	stream.Offset += length * size
+	return values
+}
+
+// PutShortSlice writes int16 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutShortSlice(values []int16) {
+	putSlice(stream, "PutShortSlice", values, 2, func(b []byte, order binary.ByteOrder, v int16) { order.PutUint16(b, uint16(v)) })
+}
+
+// GetShortSlice reads int16 values written by PutShortSlice.
+func (stream *Stream) GetShortSlice() []int16 {
+	return getSlice(stream, "GetShortSlice", 2, func(b []byte, order binary.ByteOrder) int16 { return int16(order.Uint16(b)) })
+}
+
+// PutUnsignedShortSlice writes uint16 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutUnsignedShortSlice(values []uint16) {
+	putSlice(stream, "PutUnsignedShortSlice", values, 2, func(b []byte, order binary.ByteOrder, v uint16) { order.PutUint16(b, v) })
+}
+
+// GetUnsignedShortSlice reads uint16 values written by PutUnsignedShortSlice.
+func (stream *Stream) GetUnsignedShortSlice() []uint16 {
+	return getSlice(stream, "GetUnsignedShortSlice", 2, func(b []byte, order binary.ByteOrder) uint16 { return order.Uint16(b) })
+}
+
+// PutIntSlice writes int32 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutIntSlice(values []int32) {
+	putSlice(stream, "PutIntSlice", values, 4, func(b []byte, order binary.ByteOrder, v int32) { order.PutUint32(b, uint32(v)) })
+}
+
+// GetIntSlice reads int32 values written by PutIntSlice.
+func (stream *Stream) GetIntSlice() []int32 {
+	return getSlice(stream, "GetIntSlice", 4, func(b []byte, order binary.ByteOrder) int32 { return int32(order.Uint32(b)) })
+}
+
+// PutUnsignedIntSlice writes uint32 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutUnsignedIntSlice(values []uint32) {
+	putSlice(stream, "PutUnsignedIntSlice", values, 4, func(b []byte, order binary.ByteOrder, v uint32) { order.PutUint32(b, v) })
+}
+
+// GetUnsignedIntSlice reads uint32 values written by PutUnsignedIntSlice.
+func (stream *Stream) GetUnsignedIntSlice() []uint32 {
+	return getSlice(stream, "GetUnsignedIntSlice", 4, func(b []byte, order binary.ByteOrder) uint32 { return order.Uint32(b) })
+}
+
+// PutLongSlice writes int64 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutLongSlice(values []int64) {
+	putSlice(stream, "PutLongSlice", values, 8, func(b []byte, order binary.ByteOrder, v int64) { order.PutUint64(b, uint64(v)) })
+}
+
+// GetLongSlice reads int64 values written by PutLongSlice.
+func (stream *Stream) GetLongSlice() []int64 {
+	return getSlice(stream, "GetLongSlice", 8, func(b []byte, order binary.ByteOrder) int64 { return int64(order.Uint64(b)) })
+}
+
+// PutUnsignedLongSlice writes uint64 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutUnsignedLongSlice(values []uint64) {
+	putSlice(stream, "PutUnsignedLongSlice", values, 8, func(b []byte, order binary.ByteOrder, v uint64) { order.PutUint64(b, v) })
+}
+
+// GetUnsignedLongSlice reads uint64 values written by PutUnsignedLongSlice.
+func (stream *Stream) GetUnsignedLongSlice() []uint64 {
+	return getSlice(stream, "GetUnsignedLongSlice", 8, func(b []byte, order binary.ByteOrder) uint64 { return order.Uint64(b) })
+}
+
+// PutFloatSlice writes float32 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutFloatSlice(values []float32) {
+	putSlice(stream, "PutFloatSlice", encodeFloats(stream, values), 4, func(b []byte, order binary.ByteOrder, v float32) { order.PutUint32(b, math.Float32bits(v)) })
+}
+
+// GetFloatSlice reads float32 values written by PutFloatSlice.
+func (stream *Stream) GetFloatSlice() []float32 {
+	offset := stream.Offset
+	values := getSlice(stream, "GetFloatSlice", 4, func(b []byte, order binary.ByteOrder) float32 { return math.Float32frombits(order.Uint32(b)) })
+	return decodeFloats(stream, "GetFloatSlice", offset, values)
+}
+
+// PutDoubleSlice writes float64 values prefixed with their count as the string prefix type, in the byte order of the stream.
+func (stream *Stream) PutDoubleSlice(values []float64) {
+	putSlice(stream, "PutDoubleSlice", encodeFloats(stream, values), 8, func(b []byte, order binary.ByteOrder, v float64) { order.PutUint64(b, math.Float64bits(v)) })
+}
+
+// GetDoubleSlice reads float64 values written by PutDoubleSlice.
+func (stream *Stream) GetDoubleSlice() []float64 {
+	offset := stream.Offset
+	values := getSlice(stream, "GetDoubleSlice", 8, func(b []byte, order binary.ByteOrder) float64 { return math.Float64frombits(order.Uint64(b)) })
+	return decodeFloats(stream, "GetDoubleSlice", offset, values)
+}
+
+// CountPrefixed is passed to GetSlice as count to read the element count from an unsigned varint prefix,
+// as written by PutSlice with prefixed set.
+const CountPrefixed = -1
+
+// PutSlice writes the values with write, prefixed with their count as unsigned varint if prefixed is set.
+// Writing stops at the first error returned by write or set on the stream. Errors returned by write are set on
+// the stream, and the error of the stream is returned.
+func PutSlice[T any](stream *Stream, values []T, prefixed bool, write func(*Stream, T) error) error {
+	if !stream.writable("PutSlice", 0) {
+		return stream.err
+	}
+	if prefixed {
+		stream.PutUnsignedVarInt(uint32(len(values)))
+	}
+	for i, v := range values {
+		if stream.err != nil {
+			break
+		}
+		offset := len(stream.Buffer)
+		if err := write(stream, v); err != nil {
+			stream.SetError(&StreamError{"PutSlice", offset, fmt.Errorf("element %d: %w", i, err)})
+		}
+	}
+	return stream.err
+}
+
+// GetSlice reads count values with read, or as many as the unsigned varint prefix holds if count is CountPrefixed.
+// Reading stops at the first error returned by read or set on the stream, so that read does not need to check
+// the stream itself. Errors returned by read are set on the stream, and the values read before an error are
+// returned with the error of the stream. Counts beyond the slice limit of the stream are rejected.
+func GetSlice[T any](stream *Stream, count int, read func(*Stream) (T, error)) ([]T, error) {
+	offset := stream.Offset
+	if count == CountPrefixed {
+		count = int(stream.GetUnsignedVarInt())
+	}
+	if stream.err != nil || !stream.checkCount("GetSlice", offset, count) {
+		return nil, stream.err
+	}
+	if count < 0 {
+		stream.SetError(&StreamError{"GetSlice", offset, fmt.Errorf("negative count %d", count)})
+		return nil, stream.err
+	}
+	// Elements may be encoded in no bytes at all, so the count is only trusted as far as the bytes left.
+	values := make([]T, 0, min(count, stream.Remaining()))
+	for i := 0; i < count; i++ {
+		offset := stream.Offset
+		v, err := read(stream)
+		if err != nil {
+			stream.SetError(&StreamError{"GetSlice", offset, fmt.Errorf("element %d: %w", i, err)})
+		}
+		if stream.err != nil {
+			break
+		}
+		values = append(values, v)
+	}
+	return values, stream.err
+}
@@ -0,0 +1,101 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestSlices(t *testing.T) {
+	for _, endian := range []EndianType{BigEndian, LittleEndian} {
+		stream := NewStream()
+		stream.SetEndianness(endian)
+		stream.PutShortSlice([]int16{-1, 2})
+		stream.PutUnsignedShortSlice([]uint16{0xffff})
+		stream.PutIntSlice([]int32{-3, 4})
+		stream.PutUnsignedIntSlice(nil)
+		stream.PutLongSlice([]int64{-5})
+		stream.PutUnsignedLongSlice([]uint64{6, 7})
+		stream.PutFloatSlice([]float32{1.5, -2})
+		stream.PutDoubleSlice([]float64{0.25})
+
+		assert.DeepEqual(t, stream.GetShortSlice(), []int16{-1, 2})
+		assert.DeepEqual(t, stream.GetUnsignedShortSlice(), []uint16{0xffff})
+		assert.DeepEqual(t, stream.GetIntSlice(), []int32{-3, 4})
+		assert.DeepEqual(t, stream.GetUnsignedIntSlice(), []uint32{})
+		assert.DeepEqual(t, stream.GetLongSlice(), []int64{-5})
+		assert.DeepEqual(t, stream.GetUnsignedLongSlice(), []uint64{6, 7})
+		assert.DeepEqual(t, stream.GetFloatSlice(), []float32{1.5, -2})
+		assert.DeepEqual(t, stream.GetDoubleSlice(), []float64{0.25})
+		assert.NilError(t, stream.Err())
+		assert.Equal(t, stream.Offset, len(stream.Buffer))
+	}
+}
+
+func TestSliceEndianness(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedShortSlice([]uint16{0x0102})
+	stream.SetEndianness(LittleEndian)
+	stream.PutUnsignedShortSlice([]uint16{0x0102})
+	assert.DeepEqual(t, stream.Buffer, b(1, 0x01, 0x02, 1, 0x02, 0x01))
+}
+
+func TestSliceTruncated(t *testing.T) {
+	stream := &Stream{Buffer: b(0xff, 0xff, 0xff, 0xff, 0x0f, 0, 0, 0, 0)}
+	assert.Assert(t, stream.GetLongSlice() == nil)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
+
+func TestGenericSlice(t *testing.T) {
+	type point struct {
+		X, Y int32
+	}
+	write := func(stream *Stream, p point) error {
+		stream.PutVarInt(p.X)
+		stream.PutVarInt(p.Y)
+		return nil
+	}
+	read := func(stream *Stream) (point, error) {
+		return point{stream.GetVarInt(), stream.GetVarInt()}, nil
+	}
+	points := []point{{1, -1}, {300, 2}}
+	stream := NewStream()
+	assert.NilError(t, PutSlice(stream, points, true, write))
+	assert.NilError(t, PutSlice(stream, points, false, write))
+	assert.Equal(t, stream.Buffer[0], byte(2))
+
+	decoded, err := GetSlice(stream, CountPrefixed, read)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decoded, points)
+	decoded, err = GetSlice(stream, 2, read)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decoded, points)
+
+	// Reading stops at the first error set on the stream, without read checking for it.
+	decoded, err = GetSlice(&Stream{Buffer: stream.Buffer[:4]}, CountPrefixed, read)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+	assert.DeepEqual(t, decoded, points[:1])
+
+	// Errors returned by read or write are set on the stream with the index of the element.
+	invalid := errors.New("invalid point")
+	stream = &Stream{Buffer: stream.Buffer}
+	_, err = GetSlice(stream, CountPrefixed, func(stream *Stream) (point, error) {
+		p, _ := read(stream)
+		if p.X > 100 {
+			return p, invalid
+		}
+		return p, nil
+	})
+	assert.Assert(t, errors.Is(err, invalid))
+	assert.ErrorContains(t, err, "element 1")
+	assert.Equal(t, stream.Err(), err)
+	err = PutSlice(NewStream(), points, true, func(*Stream, point) error { return invalid })
+	assert.Assert(t, errors.Is(err, invalid))
+
+	stream = NewStream()
+	stream.SetMaxSliceLength(1)
+	stream.PutUnsignedVarInt(2)
+	_, err = GetSlice(stream, CountPrefixed, read)
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(err, &lengthErr))
+}
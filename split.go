@@ -0,0 +1,52 @@
+package binutils
+
+import (
+	"bytes"
+)
+
+// SplitBy splits the bytes left in the buffer by the delimiter into sub-streams, consuming them.
+// The pieces are split like bytes.Split, and share the backing array of the stream.
+func (stream *Stream) SplitBy(delim []byte) []*Stream {
+	var streams []*Stream
+	splitter := stream.Splitter(delim)
+	for sub, ok := splitter.Next(); ok; sub, ok = splitter.Next() {
+		streams = append(streams, sub)
+	}
+	return streams
+}
+
+// Splitter returns a splitter splitting the bytes left in the buffer by the delimiter,
+// consuming the pieces as they are returned.
+func (stream *Stream) Splitter(delim []byte) *Splitter {
+	return &Splitter{stream: stream, delim: delim}
+}
+
+// Splitter iterates over the pieces of a stream split by a delimiter.
+type Splitter struct {
+	stream *Stream
+	delim  []byte
+	done   bool
+}
+
+// Next returns a sub-stream of the next piece, and false if there are no pieces left.
+// The sub-stream shares the backing array of the stream, but cannot be appended into it,
+// and has the byte order of the stream.
+func (splitter *Splitter) Next() (*Stream, bool) {
+	if splitter.done {
+		return nil, false
+	}
+	stream := splitter.stream
+	if stream.Offset > len(stream.Buffer) {
+		splitter.done = true
+		return nil, false
+	}
+	rest := stream.Buffer[stream.Offset:]
+	end, next := len(rest), len(rest)
+	if i := bytes.Index(rest, splitter.delim); i >= 0 && len(splitter.delim) > 0 {
+		end, next = i, i+len(splitter.delim)
+	} else {
+		splitter.done = true
+	}
+	stream.Offset += next
+	return stream.child(rest[:end]), true
+}
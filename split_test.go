@@ -0,0 +1,42 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestSplitBy(t *testing.T) {
+	stream := NewStream()
+	stream.PutByte(0xff)
+	stream.PutBytes([]byte("a||bc||||d"))
+	stream.GetByte()
+
+	var pieces []string
+	for _, sub := range stream.SplitBy([]byte("||")) {
+		pieces = append(pieces, string(sub.Buffer))
+	}
+	assert.DeepEqual(t, pieces, []string{"a", "bc", "", "d"})
+	assert.Equal(t, stream.Offset, len(stream.Buffer))
+}
+
+func TestSplitter(t *testing.T) {
+	stream := NewStream()
+	stream.PutBytes([]byte{1, 0, 2, 0})
+
+	splitter := stream.Splitter([]byte{0})
+	sub, ok := splitter.Next()
+	assert.Assert(t, ok)
+	assert.Equal(t, sub.GetByte(), byte(1))
+	assert.Equal(t, stream.Offset, 2)
+
+	sub.PutByte(9)
+	assert.Equal(t, stream.Buffer[1], byte(0))
+
+	sub, _ = splitter.Next()
+	assert.Equal(t, sub.GetByte(), byte(2))
+	sub, ok = splitter.Next()
+	assert.Assert(t, ok)
+	assert.Equal(t, len(sub.Buffer), 0)
+	_, ok = splitter.Next()
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,99 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+// State is a state of a protocol, such as handshake, login or play.
+type State int
+
+// StateMachine tracks the state of a protocol and the packets allowed in each state.
+// Packets decoded through the state machine that are not allowed in the current state are rejected.
+type StateMachine struct {
+	registry    *Registry
+	state       State
+	allowed     map[State]map[uint32]bool
+	transitions map[State]map[uint32]State
+	callbacks   []func(from, to State)
+}
+
+// NewStateMachine returns a new state machine decoding packets of the registry, starting in the initial state.
+func NewStateMachine(registry *Registry, initial State) *StateMachine {
+	return &StateMachine{
+		registry:    registry,
+		state:       initial,
+		allowed:     make(map[State]map[uint32]bool),
+		transitions: make(map[State]map[uint32]State),
+	}
+}
+
+// Allow allows the packets with the given IDs in the state.
+func (machine *StateMachine) Allow(state State, ids ...uint32) {
+	if machine.allowed[state] == nil {
+		machine.allowed[state] = make(map[uint32]bool)
+	}
+	for _, id := range ids {
+		machine.allowed[state][id] = true
+	}
+}
+
+// TransitionOn makes the state machine transition from one state to another
+// once the packet with the given ID was decoded in the from state.
+// The packet is allowed in the from state.
+func (machine *StateMachine) TransitionOn(from State, id uint32, to State) {
+	machine.Allow(from, id)
+	if machine.transitions[from] == nil {
+		machine.transitions[from] = make(map[uint32]State)
+	}
+	machine.transitions[from][id] = to
+}
+
+// OnTransition adds a callback called on every state transition.
+func (machine *StateMachine) OnTransition(callback func(from, to State)) {
+	machine.callbacks = append(machine.callbacks, callback)
+}
+
+// State returns the current state.
+func (machine *StateMachine) State() State {
+	return machine.state
+}
+
+// Transition transitions to the given state, calling all transition callbacks.
+func (machine *StateMachine) Transition(to State) {
+	from := machine.state
+	machine.state = to
+	for _, callback := range machine.callbacks {
+		callback(from, to)
+	}
+}
+
+// Allowed checks if the packet with the given ID is allowed in the current state.
+func (machine *StateMachine) Allowed(id uint32) bool {
+	return machine.allowed[machine.state][id]
+}
+
+// Decode reads an ID prefixed packet from the stream.
+// A *PacketError wrapping ErrUnexpectedPacket is set on the stream and returned
+// if the packet is not allowed in the current state; the packet body is not read.
+// Decoding a packet registered with TransitionOn transitions the state machine.
+func (machine *StateMachine) Decode(stream *Stream) (uint32, Serializable, error) {
+	defer stream.TraceDecode("StateMachine.Decode")()
+	offset := stream.Offset
+	id := stream.GetUnsignedVarInt()
+	if stream.err != nil {
+		return 0, nil, stream.err
+	}
+	if !machine.Allowed(id) {
+		err := &PacketError{id, ErrUnexpectedPacket}
+		stream.SetError(err)
+		return id, nil, err
+	}
+
+	stream.Offset = offset
+	id, packet, err := machine.registry.Decode(stream)
+	if err != nil {
+		return id, packet, err
+	}
+	if to, ok := machine.transitions[machine.state][id]; ok {
+		machine.Transition(to)
+	}
+	return id, packet, nil
+}
@@ -0,0 +1,101 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+const (
+	stateHandshake State = iota
+	statePlay
+)
+
+type testPacket struct {
+	Value int32
+}
+
+func (packet *testPacket) Encode(stream *Stream) {
+	stream.PutVarInt(packet.Value)
+}
+
+func (packet *testPacket) Decode(stream *Stream) {
+	packet.Value = stream.GetVarInt()
+}
+
+type testOtherPacket struct {
+	testPacket
+}
+
+func TestStateMachine(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(0x01, func() Serializable { return &testPacket{} })
+	registry.Register(0x02, func() Serializable { return &testOtherPacket{} })
+
+	var transitions []State
+	machine := NewStateMachine(registry, stateHandshake)
+	machine.TransitionOn(stateHandshake, 0x01, statePlay)
+	machine.Allow(statePlay, 0x02)
+	machine.OnTransition(func(from, to State) {
+		transitions = append(transitions, from, to)
+	})
+
+	stream := NewStream()
+	assert.NilError(t, registry.Encode(stream, &testOtherPacket{testPacket{3}}))
+	assert.NilError(t, registry.Encode(stream, &testPacket{5}))
+	assert.NilError(t, registry.Encode(stream, &testOtherPacket{testPacket{7}}))
+
+	_, _, err := machine.Decode(stream)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedPacket))
+	assert.Equal(t, stream.Offset, 1)
+
+	stream = &Stream{Buffer: stream.Buffer[2:]}
+	id, packet, err := machine.Decode(stream)
+	assert.NilError(t, err)
+	assert.Equal(t, id, uint32(0x01))
+	assert.Equal(t, packet.(*testPacket).Value, int32(5))
+	assert.Equal(t, machine.State(), statePlay)
+	assert.DeepEqual(t, transitions, []State{stateHandshake, statePlay})
+
+	id, packet, err = machine.Decode(stream)
+	assert.NilError(t, err)
+	assert.Equal(t, packet.(*testOtherPacket).Value, int32(7))
+}
+
+func TestRegistryUnknownPacket(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedVarInt(9)
+
+	_, _, err := NewRegistry().Decode(stream)
+	assert.Assert(t, errors.Is(err, ErrUnknownPacket))
+	assert.Equal(t, stream.Error(), err)
+}
+
+func TestStateMachineTrace(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(0x01, func() Serializable { return &testPacket{} })
+	machine := NewStateMachine(registry, stateHandshake)
+	machine.Allow(stateHandshake, 0x01)
+
+	tracer := &testTracer{}
+	stream := NewStream()
+	stream.SetTracer(tracer)
+	assert.NilError(t, registry.Encode(stream, &testPacket{300}))
+	_, _, err := machine.Decode(stream)
+	assert.NilError(t, err)
+	stream.PutUnsignedVarInt(0x02)
+	_, _, err = machine.Decode(stream)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedPacket))
+
+	assert.DeepEqual(t, tracer.events, []string{"binutils.encode.start", "binutils.encode.end",
+		"binutils.decode.start", "binutils.decode.start", "binutils.decode.end", "binutils.decode.end",
+		"binutils.decode.start", "binutils.decode.end"})
+	assert.Equal(t, tracer.attrs[1]["name"], "Registry.Encode")
+	assert.Equal(t, tracer.attrs[1]["bytes"], 3)
+	assert.Equal(t, tracer.attrs[4]["name"], "Registry.Decode")
+	assert.Equal(t, tracer.attrs[5]["bytes"], 3)
+	assert.Equal(t, tracer.attrs[7]["error"], true)
+	assert.Equal(t, len(tracer.errors), 1)
+}
@@ -1,15 +1,216 @@
 package binutils
 
+import (
+	"fmt"
+)
+
 // Stream is a container of a byte array and an offset.
 // Reading from the stream increments the offset.
 type Stream struct {
 	Offset int
 	Buffer []byte
+
+	endian              EndianType
+	stringPrefix        PrefixType
+	maxStringLength     int
+	maxSliceLength      int
+	maxDepth            int
+	maxVarIntBytes      int
+	maxVarLongBytes     int
+	maxDecompressedSize int
+	writeLimit          int
+	depth               int
+	err                 error
+	recoverPanics       bool
+	logger              Logger
+	tracer              Tracer
+	pipeline            Pipeline
+	errorCounts         [categoryCount]uint64
+	marks               []mark
+	sections            []section
+	transactions        []transaction
+	warnings            []Warning
+	frozen              bool
+	shared              bool
+	refill              RefillFunc
+	floatMode           FloatMode
+	pointerSize         int
+	varIntPolicy        VarIntPolicy
 }
 
 // NewStream returns a new stream.
 func NewStream() *Stream {
-	return &Stream{0, []byte{}}
+	return &Stream{Buffer: []byte{}}
+}
+
+// child returns a new stream reading the buffer, which cannot be appended into,
+// with the byte order, string, slice, varint, decompression and write limits, varint policy, float mode, pointer size,
+// nesting depth and logger of the stream. A child of a frozen stream is frozen, and a child of a stream sharing
+// its buffer, such as a fork or a mapped stream, shares it too, so that it copies the bytes before it first modifies them.
+func (stream *Stream) child(buffer []byte) *Stream {
+	return &Stream{
+		Buffer:              buffer[:len(buffer):len(buffer)],
+		endian:              stream.endian,
+		stringPrefix:        stream.stringPrefix,
+		maxStringLength:     stream.maxStringLength,
+		maxSliceLength:      stream.maxSliceLength,
+		maxDepth:            stream.maxDepth,
+		maxVarIntBytes:      stream.maxVarIntBytes,
+		maxVarLongBytes:     stream.maxVarLongBytes,
+		writeLimit:          stream.writeLimit,
+		maxDecompressedSize: stream.maxDecompressedSize,
+		floatMode:           stream.floatMode,
+		pointerSize:         stream.pointerSize,
+		varIntPolicy:        stream.varIntPolicy,
+		depth:               stream.depth,
+		logger:              stream.logger,
+		frozen:              stream.frozen,
+		shared:              stream.shared,
+	}
+}
+
+// Sub returns a sub-stream of the next length bytes, and advances the offset past them.
+// The sub-stream shares the backing array of the stream, but cannot read past the length or be appended into,
+// so that malformed nested structures cannot read past their declared boundary.
+// If fewer than length bytes are left, ErrUnexpectedEOF is set on the stream and returned.
+func (stream *Stream) Sub(length int) (*Stream, error) {
+	if !stream.check("Sub", length) {
+		return nil, stream.err
+	}
+	sub := stream.child(stream.Buffer[stream.Offset : stream.Offset+length])
+	stream.Offset += length
+	return sub, nil
+}
+
+// SetEndianness sets the byte order used by the Get and Put functions of the stream
+// that do not have an explicit byte order, such as GetInt. Streams are big endian by default.
+// The Little functions, such as GetLittleInt, always use little endian, and the Endian functions,
+// such as GetIntEndian, use the byte order passed to them.
+func (stream *Stream) SetEndianness(endian EndianType) {
+	stream.endian = endian
+}
+
+// GetEndianness returns the default byte order of the stream.
+func (stream *Stream) GetEndianness() EndianType {
+	return stream.endian
+}
+
+// Err returns the first error that occurred on the stream, or nil if no error occurred.
+//
+// Reads never panic on malformed or truncated input: a failing read sets an error on the stream
+// and returns a zero value, and once an error is set every read returns a zero value without
+// advancing the offset. This allows decoding a full packet and checking Err once at the end.
+func (stream *Stream) Err() error {
+	return stream.err
+}
+
+// Error returns the first error that occurred on the stream, or nil. It is equivalent to Err.
+func (stream *Stream) Error() error {
+	return stream.err
+}
+
+// ClearError clears the error of the stream, allowing reads to continue.
+func (stream *Stream) ClearError() {
+	stream.err = nil
+}
+
+// check checks if the operation op can read n bytes, refilling the stream if it has a refill function.
+// If an error was set on the stream, or fewer than n bytes are left, it returns false.
+// In the latter case ErrUnexpectedEOF is set on the stream.
+func (stream *Stream) check(op string, n int) bool {
+	if stream.err != nil {
+		return false
+	}
+	if n < 0 || n > len(stream.Buffer)-stream.Offset && !stream.fill(op, n) {
+		if stream.err == nil {
+			stream.SetError(&StreamError{op, stream.Offset, ErrUnexpectedEOF})
+		}
+		return false
+	}
+	return true
+}
+
+// getUnsignedVarLong reads an unsigned varint of at most maxBytes bytes, or of any length if maxBytes is 0,
+// for the operation op.
+// Varints with redundant trailing zero bytes are accepted with a warning.
+func (stream *Stream) getUnsignedVarLong(op string, maxBytes int) uint64 {
+	offset := stream.Offset
+	v := stream.getEncoded(op, func(buffer []byte) (uint64, int, error) {
+		return decodeUnsignedVarInt(buffer, maxBytes)
+	})
+	if n := stream.Offset - offset; n > 1 && stream.Buffer[stream.Offset-1] == 0 {
+		stream.warn(op, offset, "non-canonical varint")
+	}
+	return v
+}
+
+// getEncoded reads a variable length value for the operation op with the decode function,
+// which returns the value with the number of bytes read. Decoding errors are set on the stream.
+func (stream *Stream) getEncoded(op string, decode func(buffer []byte) (uint64, int, error)) uint64 {
+	if stream.err != nil {
+		return 0
+	}
+	if stream.Offset < 0 || stream.Offset > len(stream.Buffer) {
+		stream.SetError(&StreamError{op, stream.Offset, ErrUnexpectedEOF})
+		return 0
+	}
+	v, n, err := decode(stream.Buffer[stream.Offset:])
+	for err == ErrUnexpectedEOF && stream.fill(op, len(stream.Buffer)-stream.Offset+1) {
+		v, n, err = decode(stream.Buffer[stream.Offset:])
+	}
+	if err != nil {
+		stream.SetError(&StreamError{op, stream.Offset, err})
+		return 0
+	}
+	stream.Offset += n
+	return v
+}
+
+// SetError records an error on the stream.
+// Only the first error is kept; later errors are discarded, but still counted.
+// The recorded error is reported to the logger of the stream, if any. A nil error is ignored.
+func (stream *Stream) SetError(err error) {
+	if err == nil {
+		return
+	}
+	stream.countError(err)
+	if stream.err != nil {
+		return
+	}
+	stream.err = err
+	if stream.logger != nil {
+		stream.logError(err)
+	}
+}
+
+// SetRecoverPanics sets whether panics in decoders are recovered.
+// Reads of the stream itself do not panic, but the Decode methods of packets may, for example
+// by calling the package level functions on the buffer of the stream. When enabled, a panic while
+// decoding a packet through a Registry is recorded as a *StreamError carrying the operation
+// and offset instead, so that a malformed packet cannot crash the program.
+func (stream *Stream) SetRecoverPanics(recoverPanics bool) {
+	stream.recoverPanics = recoverPanics
+}
+
+// RecoversPanics checks if the stream recovers panics during reads.
+func (stream *Stream) RecoversPanics() bool {
+	return stream.recoverPanics
+}
+
+// guard recovers a panic in the operation op started at offset, if panic recovery is enabled.
+// The offset of the stream is restored to where the operation started.
+func (stream *Stream) guard(op string, offset int) {
+	if !stream.recoverPanics {
+		return
+	}
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		stream.Offset = offset
+		stream.SetError(&StreamError{Op: op, Offset: offset, Err: err})
+	}
 }
 
 // GetOffset returns the current stream offset.
@@ -24,6 +225,9 @@ func (stream *Stream) SetOffset(offset int) {
 
 // SetBuffer sets the buffer of the stream.
 func (stream *Stream) SetBuffer(buffer []byte) {
+	if !stream.writable("SetBuffer", len(buffer)-len(stream.Buffer)) {
+		return
+	}
 	stream.Buffer = buffer
 }
 
@@ -43,232 +247,477 @@ func (stream *Stream) Get(length int) []byte {
 	if length < 0 {
 		length = len(stream.Buffer) - stream.Offset - 1
 	}
+	if !stream.check("Get", length) {
+		return nil
+	}
 	return Read(&stream.Buffer, &stream.Offset, length)
 }
 
 func (stream *Stream) PutBool(v bool) {
+	if !stream.writable("PutBool", 1) {
+		return
+	}
 	WriteBool(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetBool() bool {
+	if !stream.check("GetBool", 1) {
+		return false
+	}
 	return ReadBool(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutByte(v byte) {
+	if !stream.writable("PutByte", 1) {
+		return
+	}
 	WriteByte(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetByte() byte {
+	if !stream.check("GetByte", 1) {
+		return 0
+	}
 	return ReadByte(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutUnsignedByte(v byte) {
+	if !stream.writable("PutUnsignedByte", 1) {
+		return
+	}
 	WriteUnsignedByte(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetUnsignedByte() byte {
+	if !stream.check("GetUnsignedByte", 1) {
+		return 0
+	}
 	return ReadUnsignedByte(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutShort(v int16) {
-	WriteShort(&stream.Buffer, v)
+	if !stream.writable("PutShort", 2) {
+		return
+	}
+	WriteShortEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetShort() int16 {
-	return ReadShort(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetShort", 2) {
+		return 0
+	}
+	return ReadShortEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutUnsignedShort(v uint16) {
-	WriteUnsignedShort(&stream.Buffer, v)
+	if !stream.writable("PutUnsignedShort", 2) {
+		return
+	}
+	WriteUnsignedShortEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetUnsignedShort() uint16 {
-	return ReadUnsignedShort(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetUnsignedShort", 2) {
+		return 0
+	}
+	return ReadUnsignedShortEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutInt(v int32) {
-	WriteInt(&stream.Buffer, v)
+	if !stream.writable("PutInt", 4) {
+		return
+	}
+	WriteIntEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetInt() int32 {
-	return ReadInt(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetInt", 4) {
+		return 0
+	}
+	return ReadIntEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutUnsignedInt(v uint32) {
-	WriteUnsignedInt(&stream.Buffer, v)
+	if !stream.writable("PutUnsignedInt", 4) {
+		return
+	}
+	WriteUnsignedIntEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetUnsignedInt() uint32 {
-	return ReadUnsignedInt(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetUnsignedInt", 4) {
+		return 0
+	}
+	return ReadUnsignedIntEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutLong(v int64) {
-	WriteLong(&stream.Buffer, v)
+	if !stream.writable("PutLong", 8) {
+		return
+	}
+	WriteLongEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetLong() int64 {
-	return ReadLong(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetLong", 8) {
+		return 0
+	}
+	return ReadLongEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutUnsignedLong(v uint64) {
-	WriteUnsignedLong(&stream.Buffer, v)
+	if !stream.writable("PutUnsignedLong", 8) {
+		return
+	}
+	WriteUnsignedLongEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetUnsignedLong() uint64 {
-	return ReadUnsignedLong(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetUnsignedLong", 8) {
+		return 0
+	}
+	return ReadUnsignedLongEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutFloat(v float32) {
-	WriteFloat(&stream.Buffer, v)
+	if !stream.writable("PutFloat", 4) {
+		return
+	}
+	WriteFloatEndian(&stream.Buffer, encodeFloat(stream, v), stream.endian)
 }
 
 func (stream *Stream) GetFloat() float32 {
-	return ReadFloat(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetFloat", 4) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetFloat", offset, ReadFloatEndian(&stream.Buffer, &stream.Offset, stream.endian))
 }
 
 func (stream *Stream) PutDouble(v float64) {
-	WriteDouble(&stream.Buffer, v)
+	if !stream.writable("PutDouble", 8) {
+		return
+	}
+	WriteDoubleEndian(&stream.Buffer, encodeFloat(stream, v), stream.endian)
 }
 
 func (stream *Stream) GetDouble() float64 {
-	return ReadDouble(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetDouble", 8) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetDouble", offset, ReadDoubleEndian(&stream.Buffer, &stream.Offset, stream.endian))
 }
 
 func (stream *Stream) PutVarInt(v int32) {
+	if !stream.writable("PutVarInt", uvarintSize(uint64(toZigZag32(v)))) {
+		return
+	}
 	WriteVarInt(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetVarInt() int32 {
-	return ReadVarInt(&stream.Buffer, &stream.Offset)
+	return fromZigZag32(uint32(stream.getVarIntWithPolicy("GetVarInt", stream.varIntPolicy, false)))
 }
 
 func (stream *Stream) PutVarLong(v int64) {
+	if !stream.writable("PutVarLong", uvarintSize(toZigZag64(v))) {
+		return
+	}
 	WriteVarLong(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetVarLong() int64 {
-	return ReadVarLong(&stream.Buffer, &stream.Offset)
+	return fromZigZag64(stream.getVarIntWithPolicy("GetVarLong", stream.varIntPolicy, true))
 }
 
 func (stream *Stream) PutUnsignedVarInt(v uint32) {
+	if !stream.writable("PutUnsignedVarInt", uvarintSize(uint64(v))) {
+		return
+	}
 	WriteUnsignedVarInt(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetUnsignedVarInt() uint32 {
-	return ReadUnsignedVarInt(&stream.Buffer, &stream.Offset)
+	return uint32(stream.getVarIntWithPolicy("GetUnsignedVarInt", stream.varIntPolicy, false))
 }
 
 func (stream *Stream) PutUnsignedVarLong(v uint64) {
+	if !stream.writable("PutUnsignedVarLong", uvarintSize(v)) {
+		return
+	}
 	WriteUnsignedVarLong(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetUnsignedVarLong() uint64 {
-	return ReadUnsignedVarLong(&stream.Buffer, &stream.Offset)
+	return stream.getVarIntWithPolicy("GetUnsignedVarLong", stream.varIntPolicy, true)
 }
 
 func (stream *Stream) PutString(v string) {
-	WriteUnsignedVarInt(&stream.Buffer, uint32(len(v)))
-	stream.Buffer = append(stream.Buffer, []byte(v)...)
+	if !stream.prefixFits("PutString", stream.stringPrefix, len(v)) || !stream.writable("PutString", prefixSize(stream.stringPrefix, len(v))+len(v)) {
+		return
+	}
+	WriteStringWithPrefix(&stream.Buffer, v, stream.stringPrefix)
 }
 
 func (stream *Stream) GetString() string {
-	return string(Read(&stream.Buffer, &stream.Offset, int(stream.GetUnsignedVarInt())))
+	return stream.GetStringWithPrefix(stream.stringPrefix)
 }
 
 func (stream *Stream) PutLittleShort(v int16) {
+	if !stream.writable("PutLittleShort", 2) {
+		return
+	}
 	WriteLittleShort(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleShort() int16 {
+	if !stream.check("GetLittleShort", 2) {
+		return 0
+	}
 	return ReadLittleShort(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleUnsignedShort(v uint16) {
+	if !stream.writable("PutLittleUnsignedShort", 2) {
+		return
+	}
 	WriteLittleUnsignedShort(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleUnsignedShort() uint16 {
+	if !stream.check("GetLittleUnsignedShort", 2) {
+		return 0
+	}
 	return ReadLittleUnsignedShort(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleInt(v int32) {
+	if !stream.writable("PutLittleInt", 4) {
+		return
+	}
 	WriteLittleInt(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleInt() int32 {
+	if !stream.check("GetLittleInt", 4) {
+		return 0
+	}
 	return ReadLittleInt(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleUnsignedInt(v uint32) {
+	if !stream.writable("PutLittleUnsignedInt", 4) {
+		return
+	}
 	WriteLittleUnsignedInt(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleUnsignedInt() uint32 {
+	if !stream.check("GetLittleUnsignedInt", 4) {
+		return 0
+	}
 	return ReadLittleUnsignedInt(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleLong(v int64) {
+	if !stream.writable("PutLittleLong", 8) {
+		return
+	}
 	WriteLittleLong(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleLong() int64 {
+	if !stream.check("GetLittleLong", 8) {
+		return 0
+	}
 	return ReadLittleLong(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleUnsignedLong(v uint64) {
+	if !stream.writable("PutLittleUnsignedLong", 8) {
+		return
+	}
 	WriteLittleUnsignedLong(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleUnsignedLong() uint64 {
+	if !stream.check("GetLittleUnsignedLong", 8) {
+		return 0
+	}
 	return ReadLittleUnsignedLong(&stream.Buffer, &stream.Offset)
 }
 
 func (stream *Stream) PutLittleFloat(v float32) {
-	WriteLittleFloat(&stream.Buffer, v)
+	if !stream.writable("PutLittleFloat", 4) {
+		return
+	}
+	WriteLittleFloat(&stream.Buffer, encodeFloat(stream, v))
 }
 
 func (stream *Stream) GetLittleFloat() float32 {
-	return ReadLittleFloat(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetLittleFloat", 4) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetLittleFloat", offset, ReadLittleFloat(&stream.Buffer, &stream.Offset))
 }
 
 func (stream *Stream) PutLittleDouble(v float64) {
-	WriteLittleDouble(&stream.Buffer, v)
+	if !stream.writable("PutLittleDouble", 8) {
+		return
+	}
+	WriteLittleDouble(&stream.Buffer, encodeFloat(stream, v))
 }
 
 func (stream *Stream) GetLittleDouble() float64 {
-	return ReadLittleDouble(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetLittleDouble", 8) {
+		return 0
+	}
+	offset := stream.Offset
+	return decodeFloat(stream, "GetLittleDouble", offset, ReadLittleDouble(&stream.Buffer, &stream.Offset))
 }
 
 func (stream *Stream) PutTriad(v uint32) {
-	WriteBigTriad(&stream.Buffer, v)
+	if !stream.writable("PutTriad", 3) {
+		return
+	}
+	WriteTriadEndian(&stream.Buffer, v, stream.endian)
 }
 
 func (stream *Stream) GetTriad() uint32 {
-	return ReadBigTriad(&stream.Buffer, &stream.Offset)
+	if !stream.check("GetTriad", 3) {
+		return 0
+	}
+	return ReadTriadEndian(&stream.Buffer, &stream.Offset, stream.endian)
 }
 
 func (stream *Stream) PutLittleTriad(v uint32) {
+	if !stream.writable("PutLittleTriad", 3) {
+		return
+	}
 	WriteLittleTriad(&stream.Buffer, v)
 }
 
 func (stream *Stream) GetLittleTriad() uint32 {
+	if !stream.check("GetLittleTriad", 3) {
+		return 0
+	}
 	return ReadLittleTriad(&stream.Buffer, &stream.Offset)
 }
 
+func (stream *Stream) PutInt24(v int32) {
+	if !stream.writable("PutInt24", 3) {
+		return
+	}
+	WriteInt24Endian(&stream.Buffer, v, stream.endian)
+}
+
+func (stream *Stream) GetInt24() int32 {
+	if !stream.check("GetInt24", 3) {
+		return 0
+	}
+	return ReadInt24Endian(&stream.Buffer, &stream.Offset, stream.endian)
+}
+
+func (stream *Stream) PutLittleInt24(v int32) {
+	if !stream.writable("PutLittleInt24", 3) {
+		return
+	}
+	WriteLittleInt24(&stream.Buffer, v)
+}
+
+func (stream *Stream) GetLittleInt24() int32 {
+	if !stream.check("GetLittleInt24", 3) {
+		return 0
+	}
+	return ReadLittleInt24(&stream.Buffer, &stream.Offset)
+}
+
 func (stream *Stream) PutBytes(bytes []byte) {
+	if !stream.writable("PutBytes", len(bytes)) {
+		return
+	}
 	stream.Buffer = append(stream.Buffer, bytes...)
 }
 
 func (stream *Stream) PutLengthPrefixedBytes(bytes []byte) {
-	stream.PutUnsignedVarInt(uint32(len(bytes)))
+	if !stream.prefixFits("PutLengthPrefixedBytes", stream.stringPrefix, len(bytes)) || !stream.writable("PutLengthPrefixedBytes", prefixSize(stream.stringPrefix, len(bytes))+len(bytes)) {
+		return
+	}
+	stream.PutPrefix(stream.stringPrefix, len(bytes))
 	stream.PutBytes(bytes)
 }
 
 func (stream *Stream) GetLengthPrefixedBytes() []byte {
-	return []byte(stream.GetString())
+	return copyBytes(stream.getPrefixed("GetLengthPrefixedBytes", stream.stringPrefix, stream.maxStringLength))
 }
 
 func (stream *Stream) ResetStream() {
 	stream.Offset = 0
 	stream.Buffer = []byte{}
+	stream.err = nil
+	stream.depth = 0
+	stream.marks = nil
+	stream.sections = nil
+	stream.transactions = nil
+	stream.warnings = nil
+	stream.frozen = false
+	stream.shared = false
+}
+
+// Reset empties the stream like ResetStream, but keeps the backing array of the buffer for reuse.
+// A frozen or forked stream is unfrozen with a new buffer instead, leaving the frozen bytes untouched.
+func (stream *Stream) Reset() {
+	if stream.frozen || stream.shared {
+		stream.ResetStream()
+		return
+	}
+	stream.Offset = 0
+	stream.Buffer = stream.Buffer[:0]
+	stream.err = nil
+	stream.depth = 0
+	stream.marks = nil
+	stream.sections = nil
+	stream.transactions = nil
+	stream.warnings = nil
+}
+
+// Compact drops the bytes already read, moving the bytes left to the front of the buffer, and resets the offset.
+// The backing array of the buffer is kept, so that appending to a long-lived stream does not grow it without bounds.
+// Bytes that open marks, sections or transactions can still return to are kept, with their positions moved along.
+func (stream *Stream) Compact() {
+	if !stream.writable("Compact", 0) {
+		return
+	}
+	dropped := min(stream.Offset, len(stream.Buffer))
+	for _, m := range stream.marks {
+		dropped = min(dropped, m.offset)
+	}
+	for _, s := range stream.sections {
+		dropped = min(dropped, s.start-s.prefix.size())
+	}
+	for _, t := range stream.transactions {
+		dropped = min(dropped, t.length)
+	}
+	if dropped <= 0 {
+		return
+	}
+	n := copy(stream.Buffer, stream.Buffer[dropped:])
+	stream.Buffer = stream.Buffer[:n]
+	stream.Offset -= dropped
+	for i := range stream.marks {
+		stream.marks[i].offset -= dropped
+	}
+	for i := range stream.sections {
+		stream.sections[i].start -= dropped
+	}
+	for i := range stream.transactions {
+		stream.transactions[i].length -= dropped
+	}
+}
+
+// Remaining returns the number of bytes left to read.
+func (stream *Stream) Remaining() int {
+	return max(len(stream.Buffer)-stream.Offset, 0)
 }
@@ -0,0 +1,179 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestStreamReadErrors(t *testing.T) {
+	stream := NewStream()
+	stream.SetBuffer(b(0x01, 0x02, 0x03))
+
+	assert.Equal(t, stream.GetInt(), int32(0))
+	assert.Equal(t, stream.GetOffset(), 0)
+
+	var streamErr *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &streamErr))
+	assert.Equal(t, streamErr.Op, "GetInt")
+	assert.Equal(t, streamErr.Offset, 0)
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+}
+
+func TestStreamMalformedVarInt(t *testing.T) {
+	stream := NewStream()
+	stream.SetBuffer(b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01))
+
+	assert.Equal(t, stream.GetUnsignedVarInt(), uint32(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrMalformedVarInt))
+}
+
+func TestStreamShortCircuitsAfterError(t *testing.T) {
+	stream := NewStream()
+	stream.SetBuffer(b(0x01, 0x02, 0x03))
+
+	stream.GetInt()
+	assert.Equal(t, stream.GetByte(), byte(0))
+	assert.Equal(t, stream.GetString(), "")
+	assert.Equal(t, stream.GetOffset(), 0)
+
+	var streamErr *StreamError
+	assert.Assert(t, errors.As(stream.Err(), &streamErr))
+	assert.Equal(t, streamErr.Op, "GetInt")
+
+	stream.ClearError()
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, stream.GetByte(), byte(0x01))
+}
+
+type testLogger struct {
+	msg  string
+	args []interface{}
+}
+
+func (logger *testLogger) Error(msg string, args ...interface{}) {
+	logger.msg = msg
+	logger.args = args
+}
+
+func TestStreamLogsError(t *testing.T) {
+	logger := &testLogger{}
+	stream := NewStream()
+	stream.SetBuffer(b(0x00, 0x01, 0x02))
+	stream.SetLogger(logger)
+
+	stream.GetByte()
+	stream.GetInt()
+
+	assert.Equal(t, logger.msg, "binutils: stream error")
+	assert.DeepEqual(t, logger.args[:4], []interface{}{"op", "GetInt", "offset", 1})
+	assert.DeepEqual(t, logger.args[len(logger.args)-4:], []interface{}{"dump_start", 0, "dump", "000102"})
+}
+
+func TestStringPrefixes(t *testing.T) {
+	stream := NewStream()
+	stream.PutStringWithPrefix("java", PrefixUnsignedShort)
+	stream.SetStringPrefix(PrefixLittleUnsignedInt)
+	stream.PutString("raknet")
+	stream.PutLengthPrefixedBytes([]byte{1, 2})
+
+	expected := []byte{}
+	WriteStringWithPrefix(&expected, "java", PrefixUnsignedShort)
+	assert.DeepEqual(t, expected, b(0x00, 0x04, 'j', 'a', 'v', 'a'))
+	WriteStringWithPrefix(&expected, "raknet", PrefixLittleUnsignedInt)
+	WriteStringWithPrefix(&expected, "\x01\x02", PrefixLittleUnsignedInt)
+	assert.DeepEqual(t, stream.Buffer, expected)
+
+	assert.Equal(t, stream.GetStringWithPrefix(PrefixUnsignedShort), "java")
+	assert.Equal(t, stream.GetString(), "raknet")
+	assert.DeepEqual(t, stream.GetLengthPrefixedBytes(), b(1, 2))
+	assert.Equal(t, stream.GetStringPrefix(), PrefixLittleUnsignedInt)
+}
+
+func TestStringPrefixOverflow(t *testing.T) {
+	stream := NewStream()
+	stream.SetStringPrefix(PrefixByte)
+	stream.PutString(string(make([]byte, 300)))
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(stream.Err(), &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{300, 255})
+	assert.Equal(t, len(stream.Buffer), 0)
+
+	for _, put := range []func(*Stream){
+		func(stream *Stream) { stream.PutLengthPrefixedBytes(make([]byte, 256)) },
+		func(stream *Stream) {
+			stream.PutStringWithPrefix(string(make([]byte, 1<<16)), PrefixLittleUnsignedShort)
+		},
+		func(stream *Stream) { stream.PutPrefix(PrefixByte, 256) },
+	} {
+		stream := NewStream()
+		stream.SetStringPrefix(PrefixByte)
+		put(stream)
+		assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+	}
+
+	stream = NewStream()
+	stream.PutPrefix(PrefixUnsignedShort, -1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrNegativeLength))
+}
+
+func TestMaxStringLength(t *testing.T) {
+	stream := NewStream()
+	stream.PutString("hello")
+	stream.PutString("hi")
+	stream.SetMaxStringLength(4)
+	assert.Equal(t, stream.GetString(), "")
+	assert.Assert(t, errors.Is(stream.Err(), ErrLimitExceeded))
+	assert.Equal(t, stream.Err().Error(), "binutils: GetString at offset 0: length 5 exceeds limit of 4")
+	assert.Equal(t, stream.Offset, 1)
+
+	stream.ClearError()
+	stream.Offset = 0
+	assert.Equal(t, stream.GetStringMax(5), "hello")
+	assert.Equal(t, stream.GetStringMax(2), "hi")
+	assert.NilError(t, stream.Err())
+
+	stream.Offset = 0
+	assert.Assert(t, stream.GetLengthPrefixedBytesMax(1) == nil)
+	var lengthErr *LengthError
+	assert.Assert(t, errors.As(stream.Err(), &lengthErr))
+	assert.Equal(t, *lengthErr, LengthError{5, 1})
+}
+
+func TestResetAndCompact(t *testing.T) {
+	stream := NewStreamWithCapacity(8)
+	stream.PutBytes(b(1, 2, 3, 4))
+	stream.GetShort()
+	assert.Equal(t, stream.Remaining(), 2)
+
+	stream.Compact()
+	assert.Equal(t, stream.Offset, 0)
+	assert.DeepEqual(t, stream.Buffer, b(3, 4))
+	assert.Equal(t, cap(stream.Buffer), 8)
+
+	stream.GetInt()
+	assert.Equal(t, stream.Remaining(), 2)
+	stream.Reset()
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, stream.Remaining(), 0)
+	assert.Equal(t, len(stream.Buffer), 0)
+	assert.Equal(t, cap(stream.Buffer), 8)
+}
+
+func TestSub(t *testing.T) {
+	stream := &Stream{Buffer: b(0, 2, 1, 2, 3)}
+	stream.SetStringPrefix(PrefixUnsignedShort)
+	sub, err := stream.Sub(4)
+	assert.NilError(t, err)
+	assert.Equal(t, stream.Offset, 4)
+	assert.Equal(t, sub.GetString(), "\x01\x02")
+	assert.Equal(t, sub.GetByte(), byte(0))
+	assert.Assert(t, errors.Is(sub.Err(), ErrUnexpectedEOF))
+	assert.NilError(t, stream.Err())
+
+	sub.PutByte(9)
+	assert.Equal(t, stream.GetByte(), byte(3))
+
+	_, err = stream.Sub(1)
+	assert.Assert(t, errors.Is(err, ErrUnexpectedEOF))
+}
@@ -0,0 +1,70 @@
+package binutils
+
+import (
+	"bytes"
+	"strings"
+)
+
+// PutFixedString writes the string as exactly n bytes, truncating it or padding it with zero bytes.
+// A negative n sets ErrNegativeLength on the stream.
+func (stream *Stream) PutFixedString(s string, n int) {
+	if n < 0 {
+		stream.SetError(&StreamError{"PutFixedString", len(stream.Buffer), ErrNegativeLength})
+		return
+	}
+	if !stream.writable("PutFixedString", n) {
+		return
+	}
+	if len(s) > n {
+		s = s[:n]
+	}
+	stream.Buffer = append(stream.Buffer, s...)
+	stream.Buffer = append(stream.Buffer, make([]byte, n-len(s))...)
+}
+
+// GetFixedString reads a string of exactly n bytes, up to the first zero byte.
+func (stream *Stream) GetFixedString(n int) string {
+	if !stream.check("GetFixedString", n) {
+		return ""
+	}
+	b := Read(&stream.Buffer, &stream.Offset, n)
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// PutCString writes the string terminated by a zero byte.
+// Strings containing a zero byte are written up to the zero byte.
+func (stream *Stream) PutCString(s string) {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	if !stream.writable("PutCString", len(s)+1) {
+		return
+	}
+	stream.Buffer = append(stream.Buffer, s...)
+	stream.Buffer = append(stream.Buffer, 0)
+}
+
+// GetCString reads a string terminated by a zero byte, consuming the zero byte.
+// If no zero byte is left in the buffer, ErrUnexpectedEOF is set on the stream.
+func (stream *Stream) GetCString() string {
+	if !stream.check("GetCString", 0) {
+		return ""
+	}
+	i := bytes.IndexByte(stream.Buffer[stream.Offset:], 0)
+	for i < 0 && stream.fill("GetCString", len(stream.Buffer)-stream.Offset+1) {
+		i = bytes.IndexByte(stream.Buffer[stream.Offset:], 0)
+	}
+	if i < 0 {
+		if stream.err != nil {
+			return ""
+		}
+		stream.SetError(&StreamError{"GetCString", stream.Offset, ErrUnexpectedEOF})
+		return ""
+	}
+	s := string(stream.Buffer[stream.Offset : stream.Offset+i])
+	stream.Offset += i + 1
+	return s
+}
@@ -0,0 +1,40 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestFixedString(t *testing.T) {
+	stream := NewStream()
+	stream.PutFixedString("RIFF", 4)
+	stream.PutFixedString("ab", 4)
+	stream.PutFixedString("truncated", 3)
+	assert.DeepEqual(t, stream.Buffer, b('R', 'I', 'F', 'F', 'a', 'b', 0, 0, 't', 'r', 'u'))
+
+	assert.Equal(t, stream.GetFixedString(4), "RIFF")
+	assert.Equal(t, stream.GetFixedString(4), "ab")
+	assert.Equal(t, stream.GetFixedString(3), "tru")
+	assert.Equal(t, stream.GetFixedString(1), "")
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+
+	stream = NewStream()
+	stream.PutFixedString("steve", -1)
+	assert.Assert(t, errors.Is(stream.Err(), ErrNegativeLength))
+	assert.Equal(t, len(stream.Buffer), 0)
+}
+
+func TestCString(t *testing.T) {
+	stream := NewStream()
+	stream.PutCString("maps/e1m1.bsp")
+	stream.PutCString("")
+	stream.PutCString("a\x00b")
+	stream.PutBytes(b('x'))
+
+	assert.Equal(t, stream.GetCString(), "maps/e1m1.bsp")
+	assert.Equal(t, stream.GetCString(), "")
+	assert.Equal(t, stream.GetCString(), "a")
+	assert.Equal(t, stream.GetCString(), "")
+	assert.Equal(t, stream.Err().Error(), "binutils: GetCString at offset 17: unexpected end of buffer")
+}
@@ -0,0 +1,39 @@
+package binutils
+
+// PutTagged writes a tagged union variant: the ID as unsigned varint, followed by the body written by payload,
+// prefixed with its byte length as unsigned varint.
+func (stream *Stream) PutTagged(id uint32, payload func(*Stream)) {
+	stream.PutUnsignedVarInt(id)
+	stream.BeginSection(PrefixUnsignedVarInt)
+	payload(stream)
+	_ = stream.EndSection()
+}
+
+// GetTagged reads a tagged union variant written by PutTagged, and calls the handler of its ID with a stream
+// limited to its body, so that a handler cannot read past the body. Bytes of the body left unread by the handler
+// are skipped. It returns the ID read.
+//
+// If no handler is registered for the ID, the body is skipped and a *PacketError wrapping ErrUnknownPacket
+// is returned without being set on the stream, so that reading can continue with the next variant.
+// If the handler returns an error or leaves one on its stream, a *PacketError wrapping it is set on the stream and returned.
+func (stream *Stream) GetTagged(handlers map[uint32]func(*Stream) error) (uint32, error) {
+	id := stream.GetUnsignedVarInt()
+	body := stream.getPrefixed("GetTagged", PrefixUnsignedVarInt, 0)
+	if stream.err != nil {
+		return 0, stream.err
+	}
+	handler, ok := handlers[id]
+	if !ok {
+		return id, &PacketError{id, ErrUnknownPacket}
+	}
+	sub := stream.child(body)
+	err := handler(sub)
+	if err == nil {
+		err = sub.err
+	}
+	if err != nil {
+		stream.SetError(&PacketError{id, err})
+		return id, stream.err
+	}
+	return id, nil
+}
@@ -0,0 +1,50 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestTagged(t *testing.T) {
+	stream := NewStream()
+	stream.PutTagged(1, func(stream *Stream) { stream.PutString("hi") })
+	stream.PutTagged(9, func(stream *Stream) { stream.PutInt(7) })
+	stream.PutTagged(2, func(stream *Stream) { stream.PutShort(3) })
+	stream.PutTagged(2, func(stream *Stream) { stream.PutByte(3) })
+	assert.DeepEqual(t, stream.Buffer[:5], b(0x01, 0x03, 0x02, 'h', 'i'))
+
+	var text string
+	var short int16
+	handlers := map[uint32]func(*Stream) error{
+		1: func(stream *Stream) error {
+			text = stream.GetString()
+			return nil
+		},
+		2: func(stream *Stream) error {
+			short = stream.GetShort()
+			return nil
+		},
+	}
+	id, err := stream.GetTagged(handlers)
+	assert.NilError(t, err)
+	assert.Equal(t, id, uint32(1))
+	assert.Equal(t, text, "hi")
+
+	id, err = stream.GetTagged(handlers)
+	assert.Equal(t, id, uint32(9))
+	assert.Assert(t, errors.Is(err, ErrUnknownPacket))
+	assert.NilError(t, stream.Err())
+
+	_, err = stream.GetTagged(handlers)
+	assert.NilError(t, err)
+	assert.Equal(t, short, int16(3))
+
+	_, err = stream.GetTagged(handlers)
+	var packetErr *PacketError
+	assert.Assert(t, errors.As(err, &packetErr))
+	assert.Equal(t, packetErr.ID, uint32(2))
+	assert.Assert(t, errors.Is(stream.Err(), ErrUnexpectedEOF))
+	assert.Equal(t, stream.Remaining(), 0)
+}
@@ -0,0 +1,102 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Template is the encoding of a struct with placeholder fields, which are filled on every use.
+// Filling a template copies the static bytes and encodes only the placeholders, which is much faster
+// than encoding the full struct for packets of which most fields never change.
+//
+// Fields declaring sizeof or crc32 are not recomputed when filling, so placeholders
+// should not be covered by them unless their encoded size and value never change.
+type Template struct {
+	paths []string
+	// static holds the bytes before, between and after the placeholders in fields, which are sorted by offset.
+	static [][]byte
+	fields []templateField
+}
+
+// templateField is a placeholder of a template.
+type templateField struct {
+	path string
+	// arg is the index of the value of the placeholder passed to Fill.
+	arg int
+	typ reflect.Type
+	tag fieldTag
+	Range
+}
+
+// NewTemplate returns a template encoding v as described for Marshal, with the fields at the given paths,
+// such as "Header.Sequence", as placeholders.
+func NewTemplate(v interface{}, placeholders ...string) (*Template, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("binutils: cannot create template of %T, expected a struct", v)
+	}
+	m := &marshaler{stream: NewStream(), track: true}
+	if err := m.encode(value, fieldTag{}, ""); err != nil {
+		return nil, err
+	}
+	ranges := m.ranges()
+
+	fields := make([]templateField, len(placeholders))
+	for i, path := range placeholders {
+		r, ok := ranges[path]
+		if !ok {
+			return nil, fmt.Errorf("binutils: unknown field %s", path)
+		}
+		field, tag, err := resolvePath(value, path)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = templateField{path, i, field.Type(), tag, r}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Start < fields[j].Start })
+	for i := 1; i < len(fields); i++ {
+		if fields[i].Start < fields[i-1].End {
+			return nil, fmt.Errorf("binutils: placeholders %s and %s overlap", fields[i-1].path, fields[i].path)
+		}
+	}
+
+	template := &Template{paths: placeholders, fields: fields}
+	start := 0
+	for _, field := range fields {
+		template.static = append(template.static, m.stream.Buffer[start:field.Start])
+		start = field.End
+	}
+	template.static = append(template.static, m.stream.Buffer[start:])
+	return template, nil
+}
+
+// Placeholders returns the paths of the placeholders, in the order of their values in Fill.
+func (template *Template) Placeholders() []string {
+	return append([]string{}, template.paths...)
+}
+
+// Fill writes the template to the stream, with the values of the placeholders in the order they were passed to NewTemplate.
+func (template *Template) Fill(stream *Stream, values ...interface{}) error {
+	if len(values) != len(template.fields) {
+		return fmt.Errorf("binutils: template has %d placeholders, got %d values", len(template.fields), len(values))
+	}
+	m := &marshaler{stream: stream}
+	for i, field := range template.fields {
+		value, ok := convertValue(values[field.arg], field.typ)
+		if !ok {
+			return fmt.Errorf("binutils: cannot set placeholder %s of type %s to %T", field.path, field.typ, values[field.arg])
+		}
+		stream.PutBytes(template.static[i])
+		if err := m.encode(value, field.tag, ""); err != nil {
+			return err
+		}
+	}
+	stream.PutBytes(template.static[len(template.fields)])
+	return stream.err
+}
@@ -0,0 +1,39 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	packet := testMarshalPacket{
+		Header:   testHeader{ID: 0x0102, Flags: 0x80},
+		Name:     "steve",
+		Position: [3]float32{1.5, -2, 64},
+		Payload:  []byte{0xde, 0xad},
+	}
+	template, err := NewTemplate(&packet, "Sequence", "Name", "Header.Version")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, template.Placeholders(), []string{"Sequence", "Name", "Header.Version"})
+
+	stream := NewStream()
+	assert.NilError(t, template.Fill(stream, 7, "alexander", -300))
+	packet.Sequence, packet.Name, packet.Header.Version = 7, "alexander", -300
+	expected, err := Marshal(packet)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stream.Buffer, expected)
+}
+
+func TestTemplateErrors(t *testing.T) {
+	_, err := NewTemplate(testMarshalPacket{}, "Header", "Header.ID")
+	assert.ErrorContains(t, err, "placeholders Header and Header.ID overlap")
+	_, err = NewTemplate(testMarshalPacket{}, "Missing")
+	assert.ErrorContains(t, err, "unknown field Missing")
+
+	template, err := NewTemplate(testMarshalPacket{}, "Name")
+	assert.NilError(t, err)
+	assert.ErrorContains(t, template.Fill(NewStream()), "template has 1 placeholders, got 0 values")
+	assert.ErrorContains(t, template.Fill(NewStream(), 1), "cannot set placeholder Name of type string to int")
+}
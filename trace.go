@@ -0,0 +1,72 @@
+package binutils
+
+// Tracer receives encode and decode events from a Stream.
+// It is intended to be implemented by a thin adapter around a tracing span,
+// for example an OpenTelemetry trace.Span:
+//
+//	type spanTracer struct{ span trace.Span }
+//
+//	func (t spanTracer) AddEvent(name string, attributes map[string]interface{}) {
+//		t.span.AddEvent(name, trace.WithAttributes(toAttributes(attributes)...))
+//	}
+//
+//	func (t spanTracer) RecordError(err error) {
+//		t.span.RecordError(err)
+//	}
+type Tracer interface {
+	AddEvent(name string, attributes map[string]interface{})
+	RecordError(err error)
+}
+
+// noopTrace is returned by the trace functions if the stream has no tracer.
+var noopTrace = func() {}
+
+// SetTracer sets the tracer the stream reports encode and decode events to.
+// Registries and state machines report the packets they encode and decode on the stream,
+// and other code reports with TraceDecode and TraceEncode. Passing nil disables tracing.
+func (stream *Stream) SetTracer(tracer Tracer) {
+	stream.tracer = tracer
+}
+
+// TraceDecode reports the start of decoding name at the current offset,
+// and returns a function that reports the end of it, which is typically deferred.
+// The end event carries the amount of bytes read, and any error set during decoding is recorded.
+func (stream *Stream) TraceDecode(name string) func() {
+	if stream.tracer == nil {
+		return noopTrace
+	}
+	return stream.trace("binutils.decode", name, func() int { return stream.Offset })
+}
+
+// TraceEncode reports the start of encoding name at the current buffer length,
+// and returns a function that reports the end of it, which is typically deferred.
+// The end event carries the amount of bytes written, and any error set during encoding is recorded.
+func (stream *Stream) TraceEncode(name string) func() {
+	if stream.tracer == nil {
+		return noopTrace
+	}
+	return stream.trace("binutils.encode", name, func() int { return len(stream.Buffer) })
+}
+
+// trace reports the start event of prefix and returns the function reporting its end.
+// The position function returns the position used to compute the amount of bytes processed.
+func (stream *Stream) trace(prefix string, name string, position func() int) func() {
+	tracer, start, hadError := stream.tracer, position(), stream.err != nil
+	tracer.AddEvent(prefix+".start", map[string]interface{}{
+		"name":   name,
+		"offset": start,
+	})
+
+	return func() {
+		attributes := map[string]interface{}{
+			"name":   name,
+			"offset": position(),
+			"bytes":  position() - start,
+		}
+		if !hadError && stream.err != nil {
+			attributes["error"] = true
+			tracer.RecordError(stream.err)
+		}
+		tracer.AddEvent(prefix+".end", attributes)
+	}
+}
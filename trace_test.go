@@ -0,0 +1,74 @@
+package binutils
+
+import (
+	"bytes"
+	"gotest.tools/assert"
+	"io"
+	"testing"
+)
+
+type testTracer struct {
+	events []string
+	attrs  []map[string]interface{}
+	errors []error
+}
+
+func (tracer *testTracer) AddEvent(name string, attributes map[string]interface{}) {
+	tracer.events = append(tracer.events, name)
+	tracer.attrs = append(tracer.attrs, attributes)
+}
+
+func (tracer *testTracer) RecordError(err error) {
+	tracer.errors = append(tracer.errors, err)
+}
+
+func TestTraceDecode(t *testing.T) {
+	tracer := &testTracer{}
+	stream := NewStream()
+	stream.SetBuffer(b(0x00, 0x01, 0x02))
+	stream.SetTracer(tracer)
+
+	end := stream.TraceDecode("Packet")
+	stream.GetShort()
+	stream.GetShort()
+	end()
+
+	assert.DeepEqual(t, tracer.events, []string{"binutils.decode.start", "binutils.decode.end"})
+	assert.Equal(t, tracer.attrs[1]["bytes"], 2)
+	assert.Equal(t, tracer.attrs[1]["error"], true)
+	assert.Equal(t, len(tracer.errors), 1)
+}
+
+func TestTraceEncode(t *testing.T) {
+	tracer := &testTracer{}
+	stream := NewStream()
+	stream.SetTracer(tracer)
+
+	end := stream.TraceEncode("Packet")
+	stream.PutInt(10)
+	end()
+
+	assert.Equal(t, tracer.attrs[1]["bytes"], 4)
+	assert.Equal(t, len(tracer.errors), 0)
+}
+
+func TestTraceFramer(t *testing.T) {
+	tracer := &testTracer{}
+	var written bytes.Buffer
+	framer := NewFramer(testConn{&written, &written}, PrefixUnsignedVarInt)
+	framer.SetTracer(tracer)
+	assert.NilError(t, framer.WriteFrame([]byte("steve")))
+	assert.Assert(t, framer.WriteFrame(make([]byte, DefaultMaxFrameSize+1)) != nil)
+	_, err := framer.ReadFrame()
+	assert.NilError(t, err)
+	_, err = framer.ReadFrame()
+	assert.Equal(t, err, io.EOF)
+
+	assert.DeepEqual(t, tracer.events, []string{"binutils.encode.start", "binutils.encode.end",
+		"binutils.encode.start", "binutils.encode.end", "binutils.decode.start", "binutils.decode.end"})
+	assert.Equal(t, tracer.attrs[1]["name"], "frame")
+	assert.Equal(t, tracer.attrs[1]["bytes"], 6)
+	assert.Equal(t, tracer.attrs[3]["error"], true)
+	assert.Equal(t, tracer.attrs[5]["bytes"], 5)
+	assert.Equal(t, len(tracer.errors), 1)
+}
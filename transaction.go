@@ -0,0 +1,53 @@
+package binutils
+
+import "errors"
+
+// errNoTransaction is returned by Commit and Rollback if no transaction is open.
+var errNoTransaction = errors.New("binutils: no transaction open")
+
+// transaction is a write transaction opened by Begin.
+type transaction struct {
+	length   int
+	err      error
+	sections int
+}
+
+// Begin opens a write transaction, remembering the length of the buffer and the error of the stream, so that
+// a packet whose encoding fails half way can be removed from the buffer again with Rollback, instead of leaving half
+// a frame in it. Transactions are nested: each Commit or Rollback closes the most recently opened transaction.
+func (stream *Stream) Begin() {
+	stream.transactions = append(stream.transactions, transaction{len(stream.Buffer), stream.err, len(stream.sections)})
+}
+
+// Commit closes the most recently opened transaction, keeping the bytes written in it.
+// It returns the error of the stream, so that a caller can decide to roll back the enclosing transaction.
+func (stream *Stream) Commit() error {
+	if len(stream.transactions) == 0 {
+		return errNoTransaction
+	}
+	stream.transactions = stream.transactions[:len(stream.transactions)-1]
+	return stream.err
+}
+
+// Rollback closes the most recently opened transaction, truncating the buffer to its length when the transaction
+// was opened, and restoring the error of the stream. Sections opened in the transaction are discarded.
+// Bytes written before the transaction, such as with SetBitsAt, are not restored.
+func (stream *Stream) Rollback() error {
+	if len(stream.transactions) == 0 {
+		return errNoTransaction
+	}
+	t := stream.transactions[len(stream.transactions)-1]
+	stream.transactions = stream.transactions[:len(stream.transactions)-1]
+	if t.length <= len(stream.Buffer) {
+		stream.Buffer = stream.Buffer[:t.length]
+	}
+	stream.Offset = min(stream.Offset, len(stream.Buffer))
+	stream.err = t.err
+	stream.sections = stream.sections[:min(t.sections, len(stream.sections))]
+	return nil
+}
+
+// Transactions returns the number of open transactions.
+func (stream *Stream) Transactions() int {
+	return len(stream.transactions)
+}
@@ -0,0 +1,33 @@
+package binutils
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestTransactions(t *testing.T) {
+	stream := NewStreamWithLimit(8)
+	stream.PutInt(1)
+
+	stream.Begin()
+	stream.PutShort(2)
+	stream.Begin()
+	stream.BeginSection(PrefixByte)
+	stream.PutLong(3)
+	assert.Assert(t, errors.Is(stream.Commit(), ErrLimitExceeded))
+	assert.Equal(t, stream.Transactions(), 1)
+	assert.NilError(t, stream.Rollback())
+
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, stream.Sections(), 0)
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 1))
+
+	stream.Begin()
+	stream.PutShort(4)
+	assert.NilError(t, stream.Commit())
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 1, 0, 4))
+
+	assert.Equal(t, stream.Commit(), errNoTransaction)
+	assert.Equal(t, stream.Rollback(), errNoTransaction)
+}
@@ -0,0 +1,79 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"reflect"
+	"sync"
+)
+
+// validator is a validation rule of a struct type.
+type validator struct {
+	rule     string
+	validate func(value reflect.Value) error
+}
+
+var (
+	validatorMutex sync.RWMutex
+	validators     = make(map[reflect.Type][]validator)
+)
+
+// RegisterValidator registers a validation rule for structs of type T, checking invariants across their fields,
+// such as a count matching the length of a slice. Rules run in the order registered, once all fields of a T are
+// decoded by Unmarshal or GetStruct, including Ts nested in other structs and slices. The first error returned is
+// set on the stream as *ValidationError with the name of the rule.
+func RegisterValidator[T any](rule string, validate func(v *T) error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	validatorMutex.Lock()
+	defer validatorMutex.Unlock()
+	validators[t] = append(validators[t], validator{rule, func(value reflect.Value) error {
+		return validate(value.Addr().Interface().(*T))
+	}})
+}
+
+// validate checks the struct decoded at offset with the rules registered for its type,
+// and sets the first error on the stream.
+func (m *marshaler) validate(value reflect.Value, offset int) {
+	if m.stream.err != nil {
+		return
+	}
+	validatorMutex.RLock()
+	rules := validators[value.Type()]
+	validatorMutex.RUnlock()
+	if len(rules) > 0 && !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+	for _, rule := range rules {
+		if err := rule.validate(value); err != nil {
+			m.stream.SetError(&StreamError{"Validate", offset, &ValidationError{rule.rule, err}})
+			return
+		}
+	}
+}
+
+// schemaValidator is a validation rule of a schema.
+type schemaValidator struct {
+	rule     string
+	validate func(values map[string]interface{}) error
+}
+
+// Validate adds a validation rule checking invariants across the fields of values decoded with the schema,
+// such as an end offset following a start offset. Rules run in the order added, once all fields are decoded by Get
+// or Decode. The first error returned is set on the stream as *ValidationError with the name of the rule.
+func (schema *Schema) Validate(rule string, validate func(values map[string]interface{}) error) *Schema {
+	schema.validators = append(schema.validators, schemaValidator{rule, validate})
+	return schema
+}
+
+// validate checks the values decoded at offset with the rules of the schema, and sets the first error on the stream.
+func (schema *Schema) validate(stream *Stream, offset int, values map[string]interface{}) bool {
+	for _, rule := range schema.validators {
+		if err := rule.validate(values); err != nil {
+			stream.SetError(&StreamError{"Validate", offset, &ValidationError{rule.rule, err}})
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+//go:build !tinygo && !binutils_minimal
+
+package binutils
+
+import (
+	"errors"
+	"fmt"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type testValidatedItem struct {
+	Start uint16
+	End   uint16
+}
+
+type testValidatedPacket struct {
+	Count byte
+	Items []testValidatedItem
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("count", func(packet *testValidatedPacket) error {
+		if int(packet.Count) != len(packet.Items) {
+			return fmt.Errorf("count %d does not match %d items", packet.Count, len(packet.Items))
+		}
+		return nil
+	})
+	RegisterValidator("range", func(item *testValidatedItem) error {
+		if item.End <= item.Start {
+			return errors.New("end before start")
+		}
+		return nil
+	})
+	var packet testValidatedPacket
+	buffer, err := Marshal(testValidatedPacket{1, []testValidatedItem{{1, 2}}})
+	assert.NilError(t, err)
+	assert.NilError(t, Unmarshal(buffer, &packet))
+
+	buffer, err = Marshal(testValidatedPacket{2, []testValidatedItem{{1, 2}}})
+	assert.NilError(t, err)
+	err = Unmarshal(buffer, &packet)
+	var validationErr *ValidationError
+	assert.Assert(t, errors.As(err, &validationErr))
+	assert.Equal(t, validationErr.Rule, "count")
+	assert.ErrorContains(t, err, "Validate at offset 0: validation count failed: count 2 does not match 1 items")
+
+	// Rules of nested structs run as they are decoded.
+	buffer, err = Marshal(testValidatedPacket{1, []testValidatedItem{{2, 2}}})
+	assert.NilError(t, err)
+	err = Unmarshal(buffer, &packet)
+	assert.Assert(t, errors.As(err, &validationErr))
+	assert.Equal(t, validationErr.Rule, "range")
+	assert.ErrorContains(t, err, "at offset 2")
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := NewSchema().Field("start", TypeUint32).Field("end", TypeUint32).Validate("range", func(values map[string]interface{}) error {
+		if values["end"].(uint64) <= values["start"].(uint64) {
+			return errors.New("end before start")
+		}
+		return nil
+	})
+	outer := NewSchema().Field("id", TypeUint8).Struct("range", schema)
+
+	buffer, err := outer.Encode(map[string]interface{}{"id": 1, "range": map[string]interface{}{"start": 1, "end": 2}})
+	assert.NilError(t, err)
+	_, err = outer.Decode(buffer)
+	assert.NilError(t, err)
+
+	buffer, err = outer.Encode(map[string]interface{}{"id": 1, "range": map[string]interface{}{"start": 2, "end": 1}})
+	assert.NilError(t, err)
+	_, err = outer.Decode(buffer)
+	var validationErr *ValidationError
+	assert.Assert(t, errors.As(err, &validationErr))
+	assert.Equal(t, validationErr.Rule, "range")
+	assert.ErrorContains(t, err, "at offset 1")
+}
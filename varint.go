@@ -0,0 +1,117 @@
+package binutils
+
+import "fmt"
+
+const (
+	// DefaultMaxVarIntBytes is the maximum encoded size of 32 bit varints, and the default limit of streams.
+	DefaultMaxVarIntBytes = 5
+	// DefaultMaxVarLongBytes is the maximum encoded size of 64 bit varints, and the default limit of streams.
+	DefaultMaxVarLongBytes = maxVarLongBytes
+)
+
+// uvarintSize returns the encoded size of an unsigned varint.
+func uvarintSize(v uint64) int {
+	n := 1
+	for ; v >= 0x80; v >>= 7 {
+		n++
+	}
+	return n
+}
+
+// SetMaxVarIntBytes sets the maximum encoded size of 32 bit varints read from the stream, for protocols capping
+// varints below their natural size, such as 3 byte packet lengths. Longer varints set ErrMalformedVarInt.
+// A size of 0 or less, or more than DefaultMaxVarIntBytes, uses DefaultMaxVarIntBytes.
+func (stream *Stream) SetMaxVarIntBytes(n int) {
+	stream.maxVarIntBytes = n
+}
+
+// GetMaxVarIntBytes returns the maximum encoded size of 32 bit varints read from the stream.
+func (stream *Stream) GetMaxVarIntBytes() int {
+	if stream.maxVarIntBytes <= 0 || stream.maxVarIntBytes > DefaultMaxVarIntBytes {
+		return DefaultMaxVarIntBytes
+	}
+	return stream.maxVarIntBytes
+}
+
+// SetMaxVarLongBytes sets the maximum encoded size of 64 bit varints read from the stream, like SetMaxVarIntBytes.
+// A size of 0 or less, or more than DefaultMaxVarLongBytes, uses DefaultMaxVarLongBytes.
+func (stream *Stream) SetMaxVarLongBytes(n int) {
+	stream.maxVarLongBytes = n
+}
+
+// GetMaxVarLongBytes returns the maximum encoded size of 64 bit varints read from the stream.
+func (stream *Stream) GetMaxVarLongBytes() int {
+	if stream.maxVarLongBytes <= 0 || stream.maxVarLongBytes > DefaultMaxVarLongBytes {
+		return DefaultMaxVarLongBytes
+	}
+	return stream.maxVarLongBytes
+}
+
+// VarIntPolicy decides which encodings of varints are accepted when reading them.
+type VarIntPolicy int
+
+const (
+	// VarIntMinecraft reads 32 bit varints of at most 5 bytes and 64 bit varints of at most 10 bytes,
+	// or the maximum sizes set on the stream, ignoring bits of the last byte beyond the size of the value
+	// like Minecraft: Java Edition. It is the default.
+	VarIntMinecraft VarIntPolicy = iota
+	// VarIntProtobuf reads every varint as 64 bit value of at most 10 bytes like Protocol Buffers, rejecting values
+	// overflowing 64 bits. 32 bit varints are truncated, so that negative values sign-extended to 10 bytes are read.
+	VarIntProtobuf
+	// VarIntUnlimited reads varints of any length, ignoring bits beyond 64 bits.
+	VarIntUnlimited
+)
+
+// SetVarIntPolicy sets the policy of the varints read from the stream. The maximum sizes set with SetMaxVarIntBytes
+// and SetMaxVarLongBytes only apply to VarIntMinecraft.
+func (stream *Stream) SetVarIntPolicy(policy VarIntPolicy) {
+	stream.varIntPolicy = policy
+}
+
+// GetVarIntPolicy returns the policy of the varints read from the stream.
+func (stream *Stream) GetVarIntPolicy() VarIntPolicy {
+	return stream.varIntPolicy
+}
+
+// getVarIntWithPolicy reads an unsigned 32 or 64 bit varint under the policy for the operation op.
+func (stream *Stream) getVarIntWithPolicy(op string, policy VarIntPolicy, long bool) uint64 {
+	switch policy {
+	case VarIntProtobuf:
+		offset := stream.Offset
+		v := stream.getUnsignedVarLong(op, maxVarLongBytes)
+		if stream.Offset-offset == maxVarLongBytes && stream.Buffer[stream.Offset-1] > 1 {
+			stream.SetError(&StreamError{op, offset, ErrMalformedVarInt})
+			return 0
+		}
+		return v
+	case VarIntUnlimited:
+		return stream.getUnsignedVarLong(op, 0)
+	case VarIntMinecraft:
+		if long {
+			return stream.getUnsignedVarLong(op, stream.GetMaxVarLongBytes())
+		}
+		return stream.getUnsignedVarLong(op, stream.GetMaxVarIntBytes())
+	}
+	stream.SetError(&StreamError{op, stream.Offset, fmt.Errorf("unknown varint policy %d", policy)})
+	return 0
+}
+
+// GetVarIntWithPolicy reads a zigzag encoded 32 bit varint under the policy, regardless of the policy of the stream.
+func (stream *Stream) GetVarIntWithPolicy(policy VarIntPolicy) int32 {
+	return fromZigZag32(uint32(stream.getVarIntWithPolicy("GetVarIntWithPolicy", policy, false)))
+}
+
+// GetVarLongWithPolicy reads a zigzag encoded 64 bit varint under the policy, regardless of the policy of the stream.
+func (stream *Stream) GetVarLongWithPolicy(policy VarIntPolicy) int64 {
+	return fromZigZag64(stream.getVarIntWithPolicy("GetVarLongWithPolicy", policy, true))
+}
+
+// GetUnsignedVarIntWithPolicy reads an unsigned 32 bit varint under the policy, regardless of the policy of the stream.
+func (stream *Stream) GetUnsignedVarIntWithPolicy(policy VarIntPolicy) uint32 {
+	return uint32(stream.getVarIntWithPolicy("GetUnsignedVarIntWithPolicy", policy, false))
+}
+
+// GetUnsignedVarLongWithPolicy reads an unsigned 64 bit varint under the policy, regardless of the policy of the stream.
+func (stream *Stream) GetUnsignedVarLongWithPolicy(policy VarIntPolicy) uint64 {
+	return stream.getVarIntWithPolicy("GetUnsignedVarLongWithPolicy", policy, true)
+}
@@ -0,0 +1,62 @@
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestMaxVarIntBytes(t *testing.T) {
+	stream := NewStream()
+	assert.Equal(t, stream.GetMaxVarIntBytes(), 5)
+	assert.Equal(t, stream.GetMaxVarLongBytes(), 10)
+	stream.SetMaxVarIntBytes(3)
+	stream.SetMaxVarLongBytes(3)
+	stream.PutUnsignedVarInt(1<<21 - 1)
+	stream.PutVarLong(-1 << 20)
+	stream.PutUnsignedVarInt(1 << 21)
+
+	assert.Equal(t, stream.GetUnsignedVarInt(), uint32(1<<21-1))
+	assert.Equal(t, stream.GetVarLong(), int64(-1<<20))
+	assert.Equal(t, stream.Reader().GetMaxVarIntBytes(), 3)
+	assert.Equal(t, stream.GetUnsignedVarInt(), uint32(0))
+	assert.Assert(t, errors.Is(stream.Err(), ErrMalformedVarInt))
+
+	stream.SetMaxVarIntBytes(6)
+	assert.Equal(t, stream.GetMaxVarIntBytes(), 5)
+}
+
+func TestVarIntPolicy(t *testing.T) {
+	// -1 as int32 encoded by Protocol Buffers, sign-extended to 10 bytes.
+	protobuf := b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01)
+	stream := &Stream{Buffer: protobuf}
+	assert.Equal(t, stream.GetVarIntPolicy(), VarIntMinecraft)
+	stream.GetUnsignedVarInt()
+	assert.Assert(t, errors.Is(stream.Err(), ErrMalformedVarInt))
+
+	stream = &Stream{Buffer: protobuf}
+	stream.SetVarIntPolicy(VarIntProtobuf)
+	assert.Equal(t, int32(stream.GetUnsignedVarInt()), int32(-1))
+	assert.Equal(t, stream.Reader().GetVarIntPolicy(), VarIntProtobuf)
+	assert.NilError(t, stream.Err())
+
+	stream = &Stream{Buffer: b(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02)}
+	stream.GetUnsignedVarLongWithPolicy(VarIntProtobuf)
+	assert.Assert(t, errors.Is(stream.Err(), ErrMalformedVarInt))
+
+	padded := append(bytes.Repeat(b(0x81), 11), 0x00)
+	stream = &Stream{Buffer: padded}
+	stream.SetMaxVarLongBytes(3)
+	v := stream.GetUnsignedVarLongWithPolicy(VarIntUnlimited)
+	assert.NilError(t, stream.Err())
+	assert.Equal(t, v&0x7f, uint64(1))
+	assert.Equal(t, stream.Offset, 12)
+
+	// The 5th byte of 32 bit varints carries bits beyond 32 bits, which Minecraft ignores.
+	stream = &Stream{Buffer: b(0xff, 0xff, 0xff, 0xff, 0x7f)}
+	assert.Equal(t, stream.GetVarIntWithPolicy(VarIntMinecraft), int32(math.MinInt32))
+	stream.GetVarLongWithPolicy(VarIntPolicy(7))
+	assert.ErrorContains(t, stream.Err(), "unknown varint policy 7")
+}
@@ -0,0 +1,163 @@
+package binutils
+
+// View reads and writes numbers at absolute offsets of a buffer in a byte order, without a cursor, like a JavaScript
+// DataView. It is used to patch headers in place once the rest of a packet is encoded, such as its length.
+// Accesses outside the buffer set ErrUnexpectedEOF on the view; like on streams, only the first error is kept,
+// and once an error is set reads return zero values and writes are discarded.
+type View struct {
+	Buffer []byte
+
+	endian   EndianType
+	readOnly bool
+	err      error
+}
+
+// NewView returns a new view of the buffer in the given byte order.
+func NewView(buffer []byte, endian EndianType) *View {
+	return &View{Buffer: buffer, endian: endian}
+}
+
+// View returns a view of the buffer of the stream in its byte order. The view writes into the buffer in place,
+// and sees bytes appended to the stream later only if it is taken again. A view of a frozen stream cannot be written to.
+func (stream *Stream) View() *View {
+	if stream.shared {
+		// Forked streams copy their buffer before it is written to in place.
+		stream.writable("View", 0)
+	}
+	return &View{Buffer: stream.Buffer, endian: stream.endian, readOnly: stream.frozen}
+}
+
+// Err returns the first error that occurred on the view, or nil.
+func (view *View) Err() error {
+	return view.err
+}
+
+// check checks if the operation op can access n bytes at offset.
+func (view *View) check(op string, offset, n int) bool {
+	if view.err != nil {
+		return false
+	}
+	if offset < 0 || offset > len(view.Buffer)-n {
+		view.err = &StreamError{op, offset, ErrUnexpectedEOF}
+		return false
+	}
+	return true
+}
+
+// putAt writes v at offset for the operation op.
+func putAt[T Number](view *View, op string, offset int, v T) {
+	if view.readOnly && view.err == nil {
+		view.err = &StreamError{op, offset, ErrFrozen}
+	}
+	if view.check(op, offset, sizeOf[T]()) {
+		putNumber(view.Buffer[offset:], byteOrder(view.endian), v)
+	}
+}
+
+// getAt reads a T at offset for the operation op.
+func getAt[T Number](view *View, op string, offset int) T {
+	if !view.check(op, offset, sizeOf[T]()) {
+		return 0
+	}
+	return number[T](view.Buffer[offset:], byteOrder(view.endian))
+}
+
+// Uint8At returns the byte at offset.
+func (view *View) Uint8At(offset int) uint8 {
+	return getAt[uint8](view, "Uint8At", offset)
+}
+
+// PutUint8At sets the byte at offset.
+func (view *View) PutUint8At(offset int, v uint8) {
+	putAt(view, "PutUint8At", offset, v)
+}
+
+// Int8At returns the signed byte at offset.
+func (view *View) Int8At(offset int) int8 {
+	return getAt[int8](view, "Int8At", offset)
+}
+
+// PutInt8At sets the signed byte at offset.
+func (view *View) PutInt8At(offset int, v int8) {
+	putAt(view, "PutInt8At", offset, v)
+}
+
+// Uint16At returns the uint16 at offset.
+func (view *View) Uint16At(offset int) uint16 {
+	return getAt[uint16](view, "Uint16At", offset)
+}
+
+// PutUint16At sets the uint16 at offset.
+func (view *View) PutUint16At(offset int, v uint16) {
+	putAt(view, "PutUint16At", offset, v)
+}
+
+// Int16At returns the int16 at offset.
+func (view *View) Int16At(offset int) int16 {
+	return getAt[int16](view, "Int16At", offset)
+}
+
+// PutInt16At sets the int16 at offset.
+func (view *View) PutInt16At(offset int, v int16) {
+	putAt(view, "PutInt16At", offset, v)
+}
+
+// Uint32At returns the uint32 at offset.
+func (view *View) Uint32At(offset int) uint32 {
+	return getAt[uint32](view, "Uint32At", offset)
+}
+
+// PutUint32At sets the uint32 at offset.
+func (view *View) PutUint32At(offset int, v uint32) {
+	putAt(view, "PutUint32At", offset, v)
+}
+
+// Int32At returns the int32 at offset.
+func (view *View) Int32At(offset int) int32 {
+	return getAt[int32](view, "Int32At", offset)
+}
+
+// PutInt32At sets the int32 at offset.
+func (view *View) PutInt32At(offset int, v int32) {
+	putAt(view, "PutInt32At", offset, v)
+}
+
+// Uint64At returns the uint64 at offset.
+func (view *View) Uint64At(offset int) uint64 {
+	return getAt[uint64](view, "Uint64At", offset)
+}
+
+// PutUint64At sets the uint64 at offset.
+func (view *View) PutUint64At(offset int, v uint64) {
+	putAt(view, "PutUint64At", offset, v)
+}
+
+// Int64At returns the int64 at offset.
+func (view *View) Int64At(offset int) int64 {
+	return getAt[int64](view, "Int64At", offset)
+}
+
+// PutInt64At sets the int64 at offset.
+func (view *View) PutInt64At(offset int, v int64) {
+	putAt(view, "PutInt64At", offset, v)
+}
+
+// Float32At returns the float32 at offset.
+func (view *View) Float32At(offset int) float32 {
+	return getAt[float32](view, "Float32At", offset)
+}
+
+// PutFloat32At sets the float32 at offset.
+func (view *View) PutFloat32At(offset int, v float32) {
+	putAt(view, "PutFloat32At", offset, v)
+}
+
+// Float64At returns the float64 at offset.
+func (view *View) Float64At(offset int) float64 {
+	return getAt[float64](view, "Float64At", offset)
+}
+
+// PutFloat64At sets the float64 at offset.
+func (view *View) PutFloat64At(offset int, v float64) {
+	putAt(view, "PutFloat64At", offset, v)
+}
@@ -0,0 +1,46 @@
+package binutils
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestView(t *testing.T) {
+	stream := NewStream()
+	stream.PutUnsignedInt(0)
+	stream.PutString("body")
+	view := stream.View()
+	view.PutUint32At(0, uint32(len(stream.Buffer)-4))
+	assert.DeepEqual(t, stream.Buffer[:4], b(0, 0, 0, 5))
+	assert.Equal(t, view.Uint32At(0), uint32(5))
+	assert.Equal(t, view.Uint8At(4), uint8(4))
+
+	view = NewView(make([]byte, 8), LittleEndian)
+	view.PutInt16At(6, -2)
+	view.PutFloat32At(0, 1)
+	assert.DeepEqual(t, view.Buffer, b(0, 0, 0x80, 0x3f, 0, 0, 0xfe, 0xff))
+	assert.Equal(t, view.Int16At(6), int16(-2))
+	assert.Equal(t, view.Float32At(0), float32(1))
+
+	assert.Equal(t, view.Uint64At(1), uint64(0))
+	assert.Assert(t, errors.Is(view.Err(), ErrUnexpectedEOF))
+	view.PutUint8At(0, 1)
+	assert.Equal(t, view.Buffer[0], byte(0))
+}
+
+func TestViewFrozen(t *testing.T) {
+	stream := NewStream()
+	stream.PutInt(1)
+	fork := stream.Fork()
+	fork.View().PutInt32At(0, 2)
+	assert.Equal(t, fork.GetInt(), int32(2))
+	assert.Equal(t, stream.GetInt(), int32(1))
+
+	view := stream.View()
+	view.PutInt32At(0, 3)
+	assert.Assert(t, errors.Is(view.Err(), ErrFrozen))
+	assert.Equal(t, view.Int32At(0), int32(0))
+	assert.DeepEqual(t, stream.Buffer, b(0, 0, 0, 1))
+}
@@ -0,0 +1,164 @@
+// Package wal implements a write-ahead log of checksummed records on binutils streams.
+//
+// Every record is framed as a little endian uint32 length of the payload, a little endian uint32 CRC-32
+// (Castagnoli) of the sequence number and payload, a little endian uint64 sequence number and the payload.
+// Reading recovers the log up to the first torn or corrupt record, such as a record partially written
+// before a crash.
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/irmine/binutils"
+)
+
+// headerSize is the size of the header of a record.
+const headerSize = 16
+
+// MaxRecordSize is the maximum payload size of a record.
+const MaxRecordSize = 64 << 20
+
+// ErrTornRecord is returned when the log ends within a record.
+var ErrTornRecord = errors.New("wal: torn record")
+
+// castagnoli is the CRC-32 table of records.
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// CorruptError is returned when a record is corrupt. It matches binutils.ErrChecksumMismatch with errors.Is
+// if the checksum of the record does not match.
+type CorruptError struct {
+	// Offset is the offset of the record in the log.
+	Offset int64
+	Err    error
+}
+
+func (err *CorruptError) Error() string {
+	return fmt.Sprintf("wal: corrupt record at offset %d: %v", err.Offset, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *CorruptError) Unwrap() error {
+	return err.Err
+}
+
+// Record is a record of the log.
+type Record struct {
+	Sequence uint64
+	Payload  []byte
+}
+
+// Writer appends records to a log.
+type Writer struct {
+	writer   io.Writer
+	sequence uint64
+}
+
+// NewWriter returns a writer appending records to the writer, starting with the given sequence number.
+func NewWriter(writer io.Writer, sequence uint64) *Writer {
+	return &Writer{writer, sequence}
+}
+
+// Sequence returns the sequence number of the next record.
+func (writer *Writer) Sequence() uint64 {
+	return writer.sequence
+}
+
+// Append appends a record with the payload in a single write, and returns its sequence number.
+func (writer *Writer) Append(payload []byte) (uint64, error) {
+	if len(payload) > MaxRecordSize {
+		return 0, &binutils.LengthError{Length: len(payload), Limit: MaxRecordSize}
+	}
+	stream := binutils.AcquireStream()
+	defer binutils.ReleaseStream(stream)
+	stream.PutLittleUnsignedLong(writer.sequence)
+	checksum := crc32.Update(crc32.Checksum(stream.Buffer, castagnoli), castagnoli, payload)
+	stream.Reset()
+	stream.PutLittleUnsignedInt(uint32(len(payload)))
+	stream.PutLittleUnsignedInt(checksum)
+	stream.PutLittleUnsignedLong(writer.sequence)
+	stream.PutBytes(payload)
+
+	if _, err := stream.WriteTo(writer.writer); err != nil {
+		return 0, err
+	}
+	writer.sequence++
+	return writer.sequence - 1, nil
+}
+
+// Reader reads records from a log.
+type Reader struct {
+	reader   io.Reader
+	offset   int64
+	sequence uint64
+	started  bool
+}
+
+// NewReader returns a reader reading records from the reader.
+func NewReader(reader io.Reader) *Reader {
+	return &Reader{reader: reader}
+}
+
+// Offset returns the offset of the end of the last valid record, which a torn or corrupt log can be truncated to.
+func (reader *Reader) Offset() int64 {
+	return reader.offset
+}
+
+// Next reads the next record. It returns io.EOF at the end of the log, ErrTornRecord if the log ends within
+// a record, and a *CorruptError if the record is corrupt or its sequence number does not follow the previous record.
+func (reader *Reader) Next() (Record, error) {
+	header := make([]byte, headerSize)
+	if n, err := io.ReadFull(reader.reader, header); err != nil {
+		if err == io.EOF && n == 0 {
+			return Record{}, io.EOF
+		}
+		if err == io.ErrUnexpectedEOF {
+			return Record{}, ErrTornRecord
+		}
+		return Record{}, err
+	}
+	stream := &binutils.Stream{Buffer: header}
+	length := stream.GetLittleUnsignedInt()
+	checksum := stream.GetLittleUnsignedInt()
+	sequence := stream.GetLittleUnsignedLong()
+	if length > MaxRecordSize {
+		return Record{}, &CorruptError{reader.offset, &binutils.LengthError{Length: int(length), Limit: MaxRecordSize}}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader.reader, payload); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Record{}, ErrTornRecord
+		}
+		return Record{}, err
+	}
+	if crc32.Update(crc32.Checksum(header[8:], castagnoli), castagnoli, payload) != checksum {
+		return Record{}, &CorruptError{reader.offset, binutils.ErrChecksumMismatch}
+	}
+	if reader.started && sequence != reader.sequence+1 {
+		return Record{}, &CorruptError{reader.offset, fmt.Errorf("sequence %d does not follow %d", sequence, reader.sequence)}
+	}
+	reader.offset += int64(headerSize + len(payload))
+	reader.sequence, reader.started = sequence, true
+	return Record{sequence, payload}, nil
+}
+
+// Recover reads all valid records of the log, stopping at the first torn or corrupt record.
+// It returns the records with the offset of the end of the last valid record, which the log can be truncated to
+// before appending to it. Torn and corrupt records are not reported as error, only errors of the reader are.
+func Recover(reader io.Reader) ([]Record, int64, error) {
+	logReader := NewReader(reader)
+	var records []Record
+	for {
+		record, err := logReader.Next()
+		var corrupt *CorruptError
+		if err == io.EOF || err == ErrTornRecord || errors.As(err, &corrupt) {
+			return records, logReader.Offset(), nil
+		}
+		if err != nil {
+			return records, logReader.Offset(), err
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,77 @@
+package wal
+
+import (
+	"bytes"
+	"errors"
+	"gotest.tools/assert"
+	"io"
+	"testing"
+
+	"github.com/irmine/binutils"
+)
+
+func writeLog(t *testing.T, payloads ...string) []byte {
+	var log bytes.Buffer
+	writer := NewWriter(&log, 1)
+	for i, payload := range payloads {
+		sequence, err := writer.Append([]byte(payload))
+		assert.NilError(t, err)
+		assert.Equal(t, sequence, uint64(i+1))
+	}
+	assert.Equal(t, writer.Sequence(), uint64(len(payloads)+1))
+	return log.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	log := writeLog(t, "first", "", "third")
+	reader := NewReader(bytes.NewReader(log))
+	for i, payload := range []string{"first", "", "third"} {
+		record, err := reader.Next()
+		assert.NilError(t, err)
+		assert.Equal(t, record.Sequence, uint64(i+1))
+		assert.Equal(t, string(record.Payload), payload)
+	}
+	_, err := reader.Next()
+	assert.Equal(t, err, io.EOF)
+	assert.Equal(t, reader.Offset(), int64(len(log)))
+}
+
+func TestRecoverTorn(t *testing.T) {
+	log := writeLog(t, "first", "second")
+	for _, size := range []int{len(log) - 1, len(log) - 7, 21 + 3} {
+		records, offset, err := Recover(bytes.NewReader(log[:size]))
+		assert.NilError(t, err)
+		assert.Equal(t, len(records), 1)
+		assert.Equal(t, offset, int64(21))
+	}
+	reader := NewReader(bytes.NewReader(log[:len(log)-1]))
+	reader.Next()
+	_, err := reader.Next()
+	assert.Equal(t, err, ErrTornRecord)
+}
+
+func TestRecoverCorrupt(t *testing.T) {
+	log := writeLog(t, "first", "second", "third")
+	log[21+headerSize] ^= 0xff
+	records, offset, err := Recover(bytes.NewReader(log))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, offset, int64(21))
+
+	reader := NewReader(bytes.NewReader(log))
+	reader.Next()
+	_, err = reader.Next()
+	assert.Assert(t, errors.Is(err, binutils.ErrChecksumMismatch))
+	assert.Error(t, err, "wal: corrupt record at offset 21: checksum mismatch")
+}
+
+func TestRecoverSequenceGap(t *testing.T) {
+	log := writeLog(t, "first")
+	var second bytes.Buffer
+	_, err := NewWriter(&second, 5).Append([]byte("second"))
+	assert.NilError(t, err)
+	records, offset, err := Recover(bytes.NewReader(append(log, second.Bytes()...)))
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, offset, int64(len(log)))
+}
@@ -0,0 +1,56 @@
+package binutils
+
+import (
+	"fmt"
+)
+
+// maxWarnings is the maximum amount of warnings kept by a stream, so that hostile input cannot grow them without bounds.
+const maxWarnings = 64
+
+// Warning is a non-fatal issue found while decoding, such as a non-canonical encoding or a deprecated field,
+// which lenient decoders can surface without aborting.
+type Warning struct {
+	Op     string
+	Offset int
+	Msg    string
+}
+
+func (warning Warning) String() string {
+	if warning.Op == "" {
+		return fmt.Sprintf("binutils: at offset %d: %s", warning.Offset, warning.Msg)
+	}
+	return fmt.Sprintf("binutils: %s at offset %d: %s", warning.Op, warning.Offset, warning.Msg)
+}
+
+// warnLogger is implemented by loggers supporting warnings, such as *slog.Logger.
+type warnLogger interface {
+	Warn(msg string, args ...interface{})
+}
+
+// Warn records a warning at the current offset of the stream. Unlike errors, warnings do not affect reading.
+// The warning is reported to the logger of the stream if it has a Warn method, like *slog.Logger.
+// Once 64 warnings have been recorded, further warnings are discarded.
+func (stream *Stream) Warn(msg string) {
+	stream.warn("", stream.Offset, msg)
+}
+
+// warn records a warning of the operation op at offset.
+func (stream *Stream) warn(op string, offset int, msg string) {
+	if len(stream.warnings) >= maxWarnings {
+		return
+	}
+	stream.warnings = append(stream.warnings, Warning{op, offset, msg})
+	if logger, ok := stream.logger.(warnLogger); ok {
+		logger.Warn("binutils: stream warning", "op", op, "offset", offset, "warning", msg)
+	}
+}
+
+// Warnings returns the warnings recorded on the stream, in the order they were recorded.
+func (stream *Stream) Warnings() []Warning {
+	return stream.warnings
+}
+
+// ClearWarnings removes the warnings recorded on the stream.
+func (stream *Stream) ClearWarnings() {
+	stream.warnings = nil
+}
@@ -0,0 +1,41 @@
+package binutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+type testWarnLogger struct {
+	warnings []string
+}
+
+func (logger *testWarnLogger) Error(msg string, args ...interface{}) {}
+
+func (logger *testWarnLogger) Warn(msg string, args ...interface{}) {
+	logger.warnings = append(logger.warnings, msg)
+}
+
+func TestWarnings(t *testing.T) {
+	logger := &testWarnLogger{}
+	stream := &Stream{Buffer: b(0x81, 0x00, 0x01, 0x80, 0x80, 0x00)}
+	stream.SetLogger(logger)
+	assert.Equal(t, stream.GetUnsignedVarInt(), uint32(1))
+	assert.Equal(t, stream.GetVarInt(), int32(-1))
+	stream.Warn("deprecated field")
+	assert.Equal(t, stream.GetUnsignedVarLong(), uint64(0))
+	assert.NilError(t, stream.Err())
+
+	assert.DeepEqual(t, stream.Warnings(), []Warning{
+		{"GetUnsignedVarInt", 0, "non-canonical varint"},
+		{"", 3, "deprecated field"},
+		{"GetUnsignedVarLong", 3, "non-canonical varint"},
+	})
+	assert.Equal(t, stream.Warnings()[0].String(), "binutils: GetUnsignedVarInt at offset 0: non-canonical varint")
+	assert.Equal(t, len(logger.warnings), 3)
+
+	stream.ClearWarnings()
+	for i := 0; i < 100; i++ {
+		stream.Warn("spam")
+	}
+	assert.Equal(t, len(stream.Warnings()), maxWarnings)
+}